@@ -759,6 +759,9 @@ func (a *Application) DefaultI18nLang() string {
 }
 
 func (a *Application) initI18n() error {
+	defaultLocale := a.Config().StringDefault("i18n.default", "en")
+	ahttp.SetDefaultLocale(ahttp.NewLocale(defaultLocale))
+
 	i18nPath := path.Join(a.VirtualBaseDir(), "i18n")
 	if !a.VFS().IsExists(i18nPath) {
 		// i18n directory not exists, scenario could be only API application
@@ -766,7 +769,7 @@ func (a *Application) initI18n() error {
 	}
 	ai18n := i18n.New(
 		a.Log(),
-		i18n.DefaultLocale(a.Config().StringDefault("i18n.default", "en")),
+		i18n.DefaultLocale(defaultLocale),
 		i18n.VFS(a.VFS()),
 		i18n.Dirs(i18nPath),
 	)
@@ -0,0 +1,309 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const queryTagName = "query"
+
+// ErrBindTargetInvalid returned when bind target is not a non-nil pointer
+// to struct.
+var ErrBindTargetInvalid = errors.New("ahttp: bind target must be a non-nil pointer to struct")
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// BindError represents the field-level error occurred while binding
+// the request value into struct field.
+type BindError struct {
+	Field string
+	Key   string
+	Value string
+	Err   error
+}
+
+// Error method is error interface implementation.
+func (e *BindError) Error() string {
+	return fmt.Sprintf("ahttp: unable to bind '%s' value '%s' into field '%s': %s",
+		e.Key, e.Value, e.Field, e.Err)
+}
+
+// BindErrors is list of field-level bind errors.
+type BindErrors []*BindError
+
+// Error method is error interface implementation.
+func (e BindErrors) Error() string {
+	var msgs []string
+	for _, be := range e {
+		msgs = append(msgs, be.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// MustBindError is the panic value of method `Request.MustBindQuery`, it
+// wraps the bind error, so that recover-based error middleware can identify
+// it and translate to `400 Bad Request` response.
+type MustBindError struct {
+	Err error
+}
+
+// Error method is error interface implementation.
+func (e *MustBindError) Error() string {
+	return fmt.Sprintf("ahttp: must bind failed: %s", e.Err)
+}
+
+// Unwrap method returns the underlying bind error.
+func (e *MustBindError) Unwrap() error {
+	return e.Err
+}
+
+// BindQuery method binds the URL query parameters into given struct pointer.
+// Field key is taken from struct tag `query` otherwise field name; tag value
+// `-` skips the field. It supports-
+//
+//  - Bracket notation for nested struct and map, e.g. `page[size]=20`,
+//    `filter[status]=active`
+//
+//  - Repeated keys into slice, e.g. `ids=1&ids=2` or `ids[]=1&ids[]=2`
+//
+//  - Types string, bool, int*, uint*, float*, time.Time (RFC3339) and
+//    pointer of these types
+//
+// 	For e.g.:
+// 		type Query struct {
+// 			Filter map[string]string `query:"filter"`
+// 			Sort   []string          `query:"sort"`
+// 			Page   struct {
+// 				Size   int `query:"size"`
+// 				Number int `query:"number"`
+// 			} `query:"page"`
+// 		}
+//
+// It returns `BindErrors` with field-level errors if any, invalid map entry
+// is not stored.
+func (r *Request) BindQuery(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrBindTargetInvalid
+	}
+
+	var errs BindErrors
+	bindQueryStruct(r.URL().Query(), "", "", rv.Elem(), &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// MustBindQuery method is same as method `BindQuery` except it panics with
+// `*MustBindError` on bind error. Use it only for non-critical paths, such
+// as prototypes and internal tools, along with recover-based error
+// middleware; otherwise use `BindQuery` and handle the error.
+//
+// 	For e.g.:
+// 		defer func() {
+// 			if r := recover(); r != nil {
+// 				if err, ok := r.(*ahttp.MustBindError); ok {
+// 					// reply 400 Bad Request
+// 				}
+// 			}
+// 		}()
+func (r *Request) MustBindQuery(v interface{}) {
+	if err := r.BindQuery(v); err != nil {
+		panic(&MustBindError{Err: err})
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func bindQueryStruct(params url.Values, prefix, fieldPrefix string, sv reflect.Value, errs *BindErrors) {
+	st := sv.Type()
+	for idx := 0; idx < st.NumField(); idx++ {
+		ft := st.Field(idx)
+		f := sv.Field(idx)
+		if !f.CanSet() {
+			continue
+		}
+
+		name := ft.Tag.Get(queryTagName)
+		if name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			name = ft.Name
+		}
+
+		key, fieldName := name, ft.Name
+		if len(prefix) > 0 {
+			key = prefix + "[" + name + "]"
+			fieldName = fieldPrefix + "." + ft.Name
+		}
+
+		bindQueryField(params, key, fieldName, f, errs)
+	}
+}
+
+func bindQueryField(params url.Values, key, fieldName string, f reflect.Value, errs *BindErrors) {
+	typ := f.Type()
+	if typ.Kind() == reflect.Ptr && typ != reflect.PtrTo(timeType) && typ.Elem().Kind() == reflect.Struct {
+		nv := reflect.New(typ.Elem())
+		if hasQueryPrefix(params, key+"[") {
+			bindQueryStruct(params, key, fieldName, nv.Elem(), errs)
+			f.Set(nv)
+		}
+		return
+	}
+
+	switch {
+	case typ.Kind() == reflect.Struct && typ != timeType:
+		bindQueryStruct(params, key, fieldName, f, errs)
+	case typ.Kind() == reflect.Map:
+		bindQueryMap(params, key, fieldName, f, errs)
+	case typ.Kind() == reflect.Slice:
+		values := params[key]
+		if len(values) == 0 {
+			values = params[key+"[]"]
+		}
+		if len(values) == 0 {
+			return
+		}
+		slice := reflect.MakeSlice(typ, len(values), len(values))
+		for i, value := range values {
+			if err := parseQueryValue(value, slice.Index(i)); err != nil {
+				*errs = append(*errs, &BindError{Field: fieldName, Key: key, Value: value, Err: err})
+				return
+			}
+		}
+		f.Set(slice)
+	default:
+		values, found := params[key]
+		if !found || len(values) == 0 {
+			return
+		}
+		if err := parseQueryValue(values[0], f); err != nil {
+			*errs = append(*errs, &BindError{Field: fieldName, Key: key, Value: values[0], Err: err})
+		}
+	}
+}
+
+func bindQueryMap(params url.Values, key, fieldName string, f reflect.Value, errs *BindErrors) {
+	typ := f.Type()
+	if typ.Key().Kind() != reflect.String {
+		return
+	}
+
+	prefix := key + "["
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	m := reflect.MakeMap(typ)
+	for _, k := range keys {
+		values := params[k]
+		if !strings.HasPrefix(k, prefix) || !strings.HasSuffix(k, "]") || len(values) == 0 {
+			continue
+		}
+
+		mk := k[len(prefix) : len(k)-1]
+		if len(mk) == 0 || strings.ContainsAny(mk, "[]") {
+			continue
+		}
+
+		ev := reflect.New(typ.Elem()).Elem()
+		if ev.Kind() == reflect.Slice {
+			ev = reflect.MakeSlice(typ.Elem(), len(values), len(values))
+			var err error
+			for i, value := range values {
+				if err = parseQueryValue(value, ev.Index(i)); err != nil {
+					*errs = append(*errs, &BindError{Field: fieldName, Key: k, Value: value, Err: err})
+					break
+				}
+			}
+			if err != nil {
+				continue
+			}
+		} else if err := parseQueryValue(values[0], ev); err != nil {
+			*errs = append(*errs, &BindError{Field: fieldName, Key: k, Value: values[0], Err: err})
+			continue
+		}
+		m.SetMapIndex(reflect.ValueOf(mk).Convert(typ.Key()), ev)
+	}
+
+	if m.Len() > 0 {
+		f.Set(m)
+	}
+}
+
+func parseQueryValue(value string, elem reflect.Value) error {
+	if elem.Kind() == reflect.Ptr {
+		nv := reflect.New(elem.Type().Elem())
+		if err := parseQueryValue(value, nv.Elem()); err != nil {
+			return err
+		}
+		elem.Set(nv)
+		return nil
+	}
+
+	if elem.Type() == timeType {
+		t, err := time.Parse(time.RFC3339, value)
+		if err == nil {
+			elem.Set(reflect.ValueOf(t))
+		}
+		return err
+	}
+
+	switch elem.Kind() {
+	case reflect.String:
+		elem.SetString(value)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		elem.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(value, 10, elem.Type().Bits())
+		if err != nil {
+			return err
+		}
+		elem.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(value, 10, elem.Type().Bits())
+		if err != nil {
+			return err
+		}
+		elem.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(value, elem.Type().Bits())
+		if err != nil {
+			return err
+		}
+		elem.SetFloat(v)
+	default:
+		return fmt.Errorf("unsupported type '%s'", elem.Type())
+	}
+	return nil
+}
+
+func hasQueryPrefix(params url.Values, prefix string) bool {
+	for k := range params {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
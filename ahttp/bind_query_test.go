@@ -0,0 +1,143 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type queryPage struct {
+	Size   int `query:"size"`
+	Number int `query:"number"`
+}
+
+type queryParams struct {
+	Filter  map[string]string   `query:"filter"`
+	Tags    map[string][]int    `query:"tags"`
+	Sort    []string            `query:"sort"`
+	IDs     []int64             `query:"ids"`
+	Page    queryPage           `query:"page"`
+	Cursor  *queryPage          `query:"cursor"`
+	Limit   *uint               `query:"limit"`
+	Since   time.Time           `query:"since"`
+	Draft   bool                `query:"draft"`
+	Score   float64             `query:"score"`
+	Include string              `query:"page[include]"`
+	Skipped string              `query:"-"`
+	Unset   map[string]struct{} `query:"unset"`
+	Name    string
+	private string
+}
+
+func TestHTTPRequestBindQuery(t *testing.T) {
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/articles?"+
+		"filter[status]=active&filter[author]=jeeva&sort=-created&sort=title&"+
+		"ids[]=10&ids[]=20&page[size]=20&page[number]=3&page[include]=author&"+
+		"tags[go]=1&tags[go]=2&cursor[size]=5&limit=100&since=2018-06-15T10:00:00Z&"+
+		"draft=true&score=4.5&Skipped=yes&Name=aah&private=yes", nil)
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	var q queryParams
+	err := aahReq.BindQuery(&q)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"status": "active", "author": "jeeva"}, q.Filter)
+	assert.Equal(t, map[string][]int{"go": {1, 2}}, q.Tags)
+	assert.Equal(t, []string{"-created", "title"}, q.Sort)
+	assert.Equal(t, []int64{10, 20}, q.IDs)
+	assert.Equal(t, queryPage{Size: 20, Number: 3}, q.Page)
+	assert.Equal(t, &queryPage{Size: 5}, q.Cursor)
+	assert.Equal(t, uint(100), *q.Limit)
+	assert.Equal(t, time.Date(2018, 6, 15, 10, 0, 0, 0, time.UTC), q.Since)
+	assert.True(t, q.Draft)
+	assert.Equal(t, 4.5, q.Score)
+	assert.Equal(t, "author", q.Include)
+	assert.Equal(t, "", q.Skipped)
+	assert.Nil(t, q.Unset)
+	assert.Equal(t, "aah", q.Name)
+	assert.Equal(t, "", q.private)
+}
+
+func TestHTTPRequestBindQueryErrors(t *testing.T) {
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/articles?"+
+		"ids=10&ids=abc&page[size]=big&tags[go]=x&draft=maybe", nil)
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	var q queryParams
+	err := aahReq.BindQuery(&q)
+	assert.NotNil(t, err)
+
+	errs, ok := err.(BindErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 4, len(errs))
+
+	fields := map[string]*BindError{}
+	for _, e := range errs {
+		fields[e.Field] = e
+	}
+	assert.Equal(t, "ids", fields["IDs"].Key)
+	assert.Equal(t, "abc", fields["IDs"].Value)
+	assert.Equal(t, "page[size]", fields["Page.Size"].Key)
+	assert.Equal(t, "tags[go]", fields["Tags"].Key)
+	assert.Equal(t, "ahttp: unable to bind 'draft' value 'maybe' into field 'Draft': strconv.ParseBool: parsing \"maybe\": invalid syntax",
+		fields["Draft"].Error())
+
+	assert.Equal(t, ErrBindTargetInvalid, aahReq.BindQuery(q))
+	assert.Equal(t, ErrBindTargetInvalid, aahReq.BindQuery((*queryParams)(nil)))
+}
+
+func TestHTTPRequestBindQueryMapErrors(t *testing.T) {
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/articles?"+
+		"tags[rust]=3&tags[go]=1&tags[go]=x&tags[aah]=y", nil)
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	var q queryParams
+	err := aahReq.BindQuery(&q)
+	errs, ok := err.(BindErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, "tags[aah]", errs[0].Key)
+	assert.Equal(t, "tags[go]", errs[1].Key)
+	assert.Equal(t, "x", errs[1].Value)
+
+	// invalid entries are not stored partially
+	assert.Equal(t, map[string][]int{"rust": {3}}, q.Tags)
+}
+
+func TestHTTPRequestMustBindQuery(t *testing.T) {
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/articles?ids=10&ids=20&draft=true", nil)
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	var q queryParams
+	aahReq.MustBindQuery(&q)
+	assert.Equal(t, []int64{10, 20}, q.IDs)
+	assert.True(t, q.Draft)
+
+	// panics with typed error
+	badReq := AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080/articles?draft=maybe", nil))
+	defer ReleaseRequest(badReq)
+
+	mustBindPanic := func(v interface{}) (recovered interface{}) {
+		defer func() { recovered = recover() }()
+		badReq.MustBindQuery(v)
+		return
+	}
+
+	err, ok := mustBindPanic(&q).(*MustBindError)
+	assert.True(t, ok)
+	_, ok = err.Err.(BindErrors)
+	assert.True(t, ok)
+	assert.Equal(t, "ahttp: must bind failed: ahttp: unable to bind 'draft' value 'maybe' into field 'Draft': strconv.ParseBool: parsing \"maybe\": invalid syntax",
+		err.Error())
+
+	assert.Equal(t, &MustBindError{Err: ErrBindTargetInvalid}, mustBindPanic(q))
+}
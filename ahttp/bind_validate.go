@@ -0,0 +1,109 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"gopkg.in/go-playground/validator.v9"
+)
+
+var (
+	validatorMu     = &sync.RWMutex{}
+	structValidator = validator.New()
+)
+
+// Validatable interface is implemented by bind target struct to perform
+// the validation beyond struct tag `validate`, e.g. cross-field checks.
+// Refer to method `Request.BindAndValidate`.
+type Validatable interface {
+	Validate() error
+}
+
+// SetValidator method sets the struct validator used by method
+// `Request.BindAndValidate`, default is `validator.New()`. aah application
+// sets the validator of `valpar.Validator`, so that custom validations
+// registered there are honored.
+func SetValidator(v *validator.Validate) {
+	if v == nil {
+		return
+	}
+	validatorMu.Lock()
+	structValidator = v
+	validatorMu.Unlock()
+}
+
+// BindAndValidate method binds the request into given struct pointer and
+// validates it. Source is chosen by request content type-
+//
+//  - JSON, decodes the request body, body limit error `ErrBodyTooLarge`
+//    is returned as-is
+//
+//  - Form (urlencoded or multipart), binds form values including URL query
+//    params, same as method `BindQuery` mapping via struct tag `query`
+//
+//  - Otherwise, binds URL query params via method `BindQuery`
+//
+// Then struct is validated by the validator (refer to `ahttp.SetValidator`)
+// using struct tag `validate`, rules are of `go-playground/validator.v9`. It
+// returns `validator.ValidationErrors` listing each invalid field. Finally
+// method `Validate` is called if the struct implements `Validatable` and tag
+// validation passed.
+//
+// 	For e.g.:
+// 		type User struct {
+// 			Name  string `json:"name" validate:"required,min=3"`
+// 			Email string `json:"email" validate:"required,email"`
+// 			Role  string `json:"role" validate:"omitempty,oneof=admin user"`
+// 		}
+//
+// 		var user User
+// 		if err := req.BindAndValidate(&user); err != nil {
+// 			// reply 400 Bad Request
+// 		}
+func (r *Request) BindAndValidate(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrBindTargetInvalid
+	}
+
+	switch r.ContentType().Mime {
+	case ContentTypeJSON.Mime, ContentTypeJSONText.Mime:
+		if body := r.Body(); body != nil {
+			if err := json.NewDecoder(body).Decode(v); err != nil && err != io.EOF {
+				if err == ErrBodyTooLarge {
+					return err
+				}
+				return fmt.Errorf("ahttp: unable to decode JSON body: %s", err)
+			}
+		}
+	case ContentTypeForm.Mime, ContentTypeMultipartForm.Mime:
+		var errs BindErrors
+		bindQueryStruct(r.FormValues(), "", "", rv.Elem(), &errs)
+		if len(errs) > 0 {
+			return errs
+		}
+	default:
+		if err := r.BindQuery(v); err != nil {
+			return err
+		}
+	}
+
+	validatorMu.RLock()
+	sv := structValidator
+	validatorMu.RUnlock()
+	if err := sv.Struct(v); err != nil {
+		return err
+	}
+
+	if vv, ok := v.(Validatable); ok {
+		return vv.Validate()
+	}
+	return nil
+}
@@ -0,0 +1,179 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/go-playground/validator.v9"
+)
+
+type validateAddress struct {
+	City string `json:"city" query:"city" validate:"required"`
+	Zip  string `json:"zip" query:"zip" validate:"omitempty,len=5"`
+}
+
+type validateUser struct {
+	Name     string           `json:"name" query:"name" validate:"required,min=3,max=20"`
+	Email    string           `json:"email" query:"email" validate:"required,email"`
+	Role     string           `json:"role" query:"role" validate:"omitempty,oneof=admin user"`
+	Age      int              `json:"age" query:"age" validate:"omitempty,gte=18"`
+	Tags     []string         `json:"tags" query:"tags" validate:"max=2"`
+	Address  *validateAddress `json:"address" query:"address"`
+	Password string           `json:"password" query:"password"`
+	Confirm  string           `json:"confirm" query:"confirm"`
+}
+
+func (u *validateUser) Validate() error {
+	if u.Password != u.Confirm {
+		return errors.New("password mismatch")
+	}
+	return nil
+}
+
+func TestHTTPRequestBindAndValidate(t *testing.T) {
+	testcases := []struct {
+		label       string
+		method      string
+		target      string
+		contentType string
+		body        string
+		errMsg      string
+		invalid     []string
+		user        validateUser
+	}{
+		{
+			label:       "valid json",
+			method:      MethodPost,
+			target:      "/users",
+			contentType: ContentTypeJSON.String(),
+			body:        `{"name":"jeeva","email":"jeeva@example.com","role":"admin","age":30,"tags":["go"],"address":{"city":"Chennai","zip":"60001"}}`,
+			user: validateUser{Name: "jeeva", Email: "jeeva@example.com", Role: "admin", Age: 30,
+				Tags: []string{"go"}, Address: &validateAddress{City: "Chennai", Zip: "60001"}},
+		},
+		{
+			label:       "valid form",
+			method:      MethodPost,
+			target:      "/users?role=user",
+			contentType: ContentTypeForm.String(),
+			body:        "name=jeeva&email=jeeva%40example.com&age=18",
+			user:        validateUser{Name: "jeeva", Email: "jeeva@example.com", Role: "user", Age: 18},
+		},
+		{
+			label:  "valid query",
+			method: MethodGet,
+			target: "/users?name=jeeva&email=jeeva@example.com&address[city]=Chennai",
+			user:   validateUser{Name: "jeeva", Email: "jeeva@example.com", Address: &validateAddress{City: "Chennai"}},
+		},
+		{
+			label:       "multiple invalid fields",
+			method:      MethodPost,
+			target:      "/users",
+			contentType: ContentTypeJSON.String(),
+			body:        `{"name":"je","email":"not-an-email","role":"guest","age":12,"tags":["a","b","c"],"address":{"zip":"123"}}`,
+			invalid: []string{"validateUser.Name min", "validateUser.Email email", "validateUser.Role oneof",
+				"validateUser.Age gte", "validateUser.Tags max", "validateUser.Address.City required",
+				"validateUser.Address.Zip len"},
+		},
+		{
+			label:       "empty body",
+			method:      MethodPost,
+			target:      "/users",
+			contentType: ContentTypeJSON.String(),
+			invalid:     []string{"validateUser.Name required", "validateUser.Email required"},
+		},
+		{
+			label:       "malformed json",
+			method:      MethodPost,
+			target:      "/users",
+			contentType: ContentTypeJSON.String(),
+			body:        `<html>`,
+			errMsg:      "ahttp: unable to decode JSON body: invalid character '<' looking for beginning of value",
+		},
+		{
+			label:  "bind error",
+			method: MethodGet,
+			target: "/users?age=ten",
+			errMsg: `ahttp: unable to bind 'age' value 'ten' into field 'Age': strconv.ParseInt: parsing "ten": invalid syntax`,
+		},
+		{
+			label:       "validatable",
+			method:      MethodPost,
+			target:      "/users",
+			contentType: ContentTypeJSON.String(),
+			body:        `{"name":"jeeva","email":"jeeva@example.com","password":"secret","confirm":"secrets"}`,
+			errMsg:      "password mismatch",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "http://localhost:8080"+tc.target, strings.NewReader(tc.body))
+			if len(tc.contentType) > 0 {
+				req.Header.Set(HeaderContentType, tc.contentType)
+			}
+			aahReq := AcquireRequest(req)
+			defer ReleaseRequest(aahReq)
+
+			var user validateUser
+			err := aahReq.BindAndValidate(&user)
+			if len(tc.invalid) > 0 {
+				verrs, ok := err.(validator.ValidationErrors)
+				assert.True(t, ok)
+				var invalid []string
+				for _, fe := range verrs {
+					invalid = append(invalid, fe.Namespace()+" "+fe.Tag())
+				}
+				assert.Equal(t, tc.invalid, invalid)
+				return
+			}
+			if len(tc.errMsg) > 0 {
+				assert.NotNil(t, err)
+				assert.Equal(t, tc.errMsg, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.user, user)
+		})
+	}
+
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080/users", nil))
+	defer ReleaseRequest(aahReq)
+	var user validateUser
+	assert.Equal(t, ErrBindTargetInvalid, aahReq.BindAndValidate(user))
+
+	// body limit error is returned as-is
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/users", strings.NewReader(`{"name":"jeeva"}`))
+	req.Header.Set(HeaderContentType, ContentTypeJSON.String())
+	limitReq := AcquireRequest(req)
+	defer ReleaseRequest(limitReq)
+	limitReq.LimitBody(nil, 5)
+	assert.Equal(t, ErrBodyTooLarge, limitReq.BindAndValidate(&user))
+
+	// custom validator
+	v := validator.New()
+	assert.Nil(t, v.RegisterValidation("username", func(fl validator.FieldLevel) bool {
+		return !strings.ContainsAny(fl.Field().String(), " @")
+	}))
+	SetValidator(v)
+	defer SetValidator(validator.New())
+	SetValidator(nil) // ignored
+
+	var account struct {
+		Login string `query:"login" validate:"required,username"`
+	}
+	aahReq = AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080/accounts?login=jeeva@aah", nil))
+	defer ReleaseRequest(aahReq)
+	err := aahReq.BindAndValidate(&account)
+	verrs, ok := err.(validator.ValidationErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 1, len(verrs))
+	assert.Equal(t, "Login", verrs[0].Field())
+	assert.Equal(t, "username", verrs[0].Tag())
+}
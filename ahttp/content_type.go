@@ -110,9 +110,25 @@ func (c *ContentType) Vendor() string {
 //
 // 		Method returns `2` for key `version`
 func (c *ContentType) GetParam(key string) string {
-	if v, found := c.Params[key]; found {
+	return c.Param(key)
+}
+
+// Param method returns the media type parameter value for given name,
+// parameter name is matched case-insensitive per RFC 7231. Otherwise returns
+// empty string. Use field `Params` for all the parsed parameters.
+// 	For e.g.:
+// 		Content-Type: multipart/form-data; boundary=----aah
+//
+// 		Method returns `----aah` for name `Boundary`
+func (c *ContentType) Param(name string) string {
+	if v, found := c.Params[name]; found {
 		return v
 	}
+	for k, v := range c.Params {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
 	return ""
 }
 
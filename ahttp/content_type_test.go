@@ -105,3 +105,23 @@ func TestHTTPParseContentType(t *testing.T) {
 	assert.True(t, (contentType.Raw() == "" || contentType.String() == "text/html"))
 	assert.Equal(t, "iso-8859-1", contentType.Charset("iso-8859-1"))
 }
+
+func TestHTTPContentTypeParam(t *testing.T) {
+	req := createRawHTTPRequest(HeaderContentType, "multipart/form-data; Boundary=----WebKitFormBoundary7MA4YWxk")
+	contentType := ParseContentType(req)
+	assert.Equal(t, "multipart/form-data", contentType.Mime)
+	assert.Equal(t, "----WebKitFormBoundary7MA4YWxk", contentType.Param("boundary"))
+	assert.Equal(t, "----WebKitFormBoundary7MA4YWxk", contentType.Param("BOUNDARY"))
+	assert.Equal(t, "", contentType.Param("charset"))
+
+	req = createRawHTTPRequest(HeaderContentType, "application/vnd.aah.order+json; charset=UTF-8; Version=2")
+	contentType = ParseContentType(req)
+	assert.Equal(t, "application/vnd.aah.order+json", contentType.Mime)
+	assert.Equal(t, "2", contentType.Param("version"))
+	assert.Equal(t, "2", contentType.GetParam("VERSION"))
+	assert.Equal(t, "UTF-8", contentType.Param("Charset"))
+	assert.Equal(t, map[string]string{"charset": "UTF-8", "version": "2"}, contentType.Params)
+
+	contentType = newContentType("application/json", nil, map[string]string{"Version": "3"})
+	assert.Equal(t, "3", contentType.Param("version"))
+}
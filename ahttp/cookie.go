@@ -0,0 +1,73 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+const signedCookieSeparator = "."
+
+// ErrCookieSignatureInvalid returned when signed cookie value is malformed or
+// its signature doesn't match, i.e. cookie value has been tampered.
+var ErrCookieSignatureInvalid = errors.New("ahttp: cookie signature is invalid")
+
+// SignCookieValue method returns the HMAC-SHA256 signed value for the given
+// cookie name and value using given key. Cookie name is part of the signature,
+// so signed value cannot be reused for another cookie.
+//
+// Signed value format is-
+//
+// 	base64url(value) + "." + base64url(HMAC-SHA256(key, name + "|" + base64url(value)))
+//
+// 	For e.g.:
+// 		http.SetCookie(w, &http.Cookie{
+// 			Name:  "prefs",
+// 			Value: ahttp.SignCookieValue("prefs", "theme=dark", key),
+// 		})
+//
+// Use method `Request.SignedCookie` to read and verify the value.
+func SignCookieValue(name, value string, key []byte) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(value))
+	return encoded + signedCookieSeparator +
+		base64.RawURLEncoding.EncodeToString(signCookie(name, encoded, key))
+}
+
+// VerifyCookieValue method verifies the signed value produced by method
+// `SignCookieValue` for the given cookie name and returns original value.
+// Otherwise it returns `ErrCookieSignatureInvalid`.
+func VerifyCookieValue(name, signedValue string, key []byte) (string, error) {
+	idx := strings.LastIndex(signedValue, signedCookieSeparator)
+	if idx <= 0 {
+		return "", ErrCookieSignatureInvalid
+	}
+
+	encoded := signedValue[:idx]
+	sig, err := base64.RawURLEncoding.DecodeString(signedValue[idx+1:])
+	if err != nil {
+		return "", ErrCookieSignatureInvalid
+	}
+
+	if !hmac.Equal(sig, signCookie(name, encoded, key)) {
+		return "", ErrCookieSignatureInvalid
+	}
+
+	value, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrCookieSignatureInvalid
+	}
+
+	return string(value), nil
+}
+
+func signCookie(name, encoded string, key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(name + "|" + encoded))
+	return mac.Sum(nil)
+}
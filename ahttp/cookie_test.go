@@ -0,0 +1,47 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPSignedCookie(t *testing.T) {
+	key := []byte("5a977494319cde3203fbb49711f08ad2")
+	signed := SignCookieValue("prefs", "theme=dark; lang=en", key)
+
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/index.html", nil)
+	req.AddCookie(&http.Cookie{Name: "prefs", Value: signed})
+	req.AddCookie(&http.Cookie{Name: "other", Value: signed})
+	req.AddCookie(&http.Cookie{Name: "tampered", Value: "dGhlbWU9bGlnaHQ" + signed[len("dGhlbWU9ZGFyazsgbGFuZz1lbg"):]})
+	req.AddCookie(&http.Cookie{Name: "malformed", Value: "nosignature"})
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	value, err := aahReq.SignedCookie("prefs", key)
+	assert.Nil(t, err)
+	assert.Equal(t, "theme=dark; lang=en", value)
+
+	// wrong key
+	_, err = aahReq.SignedCookie("prefs", []byte("wrongkey"))
+	assert.Equal(t, ErrCookieSignatureInvalid, err)
+
+	// signed value copied into another cookie
+	_, err = aahReq.SignedCookie("other", key)
+	assert.Equal(t, ErrCookieSignatureInvalid, err)
+
+	_, err = aahReq.SignedCookie("tampered", key)
+	assert.Equal(t, ErrCookieSignatureInvalid, err)
+
+	_, err = aahReq.SignedCookie("malformed", key)
+	assert.Equal(t, ErrCookieSignatureInvalid, err)
+
+	_, err = aahReq.SignedCookie("notexists", key)
+	assert.Equal(t, http.ErrNoCookie, err)
+}
@@ -11,6 +11,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 
 	"aahframe.work/essentials"
 	"aahframe.work/log"
@@ -18,6 +19,11 @@ import (
 
 const vendorTreePrefix = "vnd."
 
+var (
+	localeMu      = &sync.RWMutex{}
+	defaultLocale = NewLocale("en")
+)
+
 // HTTP Header names
 const (
 	HeaderAccept                          = "Accept"
@@ -40,12 +46,14 @@ const (
 	HeaderContentDisposition              = "Content-Disposition"
 	HeaderContentEncoding                 = "Content-Encoding"
 	HeaderContentLength                   = "Content-Length"
+	HeaderContentRange                    = "Content-Range"
 	HeaderContentType                     = "Content-Type"
 	HeaderContentSecurityPolicy           = "Content-Security-Policy"
 	HeaderContentSecurityPolicyReportOnly = "Content-Security-Policy-Report-Only"
 	HeaderCookie                          = "Cookie"
 	HeaderDate                            = "Date"
 	HeaderETag                            = "Etag"
+	HeaderExpect                          = "Expect"
 	HeaderExpires                         = "Expires"
 	HeaderHost                            = "Host"
 	HeaderIfMatch                         = "If-Match"
@@ -151,9 +159,40 @@ func NegotiateContentType(req *http.Request) *ContentType {
 }
 
 // NegotiateLocale method negotiates the `Accept-Language` from the given HTTP
-// request. Most quailfied one based on quality factor.
+// request. Most quailfied one based on quality factor. Malformed entries
+// (invalid language tag, garbage or out of range quality factor) and
+// wildcard `*` are skipped while honoring valid ones in the same header.
+// It returns nil if no valid entry found.
 func NegotiateLocale(req *http.Request) *Locale {
-	return ToLocale(ParseAccept(req, HeaderAcceptLanguage).MostQualified())
+	for _, spec := range ParseAccept(req, HeaderAcceptLanguage) {
+		spec.Value = strings.TrimSpace(spec.Value)
+		if spec.Q <= 0 || spec.Q > 1 || !isLanguageTag(spec.Value) {
+			continue
+		}
+		return ToLocale(&spec)
+	}
+	return nil
+}
+
+// SetDefaultLocale method sets the default locale used by method
+// `Request.Locale` when `Accept-Language` header is missing or malformed.
+// Nil resets it to `en`, which is the default.
+func SetDefaultLocale(locale *Locale) {
+	if locale == nil {
+		locale = NewLocale("en")
+	}
+	localeMu.Lock()
+	defaultLocale = locale
+	localeMu.Unlock()
+}
+
+// DefaultLocale method returns the copy of default locale, refer to
+// `ahttp.SetDefaultLocale`.
+func DefaultLocale() *Locale {
+	localeMu.RLock()
+	defer localeMu.RUnlock()
+	l := *defaultLocale
+	return &l
 }
 
 // NegotiateEncoding negotiates the `Accept-Encoding` from the given HTTP
@@ -308,6 +347,39 @@ func (specs AcceptSpecs) MostQualified() *AcceptSpec {
 	return nil
 }
 
+// IsAccepted method returns true if the given content-coding is acceptable
+// as per RFC7231 https://tools.ietf.org/html/rfc7231#section-5.3.4, otherwise
+// false. Coding is compared case-insensitively and quality factor `0`
+// means "not acceptable".
+//
+// The resolve order is-
+//
+// 1) Explicit entry for the coding, e.g. `gzip;q=0` is rejection
+//
+// 2) Wildcard `*`, which matches any coding not listed in the header
+//
+// 3) Coding is acceptable if `identity` is rejected via `identity;q=0`,
+// since the client then expects some coding other than identity
+func (specs AcceptSpecs) IsAccepted(coding string) bool {
+	var wildcard, identity *AcceptSpec
+	for i := range specs {
+		spec := &specs[i]
+		value := strings.TrimSpace(spec.Value)
+		switch {
+		case strings.EqualFold(value, coding):
+			return spec.Q > 0
+		case value == "*":
+			wildcard = spec
+		case strings.EqualFold(value, "identity"):
+			identity = spec
+		}
+	}
+	if wildcard != nil {
+		return wildcard.Q > 0
+	}
+	return identity != nil && identity.Q <= 0
+}
+
 // sort.Interface methods for accept spec
 func (specs AcceptSpecs) Len() int           { return len(specs) }
 func (specs AcceptSpecs) Swap(i, j int)      { specs[i], specs[j] = specs[j], specs[i] }
@@ -317,6 +389,26 @@ func (specs AcceptSpecs) Less(i, j int) bool { return specs[i].Q > specs[j].Q }
 // Unexported methods
 //___________________________________
 
+// isLanguageTag method reports whether given value is well-formed language
+// tag per RFC5646 syntax `1*8ALPHA *("-" 1*8alphanum)`.
+func isLanguageTag(value string) bool {
+	if len(value) == 0 {
+		return false
+	}
+	for i, sub := range strings.Split(value, "-") {
+		if len(sub) == 0 || len(sub) > 8 {
+			return false
+		}
+		for _, c := range sub {
+			isAlpha := (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+			if !isAlpha && (i == 0 || c < '0' || c > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // isVendorType method check the mime type is vendor type as per
 // RFC4288 https://tools.ietf.org/html/rfc4288#section-3.2 - Vendor Tree
 // i.e. `vnd.` prefix.
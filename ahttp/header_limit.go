@@ -0,0 +1,87 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+var (
+	// ErrHeaderCountExceeded returned when request header field count exceeds
+	// the limit, refer to `ahttp.SetHeaderLimits`.
+	ErrHeaderCountExceeded = errors.New("ahttp: request header count exceeds the limit")
+
+	// ErrHeaderValueTooLarge returned when request header value length exceeds
+	// the limit, refer to `ahttp.SetHeaderLimits`.
+	ErrHeaderValueTooLarge = errors.New("ahttp: request header value exceeds the length limit")
+
+	headerLimitMu     = &sync.RWMutex{}
+	maxHeaderCount    int
+	maxHeaderValueLen int
+)
+
+// SetHeaderLimits method sets the application policy for request headers,
+// maximum header field count (repeated header values are counted
+// individually) and maximum length of header value. Zero or negative value
+// disables the respective check, which is the default.
+//
+// Request headers are checked by `ahttp.ParseRequest`, violation doesn't
+// truncate the headers, it's reported by method `Request.HeaderLimitError`
+// so that middleware can reject the request with
+// `431 Request Header Fields Too Large`.
+//
+// 	For e.g.:
+// 		ahttp.SetHeaderLimits(100, 8192)
+//
+// 		if err := ctx.Req.HeaderLimitError(); err != nil {
+// 			ctx.Reply().Status(http.StatusRequestHeaderFieldsTooLarge).Text(err.Error())
+// 			return
+// 		}
+func SetHeaderLimits(maxCount, maxValueLen int) {
+	headerLimitMu.Lock()
+	maxHeaderCount, maxHeaderValueLen = maxCount, maxValueLen
+	headerLimitMu.Unlock()
+}
+
+// HeaderLimitError method returns the header limit violation error of the
+// request, error is either `ErrHeaderCountExceeded` or
+// `ErrHeaderValueTooLarge`, otherwise nil. Refer to `ahttp.SetHeaderLimits`.
+func (r *Request) HeaderLimitError() error {
+	return r.headerLimitErr
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func checkHeaderLimits(hdr http.Header) error {
+	headerLimitMu.RLock()
+	maxCount, maxValueLen := maxHeaderCount, maxHeaderValueLen
+	headerLimitMu.RUnlock()
+
+	if maxCount > 0 {
+		count := 0
+		for _, values := range hdr {
+			count += len(values)
+		}
+		if count > maxCount {
+			return ErrHeaderCountExceeded
+		}
+	}
+
+	if maxValueLen > 0 {
+		for _, values := range hdr {
+			for _, v := range values {
+				if len(v) > maxValueLen {
+					return ErrHeaderValueTooLarge
+				}
+			}
+		}
+	}
+
+	return nil
+}
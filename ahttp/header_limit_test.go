@@ -0,0 +1,72 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPHeaderLimits(t *testing.T) {
+	defer SetHeaderLimits(0, 0)
+
+	newReq := func(count, valueLen int) *Request {
+		req := httptest.NewRequest(MethodGet, "http://localhost:8080/users", nil)
+		for i := 0; i < count; i++ {
+			req.Header.Add(fmt.Sprintf("X-Custom-%d", i), "value")
+		}
+		if valueLen > 0 {
+			req.Header.Set("X-Large", strings.Repeat("a", valueLen))
+		}
+		return AcquireRequest(req)
+	}
+
+	// disabled by default
+	aahReq := newReq(500, 100000)
+	assert.Nil(t, aahReq.HeaderLimitError())
+	ReleaseRequest(aahReq)
+
+	SetHeaderLimits(10, 64)
+
+	aahReq = newReq(5, 64)
+	assert.Nil(t, aahReq.HeaderLimitError())
+	ReleaseRequest(aahReq)
+
+	// over-limit header count
+	aahReq = newReq(20, 0)
+	err := aahReq.HeaderLimitError()
+	assert.Equal(t, ErrHeaderCountExceeded, err)
+	assert.Equal(t, 20, len(aahReq.Header), "headers are not truncated")
+	ReleaseRequest(aahReq)
+
+	// repeated values are counted individually
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/users", nil)
+	for i := 0; i < 11; i++ {
+		req.Header.Add("X-Forwarded-For", "10.0.0.1")
+	}
+	aahReq = AcquireRequest(req)
+	assert.Equal(t, ErrHeaderCountExceeded, aahReq.HeaderLimitError())
+	ReleaseRequest(aahReq)
+
+	// oversized value
+	aahReq = newReq(1, 65)
+	err = aahReq.HeaderLimitError()
+	assert.Equal(t, ErrHeaderValueTooLarge, err)
+	assert.Equal(t, 65, len(aahReq.Header.Get("X-Large")))
+
+	// reset on release
+	ReleaseRequest(aahReq)
+	assert.Nil(t, aahReq.HeaderLimitError())
+
+	// count only
+	SetHeaderLimits(10, 0)
+	aahReq = newReq(1, 100000)
+	assert.Nil(t, aahReq.HeaderLimitError())
+	ReleaseRequest(aahReq)
+}
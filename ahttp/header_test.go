@@ -92,6 +92,69 @@ func TestHTTPNegotiateLocale(t *testing.T) {
 	assert.Equal(t, "en-CA", locale.String())
 }
 
+func TestHTTPNegotiateLocaleMalformed(t *testing.T) {
+	testcases := []struct {
+		label  string
+		header string
+		locale string
+	}{
+		{"garbage q-value skipped", "fr;q=abc, de;q=0.5", "de"},
+		{"out of range q-value skipped", "fr;q=5, de;q=0.5", "de"},
+		{"empty tags skipped", ", ;q=0.9, ,en-US;q=0.8", "en-US"},
+		{"invalid tag skipped", "12-ab, en_US, en-GB;q=0.7", "en-GB"},
+		{"too long subtag skipped", "verylongtag, es;q=0.4", "es"},
+		{"wildcard skipped", "*, ja;q=0.1", "ja"},
+		{"whitespace tolerated", " pt-BR ;q=0.9", "pt-BR"},
+		{"all malformed", "*;q=abc, 123, ;;;", ""},
+		{"zero quality", "en;q=0", ""},
+		{"empty header", "", ""},
+	}
+
+	tag := func(l *Locale) string {
+		if len(l.Region) > 0 {
+			return l.Language + "-" + l.Region
+		}
+		return l.Language
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			req := createRawHTTPRequest(HeaderAcceptLanguage, tc.header)
+			locale := NegotiateLocale(req)
+			if len(tc.locale) == 0 {
+				assert.Nil(t, locale)
+			} else {
+				assert.Equal(t, tc.locale, tag(locale))
+			}
+
+			// request locale falls back to default
+			aahReq := AcquireRequest(req)
+			defer ReleaseRequest(aahReq)
+			assert.NotNil(t, aahReq.Locale())
+			if len(tc.locale) == 0 {
+				assert.Equal(t, "en", tag(aahReq.Locale()))
+			} else {
+				assert.Equal(t, tc.locale, tag(aahReq.Locale()))
+			}
+		})
+	}
+
+	// configurable default locale
+	SetDefaultLocale(NewLocale("fr-CA"))
+	defer SetDefaultLocale(nil)
+	aahReq := AcquireRequest(createRawHTTPRequest(HeaderAcceptLanguage, "invalid_tag"))
+	defer ReleaseRequest(aahReq)
+	assert.Equal(t, "fr", aahReq.Locale().Language)
+	assert.Equal(t, "CA", aahReq.Locale().Region)
+
+	// default locale is returned as copy
+	DefaultLocale().Language = "de"
+	assert.Equal(t, "fr", DefaultLocale().Language)
+
+	SetDefaultLocale(nil)
+	assert.Equal(t, "en", DefaultLocale().String())
+}
+
 func TestHTTPNegotiateEncoding(t *testing.T) {
 	req1 := createRawHTTPRequest(HeaderAcceptEncoding, "compress;q=0.5, gzip;q=1.0")
 	areq1 := AcquireRequest(req1)
@@ -119,6 +182,38 @@ func TestHTTPNegotiateEncoding(t *testing.T) {
 	assert.Equal(t, "compress;q=0.5", encoding.Raw)
 }
 
+func TestHTTPAcceptEncodingIsGzipAccepted(t *testing.T) {
+	testcases := []struct {
+		label, value string
+		accepted     bool
+	}{
+		{"gzip", "gzip", true},
+		{"gzip uppercase", "GZIP;q=0.8", true},
+		{"gzip rejected", "gzip;q=0", false},
+		{"gzip rejected with zero decimals", "deflate, gzip;q=0.000", false},
+		{"substring only", "x-gzip-custom", false},
+		{"wildcard", "*", true},
+		{"wildcard rejected", "*;q=0", false},
+		{"wildcard with gzip rejected", "gzip;q=0, *", false},
+		{"wildcard rejected with gzip", "gzip;q=0.3, *;q=0", true},
+		{"identity rejected", "identity;q=0", true},
+		{"identity rejected with gzip rejected", "identity;q=0, gzip;q=0", false},
+		{"identity only", "identity", false},
+		{"other coding", "br, deflate", false},
+		{"invalid quality", "gzip;q=abc", false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			req := createRawHTTPRequest(HeaderAcceptEncoding, tc.value)
+			areq := AcquireRequest(req)
+			assert.Equal(t, tc.accepted, areq.IsGzipAccepted)
+			assert.Equal(t, tc.accepted, ParseAcceptEncoding(req).IsAccepted("gzip"))
+			ReleaseRequest(areq)
+		})
+	}
+}
+
 func TestHTTPAcceptHeaderVendorType(t *testing.T) {
 	req := createRawHTTPRequest(HeaderAccept, "application/vnd.mycompany.myapp.customer-v2.2+json")
 	ctype := NegotiateContentType(req)
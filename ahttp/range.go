@@ -0,0 +1,121 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const rangeBytesPrefix = "bytes="
+
+var (
+	// ErrRangeInvalid returned when HTTP header `Range` value is malformed.
+	ErrRangeInvalid = errors.New("ahttp: invalid range")
+
+	// ErrRangeNotSatisfiable returned when none of the ranges from HTTP header
+	// `Range` overlaps the resource size. Typically respond with HTTP status 416.
+	ErrRangeNotSatisfiable = errors.New("ahttp: range not satisfiable")
+)
+
+// HTTPRange represents single byte range of the resource from HTTP header
+// `Range` per RFC7233.
+type HTTPRange struct {
+	Start  int64
+	Length int64
+}
+
+// ContentRange method returns the value for HTTP header `Content-Range`
+// for the given resource size.
+func (r HTTPRange) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.Start+r.Length-1, size)
+}
+
+// ParseRange method parses the HTTP header `Range` per RFC7233 and returns
+// the byte ranges validated against given resource size. It supports multiple
+// ranges and suffix form (e.g. `bytes=-500`). Ranges which start beyond the
+// resource size are ignored, if none of the ranges satisfiable then it returns
+// `ErrRangeNotSatisfiable`. Malformed header, including empty range set
+// (e.g. `bytes=`), returns `ErrRangeInvalid`.
+//
+// It returns nil ranges and error when request doesn't have `Range` header.
+func ParseRange(req *http.Request, size int64) ([]HTTPRange, error) {
+	hdrValue := req.Header.Get(HeaderRange)
+	if hdrValue == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(hdrValue, rangeBytesPrefix) {
+		return nil, ErrRangeInvalid
+	}
+
+	var ranges []HTTPRange
+	noOverlap := false
+	for _, rv := range strings.Split(hdrValue[len(rangeBytesPrefix):], ",") {
+		rv = strings.TrimSpace(rv)
+		if rv == "" {
+			continue
+		}
+
+		idx := strings.Index(rv, "-")
+		if idx < 0 {
+			return nil, ErrRangeInvalid
+		}
+
+		start, end := strings.TrimSpace(rv[:idx]), strings.TrimSpace(rv[idx+1:])
+		var r HTTPRange
+		if start == "" {
+			// suffix form, last N bytes of the resource
+			n, err := strconv.ParseInt(end, 10, 64)
+			if err != nil || n < 0 {
+				return nil, ErrRangeInvalid
+			}
+			if n == 0 || size == 0 {
+				noOverlap = true
+				continue
+			}
+			if n > size {
+				n = size
+			}
+			r.Start = size - n
+			r.Length = n
+		} else {
+			s, err := strconv.ParseInt(start, 10, 64)
+			if err != nil || s < 0 {
+				return nil, ErrRangeInvalid
+			}
+			if s >= size {
+				noOverlap = true
+				continue
+			}
+			r.Start = s
+			if end == "" {
+				r.Length = size - r.Start
+			} else {
+				e, err := strconv.ParseInt(end, 10, 64)
+				if err != nil || r.Start > e {
+					return nil, ErrRangeInvalid
+				}
+				if e >= size {
+					e = size - 1
+				}
+				r.Length = e - r.Start + 1
+			}
+		}
+		ranges = append(ranges, r)
+	}
+
+	if len(ranges) == 0 {
+		if noOverlap {
+			return nil, ErrRangeNotSatisfiable
+		}
+		return nil, ErrRangeInvalid
+	}
+
+	return ranges, nil
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPParseRange(t *testing.T) {
+	testcases := []struct {
+		label  string
+		value  string
+		ranges []HTTPRange
+		err    error
+	}{
+		{label: "single range", value: "bytes=0-499", ranges: []HTTPRange{{Start: 0, Length: 500}}},
+		{label: "open ended range", value: "bytes=9500-", ranges: []HTTPRange{{Start: 9500, Length: 500}}},
+		{label: "suffix range", value: "bytes=-500", ranges: []HTTPRange{{Start: 9500, Length: 500}}},
+		{label: "suffix range larger than size", value: "bytes=-20000", ranges: []HTTPRange{{Start: 0, Length: 10000}}},
+		{label: "end beyond size", value: "bytes=9000-20000", ranges: []HTTPRange{{Start: 9000, Length: 1000}}},
+		{label: "multiple ranges", value: "bytes=0-99, 200-299,-100",
+			ranges: []HTTPRange{{Start: 0, Length: 100}, {Start: 200, Length: 100}, {Start: 9900, Length: 100}}},
+		{label: "overlapping ranges", value: "bytes=0-499,250-749",
+			ranges: []HTTPRange{{Start: 0, Length: 500}, {Start: 250, Length: 500}}},
+		{label: "out of bounds range ignored", value: "bytes=0-99,10000-10099",
+			ranges: []HTTPRange{{Start: 0, Length: 100}}},
+		{label: "out of bounds range", value: "bytes=10000-10099", err: ErrRangeNotSatisfiable},
+		{label: "zero suffix range", value: "bytes=-0", err: ErrRangeNotSatisfiable},
+		{label: "invalid unit", value: "items=0-99", err: ErrRangeInvalid},
+		{label: "invalid range", value: "bytes=100-50", err: ErrRangeInvalid},
+		{label: "invalid number", value: "bytes=a-50", err: ErrRangeInvalid},
+		{label: "no divider", value: "bytes=100", err: ErrRangeInvalid},
+		{label: "empty range set", value: "bytes=", err: ErrRangeInvalid},
+		{label: "empty range entries", value: "bytes= , ", err: ErrRangeInvalid},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			req := createRawHTTPRequest(HeaderRange, tc.value)
+			ranges, err := ParseRange(req, 10000)
+			assert.Equal(t, tc.err, err)
+			assert.Equal(t, tc.ranges, ranges)
+		})
+	}
+
+	// no range header
+	ranges, err := AcquireRequest(createRawHTTPRequest(HeaderAccept, "*/*")).Ranges(10000)
+	assert.Nil(t, err)
+	assert.Nil(t, ranges)
+
+	ranges, err = AcquireRequest(createRawHTTPRequest(HeaderRange, "bytes=0-0")).Ranges(10000)
+	assert.Nil(t, err)
+	assert.Equal(t, "bytes 0-0/10000", ranges[0].ContentRange(10000))
+
+	// empty resource
+	for _, value := range []string{"bytes=-500", "bytes=0-", "bytes=0-99"} {
+		ranges, err = ParseRange(createRawHTTPRequest(HeaderRange, value), 0)
+		assert.Equal(t, ErrRangeNotSatisfiable, err)
+		assert.Nil(t, ranges)
+	}
+}
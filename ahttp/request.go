@@ -5,32 +5,95 @@
 package ahttp
 
 import (
+	"bufio"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"mime/multipart"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"aahframe.work/essentials"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
 )
 
 const (
 	jsonpReqParamKey = "callback"
 	ajaxHeaderValue  = "XMLHttpRequest"
+	maskedValue      = "***"
+	sniffLen         = 512
+	defaultMaxMemory = 32 << 20 // 32 MB, same as Go HTTP request
+	maxJSONPeekSize  = 4 << 10  // 4 KB, same as bufio default
 )
 
-var requestPool = &sync.Pool{New: func() interface{} { return &Request{} }}
+// Device types returned by method `Request.DeviceType`.
+const (
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceDesktop = "desktop"
+	DeviceBot     = "bot"
+)
+
+// ErrBodyTooLarge returned when request body exceeds the limit set by
+// method `Request.LimitBody`.
+var ErrBodyTooLarge = errors.New("ahttp: request body too large")
+
+var (
+	// RedactParamKeys is default list of sensitive param keys redacted by
+	// method `Request.RedactedParams`. It can be extended by application.
+	RedactParamKeys = []string{"password", "passwd", "secret", "token", "api_key"}
+
+	requestPool = &sync.Pool{New: func() interface{} { return &Request{} }}
+
+	tlsVersionNames = map[uint16]string{
+		tls.VersionTLS10: "TLS1.0",
+		tls.VersionTLS11: "TLS1.1",
+		tls.VersionTLS12: "TLS1.2",
+		0x0304:           "TLS1.3", // tls.VersionTLS13 is available since Go 1.12
+	}
+
+	signatureMu   = &sync.RWMutex{}
+	botSignatures = []string{
+		"Googlebot", "Bingbot", "Slurp", "DuckDuckBot", "Baiduspider",
+		"YandexBot", "Sogou", "Exabot", "facebookexternalhit", "Twitterbot",
+		"LinkedInBot", "Applebot", "AhrefsBot", "SemrushBot", "MJ12bot",
+		"PetalBot",
+	}
+
+	deviceSignatures = map[string][]string{
+		DeviceTablet: {"iPad", "Tablet", "Kindle", "Silk/", "PlayBook", "Nexus 7", "Nexus 10"},
+		DeviceMobile: {"Mobile", "iPhone", "iPod", "Windows Phone", "BlackBerry", "Opera Mini", "IEMobile"},
+	}
+
+	sessionKeyMu      = &sync.RWMutex{}
+	sessionCookieName = "aah_session"
+	sessionHeaderName = "X-Session-Id"
+
+	fingerprintMu      = &sync.RWMutex{}
+	fingerprintHeaders = []string{HeaderUserAgent, HeaderAcceptLanguage}
+)
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Package methods
 //___________________________________
 
 // ParseRequest method populates the given aah framework `ahttp.Request`
-// instance from Go HTTP request.
+// instance from Go HTTP request. Request headers are checked against the
+// limits, refer to `ahttp.SetHeaderLimits`.
 func ParseRequest(r *http.Request, req *Request) *Request {
 	req.Scheme = Scheme(r)
 	req.Host = Host(r)
@@ -38,15 +101,85 @@ func ParseRequest(r *http.Request, req *Request) *Request {
 	req.Method = r.Method
 	req.Path = r.URL.Path
 	req.Header = r.Header
-	if h := r.Header[HeaderAcceptEncoding]; len(h) > 0 {
-		req.IsGzipAccepted = strings.Contains(h[0], "gzip")
+	if len(r.Header[HeaderAcceptEncoding]) > 0 {
+		req.IsGzipAccepted = ParseAcceptEncoding(r).IsAccepted("gzip")
 	}
+	req.headerLimitErr = checkHeaderLimits(r.Header)
 	req.raw = r
 	req.raw.URL.Scheme = req.Scheme
 	req.raw.URL.Host = req.Host
 	return req
 }
 
+// SetBotSignatures method sets the list of bot signatures used by methods
+// `Request.IsBot` and `Request.BotName`. Signature is matched against
+// `User-Agent` header value as case-insensitive substring, in the given order.
+func SetBotSignatures(signatures []string) {
+	signatureMu.Lock()
+	botSignatures = append([]string(nil), signatures...)
+	signatureMu.Unlock()
+}
+
+// SetDeviceSignatures method sets the list of signatures for given device type
+// `mobile` or `tablet` used by method `Request.DeviceType`. Signature is
+// matched against `User-Agent` header value as case-insensitive substring.
+// Bot signatures are set via method `ahttp.SetBotSignatures`.
+func SetDeviceSignatures(deviceType string, signatures []string) error {
+	if deviceType != DeviceMobile && deviceType != DeviceTablet {
+		return fmt.Errorf("ahttp: unsupported device type '%s'", deviceType)
+	}
+	signatureMu.Lock()
+	deviceSignatures[deviceType] = append([]string(nil), signatures...)
+	signatureMu.Unlock()
+	return nil
+}
+
+// SetSessionKeys method sets the default cookie name and header name used by
+// method `Request.SessionID`, default values are `aah_session` and
+// `X-Session-Id`. Empty value leaves the respective default unchanged.
+func SetSessionKeys(cookieName, headerName string) {
+	sessionKeyMu.Lock()
+	if len(cookieName) > 0 {
+		sessionCookieName = cookieName
+	}
+	if len(headerName) > 0 {
+		sessionHeaderName = headerName
+	}
+	sessionKeyMu.Unlock()
+}
+
+// SetFingerprintHeaders method sets the request headers contributing to
+// method `Request.Fingerprint` along with client IP, default headers are
+// `User-Agent` and `Accept-Language`. Empty list means client IP only.
+func SetFingerprintHeaders(headers []string) {
+	hdrs := make([]string, 0, len(headers))
+	for _, h := range headers {
+		if h = strings.TrimSpace(h); len(h) > 0 {
+			hdrs = append(hdrs, http.CanonicalHeaderKey(h))
+		}
+	}
+	fingerprintMu.Lock()
+	fingerprintHeaders = hdrs
+	fingerprintMu.Unlock()
+}
+
+// DetectFileContentType method detects the content type of the uploaded file
+// by sniffing first 512 bytes of the file using `http.DetectContentType`.
+func DetectFileContentType(fh *multipart.FileHeader) (string, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer ess.CloseQuietly(f)
+
+	buf := make([]byte, sniffLen)
+	n, err := io.ReadFull(f, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Request
 //___________________________________
@@ -76,14 +209,18 @@ type Request struct {
 	URLParams URLParams
 
 	// IsGzipAccepted is true if the HTTP client accepts Gzip response,
-	// otherwise false.
+	// otherwise false. It's negotiated from the header `Accept-Encoding`,
+	// refer to method `AcceptSpecs.IsAccepted`.
 	IsGzipAccepted bool
 
 	raw               *http.Request
 	locale            *Locale
 	contentType       *ContentType
 	acceptContentType *ContentType
+	userAgent         *UserAgentInfo
+	routePattern      string
 	acceptEncoding    *AcceptSpec
+	headerLimitErr    error
 }
 
 // AcceptContentType method returns negotiated value.
@@ -102,6 +239,33 @@ func (r *Request) AcceptContentType() *ContentType {
 	return r.acceptContentType
 }
 
+// NegotiateContentType method returns the best match from given offered
+// content types for HTTP header `Accept` otherwise empty string, so that
+// handler can respond with `406 Not Acceptable`. Each offer is rated by its
+// most specific matching media range (exact, `type/*` then `*/*`) and the
+// highest quality factor wins, ties are resolved by offers order. First
+// offer is returned when `Accept` header is not present.
+//
+// 	For e.g.: `Accept: text/*;q=0.5, application/json`
+// 		r.NegotiateContentType("text/html", "application/json") // returns "application/json"
+func (r *Request) NegotiateContentType(offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+	specs := ParseAccept(r.Unwrap(), HeaderAccept)
+	if len(specs) == 0 {
+		return offers[0]
+	}
+
+	best, bestQ := "", float32(0)
+	for _, offer := range offers {
+		if q := offerQuality(specs, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
 // SetAcceptContentType method is used to set Accept ContentType instance.
 func (r *Request) SetAcceptContentType(contentType *ContentType) *Request {
 	r.acceptContentType = contentType
@@ -134,6 +298,59 @@ func (r *Request) ClientIP() string {
 	return ClientIP(r.Unwrap())
 }
 
+// ForwardingHeaders method returns the forwarding headers to be set on the
+// outbound request when proxying this request to a backend. The immediate
+// peer IP (from `RemoteAddr`) is appended to the existing `X-Forwarded-For`
+// chain. `X-Forwarded-Proto`, `X-Forwarded-Host` and `X-Forwarded-Port` are
+// preserved if already set by upstream proxy otherwise derived from this
+// request.
+//
+// 	For e.g.:
+// 		for k, v := range req.ForwardingHeaders() {
+// 			outReq.Header[k] = v
+// 		}
+func (r *Request) ForwardingHeaders() http.Header {
+	hdr := make(http.Header, 4)
+
+	peerIP := r.Unwrap().RemoteAddr
+	if ip, _, err := net.SplitHostPort(peerIP); err == nil {
+		peerIP = ip
+	}
+	xff := strings.Join(r.Header[HeaderXForwardedFor], ", ")
+	switch {
+	case len(xff) > 0 && len(peerIP) > 0:
+		hdr.Set(HeaderXForwardedFor, xff+", "+peerIP)
+	case len(xff) > 0:
+		hdr.Set(HeaderXForwardedFor, xff)
+	case len(peerIP) > 0:
+		hdr.Set(HeaderXForwardedFor, peerIP)
+	}
+
+	hdr.Set(HeaderXForwardedProto, r.Scheme)
+
+	host := r.Header.Get(HeaderXForwardedHost)
+	if len(host) == 0 {
+		host = r.Host
+	}
+	hostname, port, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+	}
+	hdr.Set(HeaderXForwardedHost, hostname)
+
+	if p := r.Header.Get(HeaderXForwardedPort); len(p) > 0 {
+		port = p
+	} else if len(port) == 0 {
+		port = "80"
+		if r.Scheme == "https" {
+			port = "443"
+		}
+	}
+	hdr.Set(HeaderXForwardedPort, port)
+
+	return hdr
+}
+
 // Cookie method returns a named cookie from HTTP request otherwise error.
 func (r *Request) Cookie(name string) (*http.Cookie, error) {
 	return r.Unwrap().Cookie(name)
@@ -144,6 +361,72 @@ func (r *Request) Cookies() []*http.Cookie {
 	return r.Unwrap().Cookies()
 }
 
+// SignedCookie method returns the verified value of named signed cookie from
+// HTTP request. It returns error `http.ErrNoCookie` if cookie is missing and
+// `ErrCookieSignatureInvalid` if cookie value has been tampered.
+//
+// Refer to method `ahttp.SignCookieValue` for signed value format.
+func (r *Request) SignedCookie(name string, key []byte) (string, error) {
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return VerifyCookieValue(name, cookie.Value, key)
+}
+
+// SessionID method returns the session identifier from HTTP request. Named
+// cookie is checked first, then the named header; it returns empty string
+// when neither is present. Empty name falls back to package default, refer
+// to `ahttp.SetSessionKeys`.
+//
+// It only extracts the value, for the signed session cookie use method
+// `Request.SignedCookie`.
+func (r *Request) SessionID(cookieName, headerName string) string {
+	if len(cookieName) == 0 || len(headerName) == 0 {
+		sessionKeyMu.RLock()
+		if len(cookieName) == 0 {
+			cookieName = sessionCookieName
+		}
+		if len(headerName) == 0 {
+			headerName = sessionHeaderName
+		}
+		sessionKeyMu.RUnlock()
+	}
+
+	if cookie, err := r.Cookie(cookieName); err == nil && len(cookie.Value) > 0 {
+		return cookie.Value
+	}
+	return strings.TrimSpace(r.Header.Get(headerName))
+}
+
+// Fingerprint method returns the stable opaque key of the client, it's the
+// SHA-256 hash (hex, 32 chars) of the client IP and the normalized values of
+// fingerprint headers, refer to `ahttp.SetFingerprintHeaders`. Header values
+// are compared in case-insensitive with whitespace collapsed. It's meant for
+// per-client buckets of rate limiter and abuse detection.
+//
+// Note: It's a heuristic not a security identity, clients behind the same
+// NAT with same browser share the fingerprint and headers are client
+// supplied.
+//
+// 	For e.g.:
+// 		if !limiter.Allow(req.Fingerprint()) {
+// 			// reply 429 Too Many Requests
+// 		}
+func (r *Request) Fingerprint() string {
+	h := sha256.New()
+	_, _ = h.Write([]byte(r.ClientIP()))
+
+	fingerprintMu.RLock()
+	for _, name := range fingerprintHeaders {
+		value := strings.ToLower(strings.Join(strings.Fields(r.Header.Get(name)), " "))
+		_, _ = h.Write([]byte("\x00" + name + "\x00" + value))
+	}
+	fingerprintMu.RUnlock()
+
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
 // ContentType method returns the parsed value of HTTP header `Content-Type` per RFC1521.
 func (r *Request) ContentType() *ContentType {
 	if r.contentType == nil {
@@ -158,15 +441,108 @@ func (r *Request) SetContentType(contType *ContentType) *Request {
 	return r
 }
 
+// Ranges method returns the parsed byte ranges of HTTP header `Range` for
+// the given resource size. Refer to method `ahttp.ParseRange`.
+func (r *Request) Ranges(size int64) ([]HTTPRange, error) {
+	return ParseRange(r.Unwrap(), size)
+}
+
 // Locale method returns negotiated value from HTTP Header `Accept-Language`
-// per RFC7231.
+// per RFC7231. It never returns nil, default locale is returned when header
+// is missing or has no valid entry. Refer to `ahttp.SetDefaultLocale`.
 func (r *Request) Locale() *Locale {
 	if r.locale == nil {
-		r.locale = NegotiateLocale(r.Unwrap())
+		if r.locale = NegotiateLocale(r.Unwrap()); r.locale == nil {
+			r.locale = DefaultLocale()
+		}
 	}
 	return r.locale
 }
 
+// AcceptLanguages method returns the BCP 47 language tags from HTTP header
+// `Accept-Language` in the preference order. Tags with quality factor `0`
+// and wildcard `*` are excluded.
+//
+// 	For e.g.:
+// 		Accept-Language: da, en-gb;q=0.8, en;q=0.7
+//
+// 		Method returns `[]string{"da", "en-gb", "en"}`
+func (r *Request) AcceptLanguages() []string {
+	var tags []string
+	for _, spec := range ParseAccept(r.Unwrap(), HeaderAcceptLanguage) {
+		if spec.Q <= 0 || spec.Value == "*" {
+			continue
+		}
+		tags = append(tags, spec.Value)
+	}
+	return tags
+}
+
+// MatchLanguage method returns the best match from given supported languages
+// for HTTP header `Accept-Language` otherwise empty string.
+//
+// Accepted languages are matched in the preference order using
+// `golang.org/x/text/language` matcher, so regional variants and base
+// language match each other (e.g. `en-US` matches `en` and `en` matches
+// `en-GB`). Only the high confidence match is considered. Wildcard `*`
+// matches the first supported language when nothing else matched.
+func (r *Request) MatchLanguage(supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+
+	var desired []language.Tag
+	wildcard := false
+	for _, spec := range ParseAccept(r.Unwrap(), HeaderAcceptLanguage) {
+		if spec.Q <= 0 {
+			continue
+		}
+		if spec.Value == "*" {
+			wildcard = true
+			continue
+		}
+		if t, err := language.Parse(spec.Value); err == nil {
+			desired = append(desired, t)
+		}
+	}
+
+	if len(desired) > 0 {
+		tags := make([]language.Tag, 0, len(supported))
+		indexes := make([]int, 0, len(supported))
+		for i, s := range supported {
+			if t, err := language.Parse(s); err == nil {
+				tags = append(tags, t)
+				indexes = append(indexes, i)
+			}
+		}
+		if len(tags) > 0 {
+			_, idx, conf := language.NewMatcher(tags).Match(desired...)
+			if conf >= language.High {
+				return supported[indexes[idx]]
+			}
+		}
+	}
+
+	if wildcard {
+		return supported[0]
+	}
+	return ""
+}
+
+// PreferredLanguage method returns the best match between HTTP header
+// `Accept-Language` and given application supported languages otherwise
+// given default language. Matching follows the quality order with
+// language/region fallback, refer to method `Request.MatchLanguage`.
+//
+// 	For e.g.: `Accept-Language: de-DE, de;q=0.9, fr;q=0.8`
+// 		r.PreferredLanguage([]string{"en", "fr"}, "en") // returns "fr"
+func (r *Request) PreferredLanguage(supported []string, def string) string {
+	if lang := r.MatchLanguage(supported); len(lang) > 0 {
+		return lang
+	}
+	return def
+}
+
 // SetLocale method is used to set locale instance in to aah request.
 func (r *Request) SetLocale(locale *Locale) *Request {
 	r.locale = locale
@@ -185,11 +561,243 @@ func (r *Request) IsAJAX() bool {
 	return r.Header.Get(HeaderXRequestedWith) == ajaxHeaderValue
 }
 
+// RecommendSecureCookie method returns true if the request is served over
+// HTTPS otherwise false. It's the authoritative source for cookie `Secure`
+// flag decision, scheme is proxy-aware, refer to `ahttp.Scheme`.
+//
+// 	For e.g.:
+// 		cookie.Secure = ctx.Req.RecommendSecureCookie()
+func (r *Request) RecommendSecureCookie() bool {
+	return strings.EqualFold(r.Scheme, "https")
+}
+
+// SecureCompare method compares the provided value (e.g. API key from header
+// or query param) with expected value in constant time, to prevent timing
+// attacks. Length difference is handled safely, values are compared
+// via SHA-256 digest, so comparison time doesn't depend on the length or
+// position of the first mismatch.
+func (r *Request) SecureCompare(provided, expected string) bool {
+	p := sha256.Sum256([]byte(provided))
+	e := sha256.Sum256([]byte(expected))
+	return subtle.ConstantTimeCompare(p[:], e[:]) == 1
+}
+
+// VerifyHeaderToken method returns true if the given request header value
+// matches the expected token using method `Request.SecureCompare`, otherwise
+// false. It returns false if expected token is empty.
+//
+// 	For e.g.:
+// 		if !req.VerifyHeaderToken("X-API-Key", apiKey) {
+// 			// respond with 401 Unauthorized
+// 		}
+func (r *Request) VerifyHeaderToken(headerName, expected string) bool {
+	if len(expected) == 0 {
+		return false
+	}
+	return r.SecureCompare(r.Header.Get(headerName), expected)
+}
+
+// SupportsHTTP2 method returns true if request is received over HTTP/2
+// protocol otherwise false. Streaming handlers can use it to decide the
+// strategy, e.g. HTTP/2 connection cannot be hijacked.
+func (r *Request) SupportsHTTP2() bool {
+	return r.Unwrap().ProtoMajor == 2
+}
+
+// SupportsWebSocketUpgrade method returns true if request is WebSocket
+// upgrade request per RFC 6455 i.e. HTTP/1.1 `GET` request with header
+// `Connection` containing token `upgrade` and header `Upgrade: websocket`
+// (case-insensitive). Otherwise false.
+func (r *Request) SupportsWebSocketUpgrade() bool {
+	raw := r.Unwrap()
+	if raw.Method != MethodGet || raw.ProtoMajor != 1 || raw.ProtoMinor < 1 {
+		return false
+	}
+	return headerHasToken(r.Header, HeaderConnection, "upgrade") &&
+		headerHasToken(r.Header, HeaderUpgrade, "websocket")
+}
+
+// ExpectsContinue method returns true if HTTP client sent header
+// `Expect: 100-continue` and waits for acknowledgement before transmitting
+// the request body, otherwise false.
+//
+// Go HTTP server sends `100 Continue` automatically on first read of request
+// body. So handler can reject the request early (e.g. `Content-Length` exceeds
+// the size limit) by responding without reading the body, then client doesn't
+// transmit the body.
+//
+// 	For e.g.:
+// 		if req.ExpectsContinue() && req.Unwrap().ContentLength > maxUploadSize {
+// 			// respond with 413 Request Entity Too Large without reading body
+// 		}
+func (r *Request) ExpectsContinue() bool {
+	return strings.EqualFold(strings.TrimSpace(r.Header.Get(HeaderExpect)), "100-continue")
+}
+
+// Origin method returns value of HTTP 'Origin' header, it could be `null`
+// for privacy-sensitive contexts.
+func (r *Request) Origin() string {
+	return strings.TrimSpace(r.Header.Get(HeaderOrigin))
+}
+
+// IsPreflight method returns true if request is CORS preflight request i.e.
+// HTTP method `OPTIONS` with header `Access-Control-Request-Method`,
+// otherwise false.
+func (r *Request) IsPreflight() bool {
+	return r.Method == MethodOptions && len(r.Header.Get(HeaderAccessControlRequestMethod)) > 0
+}
+
+// OriginMatches method returns true if request origin matches one of the
+// given allowed origins otherwise false. Scheme and host are compared
+// case-insensitive. Allowed origin could be-
+//
+//  - Exact origin, e.g. `https://example.com`, `http://localhost:8080`
+//
+//  - Wildcard subdomain with or without scheme, e.g. `https://*.example.com`,
+//    `*.example.com`. It doesn't match the apex domain `example.com`
+//
+//  - `*` matches any origin except `null`
+//
+//  - `null` matches the null origin, allow it with care
+func (r *Request) OriginMatches(allowed []string) bool {
+	origin := r.Origin()
+	if len(origin) == 0 {
+		return false
+	}
+
+	if strings.EqualFold(origin, "null") {
+		for _, a := range allowed {
+			if strings.EqualFold(strings.TrimSpace(a), "null") {
+				return true
+			}
+		}
+		return false
+	}
+
+	scheme, host, ok := parseOrigin(origin)
+	return ok && originAllowed(scheme, host, allowed)
+}
+
+// IsSameOrigin method returns true if the request `Origin` header (or
+// `Referer` header when `Origin` is missing) has the same scheme and host
+// as the request, otherwise false. It's a building block for CSRF defense
+// on state-changing endpoints. Missing headers and `null` origin are
+// considered as cross-origin. Scheme and host are compared case-insensitive
+// and default ports are ignored, e.g. `https://example.com:443` is same as
+// `https://example.com`.
+func (r *Request) IsSameOrigin() bool {
+	source := r.Origin()
+	if len(source) == 0 {
+		source = strings.TrimSpace(r.Referer())
+	}
+	scheme, host, ok := parseOrigin(source)
+	if !ok {
+		return false
+	}
+	return scheme == strings.ToLower(r.Scheme) &&
+		stripDefaultPort(scheme, host) == stripDefaultPort(scheme, strings.ToLower(r.Host))
+}
+
+// RefererMatches method returns true if the scheme and host of request
+// `Referer` header matches one of the given allowed origins otherwise false.
+// Allowed origin values are same as method `OriginMatches`.
+func (r *Request) RefererMatches(allowed []string) bool {
+	scheme, host, ok := parseOrigin(strings.TrimSpace(r.Referer()))
+	return ok && originAllowed(scheme, host, allowed)
+}
+
+// NotModified method returns true if the client's cached representation is
+// unchanged as per conditional request headers, so that handler can respond
+// with `304 Not Modified` instead of full body. Otherwise false. It's
+// applicable only to HTTP methods `GET` and `HEAD`.
+//
+// Per RFC 7232 precedence, header `If-None-Match` is evaluated with weak
+// comparison against given etag, and header `If-Modified-Since` is evaluated
+// against given modtime only when `If-None-Match` is not present.
+//
+// 	For e.g.:
+// 		if req.NotModified(etag, fileInfo.ModTime()) {
+// 			// respond with 304 Not Modified
+// 		}
+func (r *Request) NotModified(etag string, modtime time.Time) bool {
+	if r.Method != MethodGet && r.Method != MethodHead {
+		return false
+	}
+
+	if inm := r.Header.Get(HeaderIfNoneMatch); len(inm) > 0 {
+		return etagWeakMatch(inm, etag)
+	}
+
+	ims := r.Header.Get(HeaderIfModifiedSince)
+	if len(ims) == 0 || modtime.IsZero() || modtime.Unix() == 0 {
+		return false
+	}
+	t, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	// HTTP dates have second precision
+	return !modtime.Truncate(time.Second).After(t)
+}
+
 // URL method return underlying request URL instance.
 func (r *Request) URL() *url.URL {
 	return r.Unwrap().URL
 }
 
+// WithQuery method returns a new URL based on the current request URL with
+// query param key set to given value, existing params are preserved. It
+// doesn't modify the request. URL scheme and host are derived from the request,
+// refer to `ahttp.Scheme` and `ahttp.Host`.
+//
+// 	For e.g.:
+// 		// current URL http://localhost:8080/users?status=active&page=2
+// 		next := req.WithQuery("page", "3")
+// 		// http://localhost:8080/users?page=3&status=active
+func (r *Request) WithQuery(key, value string) *url.URL {
+	return r.modifyQuery(func(q url.Values) { q.Set(key, value) })
+}
+
+// WithoutQuery method returns a new URL based on the current request URL with
+// query param key removed, existing params are preserved. It doesn't modify
+// the request.
+func (r *Request) WithoutQuery(key string) *url.URL {
+	return r.modifyQuery(func(q url.Values) { q.Del(key) })
+}
+
+// TLSVersion method returns the negotiated TLS version of the connection
+// (e.g. `tls.VersionTLS12`) and true, otherwise false for plaintext connection.
+func (r *Request) TLSVersion() (uint16, bool) {
+	if r.Unwrap().TLS == nil {
+		return 0, false
+	}
+	return r.Unwrap().TLS.Version, true
+}
+
+// TLSVersionName method returns human-readable name of the negotiated TLS
+// version e.g. `TLS1.2`, `TLS1.3`. Otherwise empty string for plaintext
+// connection.
+func (r *Request) TLSVersionName() string {
+	v, ok := r.TLSVersion()
+	if !ok {
+		return ""
+	}
+	if name, found := tlsVersionNames[v]; found {
+		return name
+	}
+	return fmt.Sprintf("unknown(0x%04x)", v)
+}
+
+// TLSCipherSuite method returns the negotiated cipher suite of the connection
+// (e.g. `tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256`) and true, otherwise false
+// for plaintext connection.
+func (r *Request) TLSCipherSuite() (uint16, bool) {
+	if r.Unwrap().TLS == nil {
+		return 0, false
+	}
+	return r.Unwrap().TLS.CipherSuite, true
+}
+
 // Referer method returns value of HTTP 'Referrer' (or 'Referer') header.
 func (r *Request) Referer() string {
 	if h := r.Header[HeaderReferer]; len(h) > 0 {
@@ -206,18 +814,210 @@ func (r *Request) UserAgent() string {
 	return r.Header.Get(HeaderUserAgent)
 }
 
+// IsBot method returns true if request `User-Agent` matches one of the known
+// bot signatures otherwise false. Refer to `ahttp.SetBotSignatures`.
+//
+// Note: It's a heuristic based on client supplied header, not an authentication.
+func (r *Request) IsBot() bool {
+	return len(r.BotName()) > 0
+}
+
+// BotName method returns the bot signature matched with request `User-Agent`
+// (e.g. `Googlebot`) otherwise empty string.
+func (r *Request) BotName() string {
+	ua := strings.ToLower(r.UserAgent())
+	if len(ua) == 0 {
+		return ""
+	}
+
+	signatureMu.RLock()
+	defer signatureMu.RUnlock()
+	for _, sig := range botSignatures {
+		if len(sig) > 0 && strings.Contains(ua, strings.ToLower(sig)) {
+			return sig
+		}
+	}
+	return ""
+}
+
+// DeviceType method returns the rough device class of the client `bot`,
+// `tablet`, `mobile` or `desktop` based on `User-Agent` signatures. Android
+// without `Mobile` token is considered as tablet. It returns `desktop` when
+// no signature matches, including empty `User-Agent`. Refer to
+// `ahttp.SetDeviceSignatures`.
+//
+// Note: It's a heuristic based on client supplied header, it's meant for
+// response tailoring and analytics, not a device detection.
+func (r *Request) DeviceType() string {
+	if r.IsBot() {
+		return DeviceBot
+	}
+
+	ua := strings.ToLower(r.UserAgent())
+	if len(ua) == 0 {
+		return DeviceDesktop
+	}
+
+	signatureMu.RLock()
+	defer signatureMu.RUnlock()
+	if uaContainsAny(ua, deviceSignatures[DeviceTablet]) ||
+		(strings.Contains(ua, "android") && !strings.Contains(ua, "mobile")) {
+		return DeviceTablet
+	}
+	if uaContainsAny(ua, deviceSignatures[DeviceMobile]) || strings.Contains(ua, "android") {
+		return DeviceMobile
+	}
+	return DeviceDesktop
+}
+
+// StripPrefix method returns the request path relative to given prefix (mount
+// point) and true if prefix matched, otherwise request path as-is and false.
+// It doesn't modify the request `Path`. Trailing slash of prefix is ignored.
+//
+// 	For e.g.:
+// 		prefix `/api/v1` and path `/api/v1/users` => `/users`, true
+// 		prefix `/api/v1` and path `/api/v1` => `/`, true
+// 		prefix `/api/v1` and path `/api/v10/users` => `/api/v10/users`, false
+func (r *Request) StripPrefix(prefix string) (string, bool) {
+	prefix = strings.TrimRight(prefix, "/")
+	if len(prefix) == 0 {
+		return r.Path, true
+	}
+	if r.Path == prefix || r.Path == prefix+"/" {
+		return "/", true
+	}
+	if strings.HasPrefix(r.Path, prefix+"/") {
+		return r.Path[len(prefix):], true
+	}
+	return r.Path, false
+}
+
+// PathSegments method returns the request path split on `/` with each segment
+// URL-decoded, empty segments are dropped. Encoded slash `%2F` is kept within
+// the segment.
+//
+// 	For e.g.:
+// 		path `/files/docs/a%2Fb.txt/` => ["files", "docs", "a/b.txt"]
+func (r *Request) PathSegments() []string {
+	escaped, decode := r.URL().EscapedPath(), true
+	if p, err := url.PathUnescape(escaped); err != nil || p != r.Path {
+		// request path has been modified, it's already decoded
+		escaped, decode = r.Path, false
+	}
+
+	segments := make([]string, 0)
+	for _, seg := range strings.Split(escaped, "/") {
+		if len(seg) == 0 {
+			continue
+		}
+		if decode {
+			if v, err := url.PathUnescape(seg); err == nil {
+				seg = v
+			}
+		}
+		segments = append(segments, seg)
+	}
+	return segments
+}
+
+// PathSegment method returns the path segment for given index from method
+// `Request.PathSegments` otherwise empty string if index is out of range.
+func (r *Request) PathSegment(i int) string {
+	segments := r.PathSegments()
+	if i < 0 || i >= len(segments) {
+		return ""
+	}
+	return segments[i]
+}
+
+// CanonicalPath method returns the canonical form of request path for
+// matching and comparison, e.g. access rules, so that equivalent paths
+// encoded differently such as `/café`, `/caf%C3%A9` and `/cafe%CC%81` map to
+// the same value. Each path segment is URL-decoded, Unicode NFC normalized
+// and re-encoded with lowercase percent-encoding hex digits; empty and dot
+// segments are resolved.
+//
+// 	For e.g.:
+// 		path `/a//./b/../Caf%C3%A9/` => `/a/Caf%c3%a9`
+//
+// Note: Use it for comparison only not for serving the request, since
+// canonical path may not be the resource path on the file system.
+func (r *Request) CanonicalPath() string {
+	segments := make([]string, 0)
+	for _, seg := range r.PathSegments() {
+		switch seg = norm.NFC.String(seg); seg {
+		case ".":
+		case "..":
+			if len(segments) > 0 {
+				segments = segments[:len(segments)-1]
+			}
+		default:
+			segments = append(segments, lowerPercentEncoding(url.PathEscape(seg)))
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
 // PathValue method returns value for given Path param key otherwise empty string.
 // For eg.: /users/:userId => PathValue("userId")
 func (r *Request) PathValue(key string) string {
 	return r.URLParams.Get(key)
 }
 
+// SetRoutePattern method sets the matched route path template, it's set by
+// the router on route match.
+func (r *Request) SetRoutePattern(pattern string) *Request {
+	r.routePattern = pattern
+	return r
+}
+
+// RoutePattern method returns the matched route path template (e.g.
+// `/users/:id`) otherwise empty string. Unlike the request path, it's low
+// cardinality, so use it for metrics labels and aggregated logs.
+func (r *Request) RoutePattern() string {
+	return r.routePattern
+}
+
 // QueryValue method returns value for given URL query param key
 // otherwise empty string.
 func (r *Request) QueryValue(key string) string {
 	return r.URL().Query().Get(key)
 }
 
+// RawQueryValue method returns the first value for given URL query param key
+// as-is from raw query string without decoding, otherwise empty string.
+// Whereas method `QueryValue` returns the decoded value, e.g. for query
+// `?q=a+b%2Fc` `QueryValue` returns `a b/c` and `RawQueryValue` returns
+// `a+b%2Fc`. It's useful for signature verification and proxying where
+// encoding differences (`+` vs `%20`) matter. Key is matched after decoding.
+func (r *Request) RawQueryValue(key string) string {
+	for _, pair := range strings.Split(r.URL().RawQuery, "&") {
+		if len(pair) == 0 {
+			continue
+		}
+		k, v := pair, ""
+		if idx := strings.IndexByte(pair, '='); idx > -1 {
+			k, v = pair[:idx], pair[idx+1:]
+		}
+		if dk, err := url.QueryUnescape(k); err == nil {
+			k = dk
+		}
+		if k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+// QueryValueDefault method returns value for given URL query param key
+// otherwise given default value when it's missing or empty.
+func (r *Request) QueryValueDefault(key, defaultValue string) string {
+	if v := r.QueryValue(key); len(v) > 0 {
+		return v
+	}
+	return defaultValue
+}
+
 // QueryArrayValue method returns array value for given URL query param key
 // otherwise empty string slice.
 func (r *Request) QueryArrayValue(key string) []string {
@@ -227,11 +1027,38 @@ func (r *Request) QueryArrayValue(key string) []string {
 	return []string{}
 }
 
+// QueryValuesFiltered method returns the copy of URL query params containing
+// only the given allowed keys, e.g. forwarding only known-safe params to
+// downstream request. Keys are matched exactly (case-sensitive) and
+// multiple values of a key are preserved.
+// 	For e.g.:
+// 		// ?q=aah&page=2&utm_source=mail
+// 		r.QueryValuesFiltered("q", "page").Encode() // page=2&q=aah
+func (r *Request) QueryValuesFiltered(allowed ...string) url.Values {
+	return r.filterQuery(allowed, true)
+}
+
+// QueryValuesExcept method returns the copy of URL query params without the
+// given blocked keys, e.g. dropping tracking params. Keys are matched
+// exactly (case-sensitive). Refer to method `QueryValuesFiltered`.
+func (r *Request) QueryValuesExcept(blocked ...string) url.Values {
+	return r.filterQuery(blocked, false)
+}
+
 // FormValue method returns value for given form key otherwise empty string.
 func (r *Request) FormValue(key string) string {
 	return r.Unwrap().FormValue(key)
 }
 
+// FormValueDefault method returns value for given form key otherwise
+// given default value when it's missing or empty.
+func (r *Request) FormValueDefault(key, defaultValue string) string {
+	if v := r.FormValue(key); len(v) > 0 {
+		return v
+	}
+	return defaultValue
+}
+
 // FormArrayValue method returns array value for given form key
 // otherwise empty string slice.
 func (r *Request) FormArrayValue(key string) []string {
@@ -243,17 +1070,260 @@ func (r *Request) FormArrayValue(key string) []string {
 	return []string{}
 }
 
+// FormValues method returns the copy of parsed form values, it includes the
+// URL query params same as method `FormValue`. It parses the form
+// (urlencoded or multipart) if it's not parsed yet. Modifying the returned
+// values doesn't affect the request.
+func (r *Request) FormValues() url.Values {
+	raw := r.Unwrap()
+	if raw.Form == nil {
+		_ = raw.ParseMultipartForm(defaultMaxMemory)
+	}
+	values := make(url.Values, len(raw.Form))
+	for k, v := range raw.Form {
+		values[k] = append([]string(nil), v...)
+	}
+	return values
+}
+
+// FormKeys method returns the sorted form keys from method
+// `Request.FormValues`.
+func (r *Request) FormKeys() []string {
+	values := r.FormValues()
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RedactedParams method returns URL query and form params (first value) with
+// values of sensitive keys replaced by `***`, it's meant for logging.
+// Sensitive keys are `RedactParamKeys` and given keys, key matching is
+// case-insensitive. Form params are included only if the form is already
+// parsed, so that request body is not consumed.
+func (r *Request) RedactedParams(keys ...string) map[string]string {
+	redact := make(map[string]bool, len(RedactParamKeys)+len(keys))
+	for _, k := range RedactParamKeys {
+		redact[strings.ToLower(k)] = true
+	}
+	for _, k := range keys {
+		redact[strings.ToLower(k)] = true
+	}
+
+	params := make(map[string]string)
+	add := func(values url.Values) {
+		for k, v := range values {
+			if len(v) == 0 {
+				continue
+			}
+			if redact[strings.ToLower(k)] {
+				params[k] = maskedValue
+			} else {
+				params[k] = v[0]
+			}
+		}
+	}
+
+	add(r.URL().Query())
+	add(r.Unwrap().PostForm)
+	return params
+}
+
 // FormFile method returns the first file for the provided form key otherwise
 // returns error. It is caller responsibility to close the file.
 func (r *Request) FormFile(key string) (multipart.File, *multipart.FileHeader, error) {
 	return r.Unwrap().FormFile(key)
 }
 
+// FormFileHeaders method returns the multipart file headers for the given
+// form key otherwise empty slice. It parses the multipart form if it's not
+// parsed yet. File headers provides metadata (filename, size and header) to
+// validate the uploads before saving it, without opening the file.
+func (r *Request) FormFileHeaders(key string) []*multipart.FileHeader {
+	if r.Unwrap().MultipartForm == nil {
+		_ = r.Unwrap().ParseMultipartForm(defaultMaxMemory)
+	}
+	if r.Unwrap().MultipartForm != nil {
+		if fhs, found := r.Unwrap().MultipartForm.File[key]; found {
+			return fhs
+		}
+	}
+	return []*multipart.FileHeader{}
+}
+
+// MergeParams method merges the parameters of given request into current
+// request, it's handy for internal forward, sub-request and route aliasing.
+// Form of current request is parsed if it's not parsed yet, whereas only the
+// already parsed form and files of given request are merged, its body is not
+// read. Merge rules-
+//
+//  - Path: given request URL parameter wins on key conflict, refer to
+//    method `URLParams.Merge`
+//
+//  - Query: given request values are appended to URL query params
+//
+//  - Form: given request values are appended to form values, it's URL query
+//    params if given request form is not parsed
+//
+//  - File: given request multipart file headers are appended per key
+//
+// Given request is not modified and file headers are shared with it. It's
+// no-op if given request is nil.
+func (r *Request) MergeParams(other *Request) {
+	if other == nil {
+		return
+	}
+
+	raw, oraw := r.Unwrap(), other.Unwrap()
+	if raw.Form == nil {
+		_ = raw.ParseMultipartForm(defaultMaxMemory)
+	}
+
+	r.URLParams = r.URLParams.Merge(other.URLParams)
+
+	query, oquery := raw.URL.Query(), oraw.URL.Query()
+	appendValues(query, oquery)
+	raw.URL.RawQuery = query.Encode()
+
+	if raw.Form == nil {
+		raw.Form = make(url.Values)
+	}
+	if oraw.Form == nil {
+		appendValues(raw.Form, oquery)
+	} else {
+		appendValues(raw.Form, oraw.Form)
+	}
+	if len(oraw.PostForm) > 0 {
+		if raw.PostForm == nil {
+			raw.PostForm = make(url.Values)
+		}
+		appendValues(raw.PostForm, oraw.PostForm)
+	}
+
+	if oraw.MultipartForm != nil && len(oraw.MultipartForm.File) > 0 {
+		if raw.MultipartForm == nil {
+			raw.MultipartForm = &multipart.Form{Value: make(map[string][]string)}
+		}
+		if raw.MultipartForm.File == nil {
+			raw.MultipartForm.File = make(map[string][]*multipart.FileHeader)
+		}
+		for k, fhs := range oraw.MultipartForm.File {
+			raw.MultipartForm.File[k] = append(raw.MultipartForm.File[k], fhs...)
+		}
+	}
+}
+
 // Body method returns the HTTP request body.
 func (r *Request) Body() io.ReadCloser {
 	return r.Unwrap().Body
 }
 
+// LimitBody method limits the HTTP request body to given bytes, reading
+// beyond the limit returns error `ErrBodyTooLarge`, so that the error can
+// be mapped to `413 Request Entity Too Large`. It's the single limit for all
+// body reading paths, i.e. `BodyBytes`, form parsing and body bind. Given
+// response writer is optional, it's used to close the connection after the
+// response, same as `http.MaxBytesReader`.
+func (r *Request) LimitBody(w http.ResponseWriter, n int64) {
+	body := r.Unwrap().Body
+	if body == nil {
+		return
+	}
+	r.Unwrap().Body = &limitedBody{
+		r:     io.LimitReader(body, n+1),
+		body:  body,
+		w:     w,
+		limit: n,
+	}
+}
+
+// BodyBytes method reads the HTTP request body fully and returns the bytes.
+// It returns error `ErrBodyTooLarge` if body exceeds the limit, refer to
+// `Request.LimitBody`; no partial bytes are returned on error.
+func (r *Request) BodyBytes() ([]byte, error) {
+	if r.Unwrap().Body == nil {
+		return []byte{}, nil
+	}
+	b, err := ioutil.ReadAll(r.Unwrap().Body)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// TeeBody method wraps the HTTP request body, so that the bytes read by the
+// handler are also written into given writer, e.g. audit log, hash
+// accumulator. It streams without buffering the whole body in memory.
+//
+// Closing the request body flushes the writer if it implements
+// `Flush() error` (e.g. `bufio.Writer`).
+func (r *Request) TeeBody(w io.Writer) {
+	if w == nil || r.Unwrap().Body == nil {
+		return
+	}
+	body := r.Unwrap().Body
+	r.Unwrap().Body = &teeReadCloser{Reader: io.TeeReader(body, w), body: body, w: w}
+}
+
+// LooksLikeJSON method returns true if the HTTP request body starts with `{`
+// or `[` after the leading whitespace otherwise false. Body is peeked not
+// consumed, handler or bind reads the body from the beginning. It returns
+// false for empty body. It's a cheap check to reject the malformed payload,
+// e.g. HTML error page, before bind.
+//
+// Peek is capped at first 4 KB of the body, it returns false if the body
+// has only whitespace within that.
+func (r *Request) LooksLikeJSON() bool {
+	raw := r.Unwrap()
+	if raw.Body == nil || raw.Body == http.NoBody {
+		return false
+	}
+	pb, ok := raw.Body.(*peekReadCloser)
+	if !ok {
+		pb = &peekReadCloser{Reader: bufio.NewReaderSize(raw.Body, maxJSONPeekSize), body: raw.Body}
+		raw.Body = pb
+	}
+	for n := 1; n <= maxJSONPeekSize; n++ {
+		b, _ := pb.Peek(n)
+		if len(b) < n {
+			return false
+		}
+		switch b[n-1] {
+		case ' ', '\t', '\r', '\n':
+		case '{', '[':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// ReplaceBody method replaces the HTTP request body with given reader and
+// content length, e.g. middleware decrypts or decompresses the body for
+// downstream handlers. Header `Content-Length` is updated accordingly, `-1`
+// means unknown length and header is removed. Parsed form values are
+// cleared, so that those get parsed from the replaced body.
+func (r *Request) ReplaceBody(body io.ReadCloser, contentLength int64) {
+	if body == nil {
+		body = http.NoBody
+		contentLength = 0
+	}
+	raw := r.Unwrap()
+	raw.Body = body
+	raw.ContentLength = contentLength
+	if contentLength < 0 {
+		raw.Header.Del(HeaderContentLength)
+	} else {
+		raw.Header.Set(HeaderContentLength, strconv.FormatInt(contentLength, 10))
+	}
+	raw.Form = nil
+	raw.PostForm = nil
+	raw.MultipartForm = nil
+}
+
 // Unwrap method returns the underlying *http.Request instance of Go HTTP server,
 // direct interaction with raw object is not encouraged. Use it appropriately.
 func (r *Request) Unwrap() *http.Request {
@@ -280,6 +1350,58 @@ func (r *Request) SaveFile(key, dstFile string) (int64, error) {
 	return saveFile(uploadedFile, dstFile)
 }
 
+// SaveResult struct holds the outcome of an uploaded multipart file saved
+// by method `Request.SaveFilesResult`.
+type SaveResult struct {
+	Filename string
+	Size     int64
+	Err      error
+}
+
+// SaveFilesResult method saves all the uploaded multipart files for given
+// key from the HTTP request into given destination directory using its
+// filename, and returns the outcome of each file in upload order. Filename
+// is sanitized to its base name, so that it can't escape the destination
+// directory. Existing files are not overwritten.
+//
+// 	For e.g.:
+// 		for _, result := range ctx.Req.SaveFilesResult("images", "/data/uploads") {
+// 			if result.Err != nil {
+// 				ctx.Log().Errorf("upload %s failed: %s", result.Filename, result.Err)
+// 			}
+// 		}
+func (r *Request) SaveFilesResult(key, dstPath string) []SaveResult {
+	if ess.IsStrEmpty(dstPath) || ess.IsStrEmpty(key) {
+		return []SaveResult{{Err: errors.New("ahttp: key or dstPath is empty")}}
+	}
+
+	if !ess.IsDir(dstPath) {
+		return []SaveResult{{Err: errors.New("ahttp: dstPath should be a directory")}}
+	}
+
+	fileHeaders := r.FormFileHeaders(key)
+	results := make([]SaveResult, 0, len(fileHeaders))
+	for _, fh := range fileHeaders {
+		result := SaveResult{Filename: filepath.Base(filepath.Clean("/" + fh.Filename))}
+		if result.Filename == "/" || result.Filename == "." {
+			result.Err = fmt.Errorf("ahttp: invalid filename '%s'", fh.Filename)
+			results = append(results, result)
+			continue
+		}
+
+		uploadedFile, err := fh.Open()
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		result.Size, result.Err = saveFile(uploadedFile, filepath.Join(dstPath, result.Filename))
+		ess.CloseQuietly(uploadedFile)
+		results = append(results, result)
+	}
+	return results
+}
+
 // Reset method resets request instance for reuse.
 func (r *Request) Reset() {
 	r.Scheme = ""
@@ -295,7 +1417,99 @@ func (r *Request) Reset() {
 	r.locale = nil
 	r.contentType = nil
 	r.acceptContentType = nil
+	r.userAgent = nil
+	r.routePattern = ""
 	r.acceptEncoding = nil
+	r.headerLimitErr = nil
+}
+
+// filterQuery method returns the URL query params which are in the given
+// keys when keep is true, otherwise which are not in the given keys.
+func (r *Request) filterQuery(keys []string, keep bool) url.Values {
+	values := make(url.Values)
+	for k, v := range r.URL().Query() {
+		found := false
+		for _, key := range keys {
+			if k == key {
+				found = true
+				break
+			}
+		}
+		if found == keep {
+			values[k] = v
+		}
+	}
+	return values
+}
+
+// parseOrigin method returns the lowercase scheme and host of given origin
+// or URL value, ok is false if it's not an absolute URL.
+func parseOrigin(value string) (string, string, bool) {
+	if len(value) == 0 || strings.EqualFold(value, "null") {
+		return "", "", false
+	}
+	u, err := url.Parse(value)
+	if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return "", "", false
+	}
+	return strings.ToLower(u.Scheme), strings.ToLower(u.Host), true
+}
+
+// originAllowed method returns true if given scheme and host matches one of
+// the allowed origins, refer to method `Request.OriginMatches`.
+func originAllowed(scheme, host string, allowed []string) bool {
+	for _, a := range allowed {
+		a = strings.ToLower(strings.TrimSpace(a))
+		if a == "*" {
+			return true
+		}
+
+		pattern := a
+		if idx := strings.Index(a, "://"); idx > 0 {
+			if a[:idx] != scheme {
+				continue
+			}
+			pattern = a[idx+3:]
+		} else if !strings.HasPrefix(a, "*.") {
+			continue
+		}
+
+		if strings.HasPrefix(pattern, "*.") {
+			suffix := pattern[1:]
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+		} else if pattern == host {
+			return true
+		}
+	}
+	return false
+}
+
+// stripDefaultPort method removes the default port of given scheme from host,
+// e.g. `example.com:443` => `example.com` for `https`.
+func stripDefaultPort(scheme, host string) string {
+	switch {
+	case scheme == "http" && strings.HasSuffix(host, ":80"):
+		return strings.TrimSuffix(host, ":80")
+	case scheme == "https" && strings.HasSuffix(host, ":443"):
+		return strings.TrimSuffix(host, ":443")
+	}
+	return host
+}
+
+func (r *Request) modifyQuery(fn func(q url.Values)) *url.URL {
+	u := *r.URL()
+	u.Scheme, u.Host = r.Scheme, r.Host
+	if u.User != nil {
+		user := *u.User
+		u.User = &user
+	}
+	q := u.Query()
+	fn(q)
+	u.RawQuery = q.Encode()
+	u.ForceQuery = false
+	return &u
 }
 
 func (r *Request) cleanupMutlipart() {
@@ -336,10 +1550,182 @@ func (u URLParams) ToMap() map[string]string {
 	return ps
 }
 
+// Merge method returns new URL parameters which combines current and given
+// parameters, it's handy for internal forward and route aliasing.
+//
+// Conflict resolution: given parameter value wins on the key conflict.
+// Parameters order is preserved, new keys appended at the end.
+func (u URLParams) Merge(other URLParams) URLParams {
+	ps := make(URLParams, len(u), len(u)+len(other))
+	copy(ps, u)
+	for _, op := range other {
+		found := false
+		for i := range ps {
+			if ps[i].Key == op.Key {
+				ps[i].Value = op.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			ps = append(ps, op)
+		}
+	}
+	return ps
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Unexported methods
 //___________________________________
 
+type flusher interface {
+	Flush() error
+}
+
+// limitedBody reads at most one byte beyond the limit from the body and
+// returns `ErrBodyTooLarge` once more than limit bytes are read.
+type limitedBody struct {
+	r     io.Reader
+	body  io.ReadCloser
+	w     http.ResponseWriter
+	limit int64
+	read  int64
+	err   error
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	n, err := l.r.Read(p)
+	l.read += int64(n)
+	if l.read > l.limit {
+		n -= int(l.read - l.limit)
+		l.read = l.limit
+		l.err = ErrBodyTooLarge
+		if l.w != nil {
+			l.w.Header().Set(HeaderConnection, "close")
+		}
+		return n, l.err
+	}
+	return n, err
+}
+
+func (l *limitedBody) Close() error {
+	return l.body.Close()
+}
+
+// teeReadCloser writes the bytes read from body into writer and flushes
+// the writer on close.
+type teeReadCloser struct {
+	io.Reader
+	body io.ReadCloser
+	w    io.Writer
+}
+
+func (t *teeReadCloser) Close() error {
+	err := t.body.Close()
+	if f, ok := t.w.(flusher); ok {
+		if ferr := f.Flush(); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
+
+// peekReadCloser buffers the body, so that it can be peeked without
+// consuming, refer to `Request.LooksLikeJSON`.
+type peekReadCloser struct {
+	*bufio.Reader
+	body io.ReadCloser
+}
+
+func (p *peekReadCloser) Close() error {
+	return p.body.Close()
+}
+
+// appendValues appends the values of src into dst per key.
+func appendValues(dst, src url.Values) {
+	for k, v := range src {
+		dst[k] = append(dst[k], v...)
+	}
+}
+
+// etagWeakMatch method reports whether given etag matches any of the
+// comma-separated entity tags of header `If-None-Match` using weak comparison.
+func etagWeakMatch(header, etag string) bool {
+	etag = normalizeETag(etag)
+	if len(etag) == 0 {
+		return false
+	}
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || normalizeETag(v) == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeETag method strips the weak indicator `W/` and quotes the entity
+// tag if it's not quoted.
+func normalizeETag(etag string) string {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	if len(etag) == 0 {
+		return ""
+	}
+	if !strings.HasPrefix(etag, `"`) {
+		etag = `"` + etag + `"`
+	}
+	return etag
+}
+
+// headerHasToken method reports whether comma-separated values of given
+// header contains the token in case-insensitive.
+func headerHasToken(hdr http.Header, key, token string) bool {
+	for _, v := range hdr[http.CanonicalHeaderKey(key)] {
+		for _, t := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// uaContainsAny method reports whether lowercase user agent contains any one
+// of the given signatures in case-insensitive.
+func uaContainsAny(ua string, signatures []string) bool {
+	for _, sig := range signatures {
+		if len(sig) > 0 && strings.Contains(ua, strings.ToLower(sig)) {
+			return true
+		}
+	}
+	return false
+}
+
+// lowerPercentEncoding method lowercases the hex digits of percent-encoding.
+func lowerPercentEncoding(s string) string {
+	if strings.IndexByte(s, '%') == -1 {
+		return s
+	}
+	b := []byte(s)
+	for i := 0; i < len(b)-2; i++ {
+		if b[i] == '%' {
+			b[i+1], b[i+2] = toLowerHex(b[i+1]), toLowerHex(b[i+2])
+			i += 2
+		}
+	}
+	return string(b)
+}
+
+func toLowerHex(c byte) byte {
+	if c >= 'A' && c <= 'F' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
 func saveFile(r io.Reader, destFile string) (int64, error) {
 	f, err := os.OpenFile(destFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
 	if err != nil {
@@ -349,3 +1735,29 @@ func saveFile(r io.Reader, destFile string) (int64, error) {
 
 	return io.Copy(f, r)
 }
+
+// offerQuality method returns the quality factor of most specific media
+// range matching the given offered content type, zero if none matches.
+func offerQuality(specs AcceptSpecs, offer string) float32 {
+	mime := strings.ToLower(strings.TrimSpace(strings.SplitN(offer, ";", 2)[0]))
+	typ := strings.SplitN(mime, "/", 2)[0]
+	q, specificity := float32(0), 0
+	for _, spec := range specs {
+		value := strings.ToLower(strings.TrimSpace(spec.Value))
+		var s int
+		switch value {
+		case mime:
+			s = 3
+		case typ + "/*":
+			s = 2
+		case "*/*", "*":
+			s = 1
+		default:
+			continue
+		}
+		if s > specificity {
+			q, specificity = spec.Q, s
+		}
+	}
+	return q
+}
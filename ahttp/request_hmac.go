@@ -0,0 +1,79 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+var (
+	// MaxHMACBodySize is maximum request body size (default 1MB) allowed by
+	// method `Request.VerifyHMAC`.
+	MaxHMACBodySize int64 = 1 << 20
+
+	// ErrHMACBodyTooLarge returned when request body exceeds
+	// `MaxHMACBodySize` on `Request.VerifyHMAC`.
+	ErrHMACBodyTooLarge = errors.New("ahttp: request body too large to verify HMAC")
+)
+
+// VerifyHMAC method verifies the request body signature sent in the given
+// header, e.g. webhook payload signed by GitHub. It computes the HMAC of
+// raw request body using named algorithm `sha1` or `sha256` and given secret,
+// then compares it with hex encoded header value in constant time. Header
+// value prefix convention `<algo>=` is supported, e.g. `sha256=8f4c...`.
+//
+// 	For e.g.:
+// 		ok, err := req.VerifyHMAC("X-Hub-Signature-256", "sha256", secret)
+//
+// Request body is read into memory up to `MaxHMACBodySize` and it remains
+// readable for the request handler after verify. It returns error for
+// unsupported algorithm and `ErrHMACBodyTooLarge` if body exceeds the limit.
+func (r *Request) VerifyHMAC(headerName, algo string, secret []byte) (bool, error) {
+	algo = strings.ToLower(strings.TrimSpace(algo))
+	var hashFn func() hash.Hash
+	switch algo {
+	case "sha1":
+		hashFn = sha1.New
+	case "sha256":
+		hashFn = sha256.New
+	default:
+		return false, fmt.Errorf("ahttp: unsupported HMAC algorithm '%s'", algo)
+	}
+
+	value := strings.TrimSpace(r.Header.Get(headerName))
+	if len(value) > len(algo) && strings.EqualFold(value[:len(algo)+1], algo+"=") {
+		value = value[len(algo)+1:]
+	}
+	sig, err := hex.DecodeString(value)
+	if err != nil || len(sig) == 0 {
+		return false, nil
+	}
+
+	mac := hmac.New(hashFn, secret)
+	if body := r.Unwrap().Body; body != nil && body != http.NoBody {
+		b, err := ioutil.ReadAll(io.LimitReader(body, MaxHMACBodySize+1))
+		r.Unwrap().Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(b), body), body: body}
+		if err != nil {
+			return false, err
+		}
+		if int64(len(b)) > MaxHMACBodySize {
+			return false, ErrHMACBodyTooLarge
+		}
+		_, _ = mac.Write(b)
+	}
+
+	return hmac.Equal(sig, mac.Sum(nil)), nil
+}
@@ -0,0 +1,92 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPRequestVerifyHMAC(t *testing.T) {
+	secret := []byte("webhook-secret")
+	payload := `{"action":"opened","number":1}`
+	signWith := func(fn func() hash.Hash, key []byte, body string) string {
+		mac := hmac.New(fn, key)
+		_, _ = mac.Write([]byte(body))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	sign := func(fn func() hash.Hash, body string) string {
+		return signWith(fn, secret, body)
+	}
+
+	testcases := []struct {
+		label    string
+		header   string
+		algo     string
+		valid    bool
+		errorMsg string
+	}{
+		{"sha256 with prefix", "sha256=" + sign(sha256.New, payload), "sha256", true, ""},
+		{"sha256 without prefix", sign(sha256.New, payload), "SHA256", true, ""},
+		{"sha1 with prefix", "sha1=" + sign(sha1.New, payload), "sha1", true, ""},
+		{"signature mismatch", "sha256=" + sign(sha256.New, payload+" "), "sha256", false, ""},
+		{"algorithm mismatch", "sha1=" + sign(sha256.New, payload), "sha1", false, ""},
+		{"secret mismatch", "sha256=" + signWith(sha256.New, []byte("other-secret"), payload), "sha256", false, ""},
+		{"not hex", "sha256=not-hex", "sha256", false, ""},
+		{"header missing", "", "sha256", false, ""},
+		{"unsupported algorithm", "md5=abcd", "md5", false, "ahttp: unsupported HMAC algorithm 'md5'"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			req := httptest.NewRequest(MethodPost, "http://localhost:8080/webhook", strings.NewReader(payload))
+			if len(tc.header) > 0 {
+				req.Header.Set("X-Hub-Signature", tc.header)
+			}
+			aahReq := AcquireRequest(req)
+			defer ReleaseRequest(aahReq)
+
+			ok, err := aahReq.VerifyHMAC("X-Hub-Signature", tc.algo, secret)
+			assert.Equal(t, tc.valid, ok)
+			if len(tc.errorMsg) > 0 {
+				assert.Equal(t, tc.errorMsg, err.Error())
+				return
+			}
+			assert.Nil(t, err)
+
+			// body remains readable for the handler
+			if len(tc.header) > 0 {
+				b, _ := ioutil.ReadAll(aahReq.Body())
+				assert.Equal(t, payload, string(b))
+				assert.Nil(t, aahReq.Body().Close())
+			}
+		})
+	}
+
+	// body beyond limit
+	defer func(size int64) { MaxHMACBodySize = size }(MaxHMACBodySize)
+	MaxHMACBodySize = int64(len(payload) - 1)
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/webhook", strings.NewReader(payload))
+	req.Header.Set("X-Hub-Signature", "sha256="+sign(sha256.New, payload))
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	ok, err := aahReq.VerifyHMAC("X-Hub-Signature", "sha256", secret)
+	assert.False(t, ok)
+	assert.Equal(t, ErrHMACBodyTooLarge, err)
+
+	// body remains intact for the handler
+	b, _ := ioutil.ReadAll(aahReq.Body())
+	assert.Equal(t, payload, string(b))
+}
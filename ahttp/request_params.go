@@ -0,0 +1,332 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// NormOptions struct holds the normalization options of param value, refer
+// to methods `Request.QueryValueNorm` and `Request.FormValueNorm`. Options
+// are applied in the order of collapse, trim and lowercase.
+type NormOptions struct {
+	// Trim removes the leading and trailing whitespace.
+	Trim bool
+
+	// Lower converts the value to lowercase.
+	Lower bool
+
+	// CollapseSpace replaces each run of whitespace with single space.
+	CollapseSpace bool
+}
+
+// DefaultTimeLayout is the default time layout used by methods
+// `Request.QueryTime` and `Request.FormTime` when layout is empty. It can be
+// overridden by application.
+var DefaultTimeLayout = time.RFC3339
+
+// QueryIntArrayValue method returns array value for given URL query param key
+// parsed as int, e.g. `?id=1&id=2&id=3`. It returns error identifying the first
+// invalid element and its index. Empty slice is returned if key is missing.
+func (r *Request) QueryIntArrayValue(key string) ([]int, error) {
+	return intArray("query", key, r.QueryArrayValue(key))
+}
+
+// QueryInt64ArrayValue method returns array value for given URL query param
+// key parsed as int64. Refer to `Request.QueryIntArrayValue`.
+func (r *Request) QueryInt64ArrayValue(key string) ([]int64, error) {
+	return int64Array("query", key, r.QueryArrayValue(key))
+}
+
+// QueryFloat64ArrayValue method returns array value for given URL query param
+// key parsed as float64. Refer to `Request.QueryIntArrayValue`.
+func (r *Request) QueryFloat64ArrayValue(key string) ([]float64, error) {
+	return float64Array("query", key, r.QueryArrayValue(key))
+}
+
+// FormIntArrayValue method returns array value for given form key parsed as
+// int. It returns error identifying the first invalid element and its index.
+// Empty slice is returned if key is missing.
+func (r *Request) FormIntArrayValue(key string) ([]int, error) {
+	return intArray("form", key, r.formArrayValue(key))
+}
+
+// FormInt64ArrayValue method returns array value for given form key parsed as
+// int64. Refer to `Request.FormIntArrayValue`.
+func (r *Request) FormInt64ArrayValue(key string) ([]int64, error) {
+	return int64Array("form", key, r.formArrayValue(key))
+}
+
+// FormFloat64ArrayValue method returns array value for given form key parsed
+// as float64. Refer to `Request.FormIntArrayValue`.
+func (r *Request) FormFloat64ArrayValue(key string) ([]float64, error) {
+	return float64Array("form", key, r.formArrayValue(key))
+}
+
+// QueryCSV method returns the comma-separated value of given URL query param
+// key as slice, e.g. `?tags=a,b,c`. Elements are trimmed and empty elements
+// are dropped. Empty slice is returned if key is missing.
+func (r *Request) QueryCSV(key string) []string {
+	return r.QueryCSVSep(key, ",")
+}
+
+// QueryCSVSep method returns the value of given URL query param key split
+// by given separator, e.g. `?tags=a|b|c`. Refer to `Request.QueryCSV`.
+func (r *Request) QueryCSVSep(key, sep string) []string {
+	return splitValue(r.QueryValue(key), sep)
+}
+
+// FormCSV method returns the comma-separated value of given form key as
+// slice. Elements are trimmed and empty elements are dropped. Empty slice is
+// returned if key is missing.
+func (r *Request) FormCSV(key string) []string {
+	return r.FormCSVSep(key, ",")
+}
+
+// FormCSVSep method returns the value of given form key split by given
+// separator. Refer to `Request.FormCSV`.
+func (r *Request) FormCSVSep(key, sep string) []string {
+	return splitValue(r.FormValue(key), sep)
+}
+
+// QueryTime method returns the value of given URL query param key parsed as
+// time with given layout, e.g. `?since=2023-01-01T00:00:00Z`. Empty layout
+// uses `ahttp.DefaultTimeLayout`. It returns zero time and error if value is
+// missing or unparseable.
+func (r *Request) QueryTime(key, layout string) (time.Time, error) {
+	return parseTime("query", key, r.QueryValue(key), layout)
+}
+
+// QueryTimeRFC3339 method returns the value of given URL query param key
+// parsed as RFC3339 time. Refer to `Request.QueryTime`.
+func (r *Request) QueryTimeRFC3339(key string) (time.Time, error) {
+	return r.QueryTime(key, time.RFC3339)
+}
+
+// FormTime method returns the value of given form key parsed as time with
+// given layout. Empty layout uses `ahttp.DefaultTimeLayout`. It returns zero
+// time and error if value is missing or unparseable.
+func (r *Request) FormTime(key, layout string) (time.Time, error) {
+	var value string
+	if values := r.formArrayValue(key); len(values) > 0 {
+		value = values[0]
+	}
+	return parseTime("form", key, value, layout)
+}
+
+// FormTimeRFC3339 method returns the value of given form key parsed as
+// RFC3339 time. Refer to `Request.FormTime`.
+func (r *Request) FormTimeRFC3339(key string) (time.Time, error) {
+	return r.FormTime(key, time.RFC3339)
+}
+
+// QueryValueTrimmed method returns the value of given URL query param key
+// with leading and trailing whitespace removed.
+func (r *Request) QueryValueTrimmed(key string) string {
+	return r.QueryValueNorm(key, NormOptions{Trim: true})
+}
+
+// QueryValueLower method returns the trimmed and lowercase value of given
+// URL query param key, e.g. `?sort= Name ` => `name`.
+func (r *Request) QueryValueLower(key string) string {
+	return r.QueryValueNorm(key, NormOptions{Trim: true, Lower: true})
+}
+
+// QueryValueNorm method returns the value of given URL query param key
+// normalized as per given options.
+//
+// 	For e.g.:
+// 		// ?q=%20Go%20%20Web%09Framework%20
+// 		r.QueryValueNorm("q", ahttp.NormOptions{Trim: true, Lower: true, CollapseSpace: true})
+// 		// go web framework
+func (r *Request) QueryValueNorm(key string, opts NormOptions) string {
+	return normalizeValue(r.QueryValue(key), opts)
+}
+
+// FormValueTrimmed method returns the value of given form key with leading
+// and trailing whitespace removed.
+func (r *Request) FormValueTrimmed(key string) string {
+	return r.FormValueNorm(key, NormOptions{Trim: true})
+}
+
+// FormValueLower method returns the trimmed and lowercase value of given
+// form key.
+func (r *Request) FormValueLower(key string) string {
+	return r.FormValueNorm(key, NormOptions{Trim: true, Lower: true})
+}
+
+// FormValueNorm method returns the value of given form key normalized as per
+// given options. Refer to `Request.QueryValueNorm`.
+func (r *Request) FormValueNorm(key string, opts NormOptions) string {
+	return normalizeValue(r.FormValue(key), opts)
+}
+
+// Pagination method returns the page number, page size and offset from URL
+// query params `page`, `per_page` (alias `limit`) and `offset`. It never
+// fails, missing or invalid values fall back to defaults-
+//
+//  - size defaults to given defaultSize and clamped to [1, maxSize]
+//
+//  - page defaults to 1, offset is computed as (page-1)*size
+//
+//  - param `offset` takes precedence over `page`, page is derived from it
+//
+// 	For e.g.:
+// 		// ?page=3&per_page=500
+// 		page, size, offset := r.Pagination(20, 100)
+// 		// page=3, size=100, offset=200
+func (r *Request) Pagination(defaultSize, maxSize int) (page, size, offset int) {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+	size = clampInt(defaultSize, 1, maxSize)
+
+	query := r.URL().Query()
+	sizeValue := query.Get("per_page")
+	if len(sizeValue) == 0 {
+		sizeValue = query.Get("limit")
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(sizeValue)); err == nil {
+		size = clampInt(n, 1, maxSize)
+	}
+
+	maxPage := math.MaxInt32/size + 1
+	if n, err := strconv.Atoi(strings.TrimSpace(query.Get("offset"))); err == nil && n >= 0 {
+		page = clampInt(n/size+1, 1, maxPage)
+		return page, size, (page-1)*size + n%size
+	}
+
+	page = 1
+	if n, err := strconv.Atoi(strings.TrimSpace(query.Get("page"))); err == nil {
+		page = clampInt(n, 1, maxPage)
+	}
+	return page, size, (page - 1) * size
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// formArrayValue method parses the form if it's not parsed yet, then returns
+// the array value for given form key.
+func (r *Request) formArrayValue(key string) []string {
+	if r.Unwrap().Form == nil {
+		_ = r.Unwrap().ParseMultipartForm(defaultMaxMemory)
+	}
+	return r.FormArrayValue(key)
+}
+
+func intArray(kind, key string, values []string) ([]int, error) {
+	result := make([]int, 0, len(values))
+	for i, v := range values {
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, arrayValueError(kind, key, "int", v, i)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+func int64Array(kind, key string, values []string) ([]int64, error) {
+	result := make([]int64, 0, len(values))
+	for i, v := range values {
+		n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return nil, arrayValueError(kind, key, "int64", v, i)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+func float64Array(kind, key string, values []string) ([]float64, error) {
+	result := make([]float64, 0, len(values))
+	for i, v := range values {
+		n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, arrayValueError(kind, key, "float64", v, i)
+		}
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+func arrayValueError(kind, key, typ, value string, index int) error {
+	return fmt.Errorf("ahttp: %s param '%s' has invalid %s value '%s' at index %d", kind, key, typ, value, index)
+}
+
+func parseTime(kind, key, value, layout string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if len(value) == 0 {
+		return time.Time{}, fmt.Errorf("ahttp: %s param '%s' is missing", kind, key)
+	}
+	if len(layout) == 0 {
+		layout = DefaultTimeLayout
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("ahttp: %s param '%s' has invalid time value '%s' for layout '%s'",
+			kind, key, value, layout)
+	}
+	return t, nil
+}
+
+// splitValue method splits the value by given separator (default is comma),
+// trims the elements and drops empty ones.
+func splitValue(value, sep string) []string {
+	if len(sep) == 0 {
+		sep = ","
+	}
+	values := make([]string, 0)
+	for _, v := range strings.Split(value, sep) {
+		if v = strings.TrimSpace(v); len(v) > 0 {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// normalizeValue method normalizes the value as per given options.
+func normalizeValue(value string, opts NormOptions) string {
+	if opts.CollapseSpace {
+		var sb strings.Builder
+		inSpace := false
+		for _, c := range value {
+			if unicode.IsSpace(c) {
+				if !inSpace {
+					sb.WriteByte(' ')
+				}
+				inSpace = true
+				continue
+			}
+			inSpace = false
+			sb.WriteRune(c)
+		}
+		value = sb.String()
+	}
+	if opts.Trim {
+		value = strings.TrimSpace(value)
+	}
+	if opts.Lower {
+		value = strings.ToLower(value)
+	}
+	return value
+}
+
+func clampInt(n, min, max int) int {
+	if n < min {
+		return min
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
@@ -0,0 +1,246 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPRequestQueryArrayValues(t *testing.T) {
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet,
+		"http://localhost:8080/users?id=1&id=2&id=%203&big=9007199254740993&price=1.5&price=2&bad=1&bad=x&bad=3", nil))
+	defer ReleaseRequest(aahReq)
+
+	ids, err := aahReq.QueryIntArrayValue("id")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3}, ids)
+
+	bigs, err := aahReq.QueryInt64ArrayValue("big")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{9007199254740993}, bigs)
+
+	prices, err := aahReq.QueryFloat64ArrayValue("price")
+	assert.Nil(t, err)
+	assert.Equal(t, []float64{1.5, 2}, prices)
+
+	// missing key
+	ids, err = aahReq.QueryIntArrayValue("not-exists")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{}, ids)
+
+	// partially invalid
+	ids, err = aahReq.QueryIntArrayValue("bad")
+	assert.Nil(t, ids)
+	assert.Equal(t, "ahttp: query param 'bad' has invalid int value 'x' at index 1", err.Error())
+
+	_, err = aahReq.QueryInt64ArrayValue("price")
+	assert.Equal(t, "ahttp: query param 'price' has invalid int64 value '1.5' at index 0", err.Error())
+
+	_, err = aahReq.QueryFloat64ArrayValue("bad")
+	assert.Equal(t, "ahttp: query param 'bad' has invalid float64 value 'x' at index 1", err.Error())
+}
+
+func TestHTTPRequestFormArrayValues(t *testing.T) {
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/users",
+		strings.NewReader("id=10&id=20&ratio=0.25&ratio=abc&count=5000000000"))
+	req.Header.Set(HeaderContentType, ContentTypeForm.String())
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	// form not parsed yet
+	ids, err := aahReq.FormIntArrayValue("id")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{10, 20}, ids)
+
+	counts, err := aahReq.FormInt64ArrayValue("count")
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{5000000000}, counts)
+
+	ratios, err := aahReq.FormFloat64ArrayValue("ratio")
+	assert.Nil(t, ratios)
+	assert.Equal(t, "ahttp: form param 'ratio' has invalid float64 value 'abc' at index 1", err.Error())
+
+	ids, err = aahReq.FormIntArrayValue("not-exists")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{}, ids)
+}
+
+func TestHTTPRequestCSVValues(t *testing.T) {
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet,
+		"http://localhost:8080/posts?tags=go,+web+,,aah,&single=one&empty=&ids=1|2||3", nil))
+	defer ReleaseRequest(aahReq)
+
+	testcases := []struct {
+		label, key, sep string
+		expected        []string
+	}{
+		{label: "multi", key: "tags", expected: []string{"go", "web", "aah"}},
+		{label: "single", key: "single", expected: []string{"one"}},
+		{label: "empty", key: "empty", expected: []string{}},
+		{label: "missing", key: "not-exists", expected: []string{}},
+		{label: "custom separator", key: "ids", sep: "|", expected: []string{"1", "2", "3"}},
+		{label: "no separator match", key: "ids", sep: ";", expected: []string{"1|2||3"}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			if len(tc.sep) == 0 {
+				assert.Equal(t, tc.expected, aahReq.QueryCSV(tc.key))
+			} else {
+				assert.Equal(t, tc.expected, aahReq.QueryCSVSep(tc.key, tc.sep))
+			}
+		})
+	}
+
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/posts",
+		strings.NewReader("tags=go%2C%20aah&roles=admin%3Beditor"))
+	req.Header.Set(HeaderContentType, ContentTypeForm.String())
+	formReq := AcquireRequest(req)
+	defer ReleaseRequest(formReq)
+
+	assert.Equal(t, []string{"go", "aah"}, formReq.FormCSV("tags"))
+	assert.Equal(t, []string{"admin", "editor"}, formReq.FormCSVSep("roles", ";"))
+	assert.Equal(t, []string{}, formReq.FormCSV("not-exists"))
+}
+
+func TestHTTPRequestTimeValues(t *testing.T) {
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet,
+		"http://localhost:8080/events?since=2023-01-01T10:30:00Z&day=2023-02-15&bad=yesterday&offset=2023-01-01T10:30:00%2B05:30", nil))
+	defer ReleaseRequest(aahReq)
+
+	since, err := aahReq.QueryTimeRFC3339("since")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 1, 1, 10, 30, 0, 0, time.UTC), since)
+
+	offset, err := aahReq.QueryTime("offset", "")
+	assert.Nil(t, err)
+	assert.Equal(t, "2023-01-01 05:00:00 +0000 UTC", offset.UTC().String())
+
+	day, err := aahReq.QueryTime("day", "2006-01-02")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 2, 15, 0, 0, 0, 0, time.UTC), day)
+
+	// invalid value
+	v, err := aahReq.QueryTimeRFC3339("bad")
+	assert.True(t, v.IsZero())
+	assert.Equal(t, "ahttp: query param 'bad' has invalid time value 'yesterday' for layout '2006-01-02T15:04:05Z07:00'", err.Error())
+
+	_, err = aahReq.QueryTime("since", "2006-01-02")
+	assert.Equal(t, "ahttp: query param 'since' has invalid time value '2023-01-01T10:30:00Z' for layout '2006-01-02'", err.Error())
+
+	// missing value
+	v, err = aahReq.QueryTimeRFC3339("not-exists")
+	assert.True(t, v.IsZero())
+	assert.Equal(t, "ahttp: query param 'not-exists' is missing", err.Error())
+
+	// overridden default layout
+	DefaultTimeLayout = "2006-01-02"
+	day, err = aahReq.QueryTime("day", "")
+	DefaultTimeLayout = time.RFC3339
+	assert.Nil(t, err)
+	assert.Equal(t, 15, day.Day())
+
+	// form
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/events",
+		strings.NewReader("from=2023-03-01T00:00:00Z&until=01/04/2023"))
+	req.Header.Set(HeaderContentType, ContentTypeForm.String())
+	formReq := AcquireRequest(req)
+	defer ReleaseRequest(formReq)
+
+	from, err := formReq.FormTimeRFC3339("from")
+	assert.Nil(t, err)
+	assert.Equal(t, time.March, from.Month())
+
+	until, err := formReq.FormTime("until", "02/01/2006")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2023, 4, 1, 0, 0, 0, 0, time.UTC), until)
+
+	_, err = formReq.FormTime("until", "")
+	assert.Equal(t, "ahttp: form param 'until' has invalid time value '01/04/2023' for layout '2006-01-02T15:04:05Z07:00'", err.Error())
+
+	_, err = formReq.FormTimeRFC3339("not-exists")
+	assert.Equal(t, "ahttp: form param 'not-exists' is missing", err.Error())
+}
+
+func TestHTTPRequestNormValues(t *testing.T) {
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet,
+		"http://localhost:8080/search?q=%20Go%20%20Web%09Framework%20&sort=%20Name%20", nil))
+	defer ReleaseRequest(aahReq)
+
+	testcases := []struct {
+		label    string
+		opts     NormOptions
+		expected string
+	}{
+		{"none", NormOptions{}, " Go  Web\tFramework "},
+		{"trim", NormOptions{Trim: true}, "Go  Web\tFramework"},
+		{"lower", NormOptions{Lower: true}, " go  web\tframework "},
+		{"collapse space", NormOptions{CollapseSpace: true}, " Go Web Framework "},
+		{"trim and lower", NormOptions{Trim: true, Lower: true}, "go  web\tframework"},
+		{"all", NormOptions{Trim: true, Lower: true, CollapseSpace: true}, "go web framework"},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expected, aahReq.QueryValueNorm("q", tc.opts), tc.label)
+	}
+
+	assert.Equal(t, "Name", aahReq.QueryValueTrimmed("sort"))
+	assert.Equal(t, "name", aahReq.QueryValueLower("sort"))
+	assert.Equal(t, "", aahReq.QueryValueLower("not-exists"))
+
+	// form
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/users",
+		strings.NewReader("email=%20John%40Example.COM%20&name=John%20%20%20Doe"))
+	req.Header.Set(HeaderContentType, ContentTypeForm.String())
+	aahReq = AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	assert.Equal(t, "John@Example.COM", aahReq.FormValueTrimmed("email"))
+	assert.Equal(t, "john@example.com", aahReq.FormValueLower("email"))
+	assert.Equal(t, "John Doe", aahReq.FormValueNorm("name", NormOptions{CollapseSpace: true}))
+}
+
+func TestHTTPRequestPagination(t *testing.T) {
+	testcases := []struct {
+		label              string
+		query              string
+		page, size, offset int
+	}{
+		{label: "defaults", query: "", page: 1, size: 20, offset: 0},
+		{label: "page and per_page", query: "page=3&per_page=10", page: 3, size: 10, offset: 20},
+		{label: "limit alias", query: "page=2&limit=5", page: 2, size: 5, offset: 5},
+		{label: "per_page over limit", query: "per_page=15&limit=5", page: 1, size: 15, offset: 0},
+		{label: "size clamped to max", query: "page=2&per_page=500", page: 2, size: 100, offset: 100},
+		{label: "size clamped to min", query: "per_page=0", page: 1, size: 1, offset: 0},
+		{label: "negative page", query: "page=-4&per_page=10", page: 1, size: 10, offset: 0},
+		{label: "non-numeric values", query: "page=abc&per_page=xyz&offset=nan", page: 1, size: 20, offset: 0},
+		{label: "offset precedence", query: "page=9&per_page=10&offset=25", page: 3, size: 10, offset: 25},
+		{label: "negative offset", query: "page=2&per_page=10&offset=-5", page: 2, size: 10, offset: 10},
+		{label: "huge page", query: "page=99999999999999999999", page: 1, size: 20, offset: 0},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			aahReq := AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080/users?"+tc.query, nil))
+			defer ReleaseRequest(aahReq)
+
+			page, size, offset := aahReq.Pagination(20, 100)
+			assert.Equal(t, tc.page, page)
+			assert.Equal(t, tc.size, size)
+			assert.Equal(t, tc.offset, offset)
+		})
+	}
+
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080/users?page=2", nil))
+	defer ReleaseRequest(aahReq)
+	page, size, offset := aahReq.Pagination(50, 10)
+	assert.Equal(t, 2, page)
+	assert.Equal(t, 10, size)
+	assert.Equal(t, 10, offset)
+}
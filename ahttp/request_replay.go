@@ -0,0 +1,119 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+var (
+	// MaxReplayBodySize is maximum request body size (default 1MB) allowed by
+	// method `Request.Marshal`.
+	MaxReplayBodySize int64 = 1 << 20
+
+	// ErrReplayBodyTooLarge returned when request body exceeds
+	// `MaxReplayBodySize` on `Request.Marshal`.
+	ErrReplayBodyTooLarge = errors.New("ahttp: request body too large to marshal")
+)
+
+// replayRequest is portable form of HTTP request for record and replay.
+type replayRequest struct {
+	Method string      `json:"method"`
+	Scheme string      `json:"scheme"`
+	Host   string      `json:"host"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Proto  string      `json:"proto,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   []byte      `json:"body,omitempty"`
+}
+
+// Marshal method serializes the request method, path, headers, query and
+// body into portable JSON format, so that request can be replayed later using
+// method `ahttp.UnmarshalRequest`. Values of the given headers are masked,
+// e.g. `Authorization`, `Cookie`.
+//
+// Request body is bounded by `MaxReplayBodySize`, and body remains readable
+// for the request handler after marshal.
+func (r *Request) Marshal(maskHeaders ...string) ([]byte, error) {
+	rr := replayRequest{
+		Method: r.Method,
+		Scheme: r.Scheme,
+		Host:   r.Host,
+		Path:   r.Path,
+		Query:  r.URL().RawQuery,
+		Proto:  r.Proto,
+		Header: make(http.Header, len(r.Header)),
+	}
+
+	for k, v := range r.Header {
+		rr.Header[k] = append([]string(nil), v...)
+	}
+	for _, h := range maskHeaders {
+		if _, found := rr.Header[http.CanonicalHeaderKey(h)]; found {
+			rr.Header.Set(h, maskedValue)
+		}
+	}
+
+	if body := r.Unwrap().Body; body != nil && body != http.NoBody {
+		b, err := ioutil.ReadAll(io.LimitReader(body, MaxReplayBodySize+1))
+		r.Unwrap().Body = &replayBody{Reader: io.MultiReader(bytes.NewReader(b), body), body: body}
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(b)) > MaxReplayBodySize {
+			return nil, ErrReplayBodyTooLarge
+		}
+		rr.Body = b
+	}
+
+	return json.Marshal(rr)
+}
+
+// UnmarshalRequest method reconstructs the request from data produced by
+// method `Request.Marshal` with synthetic `*http.Request`.
+func UnmarshalRequest(data []byte) (*Request, error) {
+	var rr replayRequest
+	if err := json.Unmarshal(data, &rr); err != nil {
+		return nil, err
+	}
+
+	u := &url.URL{Scheme: rr.Scheme, Host: rr.Host, Path: rr.Path, RawQuery: rr.Query}
+	raw, err := http.NewRequest(rr.Method, u.String(), bytes.NewReader(rr.Body))
+	if err != nil {
+		return nil, err
+	}
+	if len(rr.Proto) > 0 {
+		if major, minor, ok := http.ParseHTTPVersion(rr.Proto); ok {
+			raw.Proto, raw.ProtoMajor, raw.ProtoMinor = rr.Proto, major, minor
+		}
+	}
+	if rr.Header != nil {
+		raw.Header = rr.Header
+	}
+
+	req := ParseRequest(raw, &Request{})
+	if len(rr.Scheme) > 0 {
+		req.Scheme = rr.Scheme
+		req.raw.URL.Scheme = rr.Scheme
+	}
+	return req, nil
+}
+
+// replayBody restores the consumed request body and closes original body.
+type replayBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (b *replayBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPRequestMarshalReplay(t *testing.T) {
+	req := httptest.NewRequest(MethodPost, "https://aahframework.org/users?sort=name&page=2",
+		strings.NewReader(`{"name":"jeeva"}`))
+	req.Header.Set(HeaderContentType, ContentTypeJSON.String())
+	req.Header.Set(HeaderAuthorization, "Bearer secret-token")
+	req.Header.Add(HeaderAccept, "application/json")
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	data, err := aahReq.Marshal(HeaderAuthorization, "X-Not-Exists")
+	assert.Nil(t, err)
+	assert.False(t, strings.Contains(string(data), "secret-token"))
+
+	// body remains readable after marshal
+	b, err := ioutil.ReadAll(aahReq.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"jeeva"}`, string(b))
+	assert.Nil(t, aahReq.Body().Close())
+
+	replayReq, err := UnmarshalRequest(data)
+	assert.Nil(t, err)
+	assert.Equal(t, MethodPost, replayReq.Method)
+	assert.Equal(t, "https", replayReq.Scheme)
+	assert.Equal(t, "aahframework.org", replayReq.Host)
+	assert.Equal(t, "/users", replayReq.Path)
+	assert.Equal(t, "HTTP/1.1", replayReq.Proto)
+	assert.Equal(t, "2", replayReq.QueryValue("page"))
+	assert.Equal(t, "***", replayReq.Header.Get(HeaderAuthorization))
+	assert.Equal(t, ContentTypeJSON.Mime, replayReq.ContentType().Mime)
+	assert.Equal(t, "https://aahframework.org/users?sort=name&page=2", replayReq.URL().String())
+
+	b, err = ioutil.ReadAll(replayReq.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"name":"jeeva"}`, string(b))
+
+	_, err = UnmarshalRequest([]byte("not json"))
+	assert.NotNil(t, err)
+}
+
+func TestHTTPRequestMarshalBodyTooLarge(t *testing.T) {
+	defer func(size int64) { MaxReplayBodySize = size }(MaxReplayBodySize)
+	MaxReplayBodySize = 10
+
+	aahReq := AcquireRequest(httptest.NewRequest(MethodPost, "http://localhost:8080/upload",
+		strings.NewReader("this body exceeds the limit")))
+	defer ReleaseRequest(aahReq)
+
+	_, err := aahReq.Marshal()
+	assert.Equal(t, ErrReplayBodyTooLarge, err)
+
+	b, _ := ioutil.ReadAll(aahReq.Body())
+	assert.Equal(t, "this body exceeds the limit", string(b))
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RequestRule func type is used to validate incoming request constraints.
+// It returns error if the request violates the rule otherwise nil.
+type RequestRule func(r *Request) error
+
+// RequestErrors is list of rule violations from method `Request.Validate`.
+type RequestErrors []error
+
+// Error method is error interface implementation.
+func (e RequestErrors) Error() string {
+	var msgs []string
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate method validates the request against given rules. It evaluates
+// all the rules and returns `RequestErrors` aggregating all the failures,
+// so that client gets complete feedback.
+//
+// 	For e.g.:
+// 		err := req.Validate(
+// 			ahttp.RequireContentType(ahttp.ContentTypeJSON.Mime),
+// 			ahttp.MaxHeaderCount(50),
+// 			ahttp.MaxPathLength(2048),
+// 		)
+func (r *Request) Validate(rules ...RequestRule) error {
+	var errs RequestErrors
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		if err := rule(r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// RequireContentType method returns the rule which requires HTTP header
+// `Content-Type` on request with body i.e. `POST`, `PUT` and `PATCH`. If mime
+// types given then `Content-Type` must be one of them.
+func RequireContentType(mimes ...string) RequestRule {
+	return func(r *Request) error {
+		if !(r.Method == MethodPost || r.Method == MethodPut || r.Method == MethodPatch) {
+			return nil
+		}
+
+		if len(strings.TrimSpace(r.Header.Get(HeaderContentType))) == 0 {
+			return fmt.Errorf("ahttp: header '%s' is required", HeaderContentType)
+		}
+
+		if len(mimes) == 0 {
+			return nil
+		}
+
+		mime := r.ContentType().Mime
+		for _, m := range mimes {
+			if strings.EqualFold(mime, m) {
+				return nil
+			}
+		}
+		return fmt.Errorf("ahttp: content type '%s' is not allowed", mime)
+	}
+}
+
+// MaxHeaderCount method returns the rule which limits the number of HTTP
+// header fields in the request.
+func MaxHeaderCount(n int) RequestRule {
+	return func(r *Request) error {
+		if len(r.Header) > n {
+			return fmt.Errorf("ahttp: header count %d exceeds limit %d", len(r.Header), n)
+		}
+		return nil
+	}
+}
+
+// MaxPathLength method returns the rule which limits the request URL path length.
+func MaxPathLength(n int) RequestRule {
+	return func(r *Request) error {
+		if len(r.Path) > n {
+			return fmt.Errorf("ahttp: path length %d exceeds limit %d", len(r.Path), n)
+		}
+		return nil
+	}
+}
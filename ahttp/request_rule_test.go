@@ -0,0 +1,62 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPRequestValidate(t *testing.T) {
+	rules := []RequestRule{
+		RequireContentType(ContentTypeJSON.Mime, ContentTypeXML.Mime),
+		MaxHeaderCount(3),
+		MaxPathLength(20),
+		nil,
+	}
+
+	// valid request
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/users", strings.NewReader(`{}`))
+	req.Header.Set(HeaderContentType, "application/json; charset=utf-8")
+	aahReq := AcquireRequest(req)
+	assert.Nil(t, aahReq.Validate(rules...))
+	ReleaseRequest(aahReq)
+
+	// content type is not required for GET
+	aahReq = AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080/users", nil))
+	assert.Nil(t, aahReq.Validate(rules...))
+	ReleaseRequest(aahReq)
+
+	// content type missing
+	aahReq = AcquireRequest(httptest.NewRequest(MethodPut, "http://localhost:8080/users/1", nil))
+	assert.Equal(t, "ahttp: header 'Content-Type' is required", aahReq.Validate(rules...).Error())
+	assert.Nil(t, aahReq.Validate(MaxPathLength(20)))
+	ReleaseRequest(aahReq)
+
+	// all failures aggregated
+	req = httptest.NewRequest(MethodPatch, "http://localhost:8080/users/1/addresses/primary", nil)
+	req.Header.Set(HeaderContentType, ContentTypeForm.String())
+	req.Header.Set(HeaderAccept, "*/*")
+	req.Header.Set(HeaderUserAgent, "aah-test")
+	req.Header.Set(HeaderAcceptLanguage, "en")
+	aahReq = AcquireRequest(req)
+	err := aahReq.Validate(rules...)
+	errs, ok := err.(RequestErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 3, len(errs))
+	assert.Equal(t, "ahttp: content type 'application/x-www-form-urlencoded' is not allowed; "+
+		"ahttp: header count 4 exceeds limit 3; ahttp: path length 26 exceeds limit 20", err.Error())
+	ReleaseRequest(aahReq)
+
+	// content type presence only
+	req = httptest.NewRequest(MethodPost, "http://localhost:8080/users", nil)
+	req.Header.Set(HeaderContentType, ContentTypeForm.String())
+	aahReq = AcquireRequest(req)
+	assert.Nil(t, aahReq.Validate(RequireContentType()))
+	ReleaseRequest(aahReq)
+}
@@ -5,16 +5,21 @@
 package ahttp
 
 import (
+	"bufio"
 	"bytes"
 	"crypto/tls"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"aahframe.work/essentials"
 	"github.com/stretchr/testify/assert"
@@ -122,6 +127,8 @@ func TestHTTPRequestParams(t *testing.T) {
 	assert.Equal(t, "Test1", aahReq1.QueryArrayValue("names")[0])
 	assert.Equal(t, "Test 2", aahReq1.QueryArrayValue("names")[1])
 	assert.True(t, len(aahReq1.QueryArrayValue("not-exists")) == 0)
+	assert.Equal(t, "true", aahReq1.QueryValueDefault("_ref", "false"))
+	assert.Equal(t, "default", aahReq1.QueryValueDefault("not-exists", "default"))
 	assert.Equal(t, "100001", aahReq1.PathValue("userId"))
 	assert.Equal(t, "", aahReq1.PathValue("accountId"))
 	assert.Equal(t, 1, len(aahReq1.URLParams))
@@ -140,6 +147,8 @@ func TestHTTPRequestParams(t *testing.T) {
 	assert.NotNil(t, aahReq2.Body())
 	assert.Equal(t, "welcome", aahReq2.FormValue("username"))
 	assert.Equal(t, "welcome@welcome.com", aahReq2.FormValue("email"))
+	assert.Equal(t, "welcome", aahReq2.FormValueDefault("username", "guest"))
+	assert.Equal(t, "guest", aahReq2.FormValueDefault("not-exists", "guest"))
 	assert.Equal(t, "Test1", aahReq2.FormArrayValue("names")[0])
 	assert.Equal(t, "Test 2 value", aahReq2.FormArrayValue("names")[1])
 	assert.True(t, len(aahReq2.FormArrayValue("not-exists")) == 0)
@@ -181,6 +190,943 @@ func TestHTTPRequestCookies(t *testing.T) {
 	assert.Equal(t, "test-2 value", cookie.Value)
 }
 
+func TestHTTPRequestRecommendSecureCookie(t *testing.T) {
+	// plain HTTP
+	req := createRequestWithHost("127.0.0.1:8080", "192.168.0.1:1234")
+	req.URL, _ = url.Parse("http://localhost:8080/welcome.html")
+	assert.False(t, ParseRequest(req, &Request{}).RecommendSecureCookie())
+
+	// direct TLS
+	req = createRequestWithHost("127.0.0.1:8443", "192.168.0.1:1234")
+	req.URL, _ = url.Parse("https://localhost:8443/welcome.html")
+	req.TLS = &tls.ConnectionState{}
+	assert.True(t, ParseRequest(req, &Request{}).RecommendSecureCookie())
+
+	// TLS terminated at proxy
+	req = createRequestWithHost("127.0.0.1:8080", "192.168.0.1:1234")
+	req.URL, _ = url.Parse("http://localhost:8080/welcome.html")
+	req.Header.Set(HeaderXForwardedProto, "HTTPS")
+	assert.True(t, ParseRequest(req, &Request{}).RecommendSecureCookie())
+
+	req = createRequestWithHost("127.0.0.1:8080", "192.168.0.1:1234")
+	req.URL, _ = url.Parse("http://localhost:8080/welcome.html")
+	req.Header.Set(HeaderXForwardedSsl, "on")
+	assert.True(t, ParseRequest(req, &Request{}).RecommendSecureCookie())
+
+	// plain HTTP behind proxy
+	req = createRequestWithHost("127.0.0.1:8080", "192.168.0.1:1234")
+	req.URL, _ = url.Parse("http://localhost:8080/welcome.html")
+	req.Header.Set(HeaderXForwardedProto, "http")
+	assert.False(t, ParseRequest(req, &Request{}).RecommendSecureCookie())
+}
+
+func TestHTTPRequestSessionID(t *testing.T) {
+	newReq := func() *http.Request {
+		req := createRequestWithHost("127.0.0.1:8080", "192.168.0.1:1234")
+		req.URL, _ = url.Parse("http://localhost:8080/welcome.html")
+		return req
+	}
+
+	// cookie present, wins over header
+	req := newReq()
+	req.AddCookie(&http.Cookie{Name: "aah_session", Value: "cookie-sid"})
+	req.Header.Set("X-Session-Id", "header-sid")
+	assert.Equal(t, "cookie-sid", ParseRequest(req, &Request{}).SessionID("", ""))
+
+	// header present
+	req = newReq()
+	req.Header.Set("X-Session-Id", " header-sid ")
+	assert.Equal(t, "header-sid", ParseRequest(req, &Request{}).SessionID("", ""))
+
+	// neither present
+	assert.Equal(t, "", ParseRequest(newReq(), &Request{}).SessionID("", ""))
+
+	// explicit names
+	req = newReq()
+	req.AddCookie(&http.Cookie{Name: "sid", Value: "custom-sid"})
+	req.Header.Set("X-Auth-Session", "custom-header-sid")
+	aahReq := ParseRequest(req, &Request{})
+	assert.Equal(t, "custom-sid", aahReq.SessionID("sid", "X-Auth-Session"))
+	assert.Equal(t, "custom-header-sid", aahReq.SessionID("nosid", "X-Auth-Session"))
+
+	// package defaults
+	SetSessionKeys("sid", "X-Auth-Session")
+	defer SetSessionKeys("aah_session", "X-Session-Id")
+	assert.Equal(t, "custom-sid", aahReq.SessionID("", ""))
+	assert.Equal(t, "", aahReq.SessionID("nosid", "X-Nosid"))
+}
+
+func TestHTTPRequestFingerprint(t *testing.T) {
+	newReq := func(remote, ua, lang string) *Request {
+		req := createRequestWithHost("127.0.0.1:8080", remote)
+		req.Header.Set(HeaderUserAgent, ua)
+		req.Header.Set(HeaderAcceptLanguage, lang)
+		return ParseRequest(req, &Request{})
+	}
+	ua := "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Safari/605.1.15"
+
+	fp := newReq("192.168.0.1:1234", ua, "en-US,en;q=0.9").Fingerprint()
+	assert.Equal(t, 32, len(fp))
+
+	// stable for identical requests, port and header case/whitespace ignored
+	assert.Equal(t, fp, newReq("192.168.0.1:1234", ua, "en-US,en;q=0.9").Fingerprint())
+	assert.Equal(t, fp, newReq("192.168.0.1:5678", ua, "en-US,en;q=0.9").Fingerprint())
+	assert.Equal(t, fp, newReq("192.168.0.1:1234", "  "+strings.ToUpper(ua), "EN-US,en;q=0.9 ").Fingerprint())
+
+	// differs when IP or contributing header changes
+	assert.NotEqual(t, fp, newReq("192.168.0.2:1234", ua, "en-US,en;q=0.9").Fingerprint())
+	assert.NotEqual(t, fp, newReq("192.168.0.1:1234", ua, "fr-FR").Fingerprint())
+	assert.NotEqual(t, fp, newReq("192.168.0.1:1234", "curl/8.4.0", "en-US,en;q=0.9").Fingerprint())
+
+	// configured headers
+	SetFingerprintHeaders([]string{"x-device-id", " "})
+	defer SetFingerprintHeaders([]string{HeaderUserAgent, HeaderAcceptLanguage})
+	assert.Equal(t, []string{"X-Device-Id"}, fingerprintHeaders)
+
+	aahReq := newReq("192.168.0.1:1234", ua, "en-US,en;q=0.9")
+	deviceFP := aahReq.Fingerprint()
+	assert.NotEqual(t, fp, deviceFP)
+	assert.Equal(t, deviceFP, newReq("192.168.0.1:1234", "curl/8.4.0", "fr-FR").Fingerprint())
+	aahReq.Header.Set("X-Device-Id", "device-1")
+	assert.NotEqual(t, deviceFP, aahReq.Fingerprint())
+}
+
+func TestHTTPRequestAcceptLanguages(t *testing.T) {
+	aahReq := AcquireRequest(createRawHTTPRequest(HeaderAcceptLanguage, "da, en-GB;q=0.8, en;q=0.7, fr;q=0, *;q=0.1"))
+	assert.Equal(t, []string{"da", "en-GB", "en"}, aahReq.AcceptLanguages())
+
+	assert.Equal(t, "en-gb", aahReq.MatchLanguage([]string{"en-us", "en-gb"}))
+	assert.Equal(t, "en-US", aahReq.MatchLanguage([]string{"en-US", "de"}))
+	assert.Equal(t, "da-DK", aahReq.MatchLanguage([]string{"en", "da-DK"}))
+	assert.Equal(t, "ja", aahReq.MatchLanguage([]string{"ja", "fr"}))
+	assert.Equal(t, "", aahReq.MatchLanguage(nil))
+	ReleaseRequest(aahReq)
+
+	aahReq = AcquireRequest(createRawHTTPRequest(HeaderAcceptLanguage, "en-US, fr;q=0.5"))
+	assert.Equal(t, "fr", aahReq.MatchLanguage([]string{"de", "fr"}))
+	assert.Equal(t, "", aahReq.MatchLanguage([]string{"de", "ja"}))
+	ReleaseRequest(aahReq)
+
+	// script aware match
+	aahReq = AcquireRequest(createRawHTTPRequest(HeaderAcceptLanguage, "zh-TW, en;q=0.5"))
+	assert.Equal(t, "zh-Hant", aahReq.MatchLanguage([]string{"zh-Hans", "zh-Hant"}))
+	assert.Equal(t, "en", aahReq.MatchLanguage([]string{"not a tag", "en"}))
+	ReleaseRequest(aahReq)
+
+	aahReq = AcquireRequest(createRawHTTPRequest(HeaderAccept, "*/*"))
+	assert.Nil(t, aahReq.AcceptLanguages())
+	assert.Equal(t, "", aahReq.MatchLanguage([]string{"en"}))
+	ReleaseRequest(aahReq)
+}
+
+func TestHTTPRequestPreferredLanguage(t *testing.T) {
+	aahReq := AcquireRequest(createRawHTTPRequest(HeaderAcceptLanguage, "de-DE, de;q=0.9, fr;q=0.8, en;q=0.5"))
+	assert.Equal(t, "fr", aahReq.PreferredLanguage([]string{"en", "fr"}, "en"))
+	assert.Equal(t, "en-US", aahReq.PreferredLanguage([]string{"ja", "en-US"}, "ja"))
+	assert.Equal(t, "de", aahReq.PreferredLanguage([]string{"fr", "de"}, "fr"))
+	assert.Equal(t, "ja", aahReq.PreferredLanguage([]string{"ja", "zh"}, "ja"))
+	assert.Equal(t, "en", aahReq.PreferredLanguage(nil, "en"))
+
+	aahReq = AcquireRequest(createRawHTTPRequest(HeaderAcceptLanguage, "de, fr;q=0"))
+	assert.Equal(t, "en", aahReq.PreferredLanguage([]string{"en", "fr"}, "en"))
+
+	aahReq = AcquireRequest(createRawHTTPRequest("", ""))
+	assert.Equal(t, "en", aahReq.PreferredLanguage([]string{"fr", "de"}, "en"))
+}
+
+func TestHTTPRequestNegotiateContentType(t *testing.T) {
+	testcases := []struct {
+		label, accept, result string
+		offers                []string
+	}{
+		{label: "exact", accept: "application/json, text/html;q=0.9", offers: []string{"text/html", "application/json"}, result: "application/json"},
+		{label: "quality order", accept: "text/html;q=0.5, application/xml;q=0.8", offers: []string{"text/html", "application/xml"}, result: "application/xml"},
+		{label: "type wildcard", accept: "text/*, application/json;q=0.5", offers: []string{"application/json", "text/plain"}, result: "text/plain"},
+		{label: "any wildcard", accept: "*/*", offers: []string{"application/xml", "application/json"}, result: "application/xml"},
+		{label: "specific wins over wildcard", accept: "text/*;q=0.9, text/html;q=0", offers: []string{"text/html", "text/plain"}, result: "text/plain"},
+		{label: "offer with params", accept: "application/json", offers: []string{"application/json; charset=utf-8"}, result: "application/json; charset=utf-8"},
+		{label: "case-insensitive", accept: "Application/JSON", offers: []string{"application/json"}, result: "application/json"},
+		{label: "no match", accept: "application/json, text/*", offers: []string{"image/png", "application/xml"}, result: ""},
+		{label: "refused", accept: "application/json;q=0", offers: []string{"application/json"}, result: ""},
+		{label: "no accept header", accept: "", offers: []string{"text/html", "application/json"}, result: "text/html"},
+		{label: "no offers", accept: "*/*", offers: nil, result: ""},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			aahReq := AcquireRequest(createRawHTTPRequest(HeaderAccept, tc.accept))
+			assert.Equal(t, tc.result, aahReq.NegotiateContentType(tc.offers...))
+		})
+	}
+}
+
+func TestHTTPRequestTeeBody(t *testing.T) {
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/audit", strings.NewReader(`{"amount":1000}`))
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	buf := &bytes.Buffer{}
+	audit := bufio.NewWriter(buf)
+	aahReq.TeeBody(audit)
+
+	b, err := ioutil.ReadAll(aahReq.Body())
+	assert.Nil(t, err)
+	assert.Equal(t, `{"amount":1000}`, string(b))
+	assert.Equal(t, 0, buf.Len()) // not flushed yet
+
+	assert.Nil(t, aahReq.Body().Close())
+	assert.Equal(t, `{"amount":1000}`, buf.String())
+
+	// nil writer is no-op
+	body := aahReq.Body()
+	aahReq.TeeBody(nil)
+	assert.Equal(t, body, aahReq.Body())
+}
+
+func TestHTTPRequestLimitBody(t *testing.T) {
+	payload := `{"name":"aah","tags":["go","web"]}`
+	limit := int64(len(payload) - 1)
+
+	// JSON decode just over the limit
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/users", strings.NewReader(payload))
+	aahReq := AcquireRequest(req)
+	w := httptest.NewRecorder()
+	aahReq.LimitBody(w, limit)
+	var v map[string]interface{}
+	err := json.NewDecoder(aahReq.Body()).Decode(&v)
+	assert.Equal(t, ErrBodyTooLarge, err)
+	assert.Equal(t, "close", w.Header().Get(HeaderConnection))
+	ReleaseRequest(aahReq)
+
+	// body bytes
+	req = httptest.NewRequest(MethodPost, "http://localhost:8080/users", strings.NewReader(payload))
+	aahReq = AcquireRequest(req)
+	aahReq.LimitBody(nil, limit)
+	b, err := aahReq.BodyBytes()
+	assert.Nil(t, b)
+	assert.Equal(t, ErrBodyTooLarge, err)
+	ReleaseRequest(aahReq)
+
+	// form parsing
+	req = httptest.NewRequest(MethodPost, "http://localhost:8080/users", strings.NewReader("name=aah&tags=go"))
+	req.Header.Set(HeaderContentType, ContentTypeForm.String())
+	aahReq = AcquireRequest(req)
+	aahReq.LimitBody(nil, 10)
+	assert.Equal(t, ErrBodyTooLarge, aahReq.Unwrap().ParseForm())
+	ReleaseRequest(aahReq)
+
+	// within the limit
+	req = httptest.NewRequest(MethodPost, "http://localhost:8080/users", strings.NewReader(payload))
+	aahReq = AcquireRequest(req)
+	aahReq.LimitBody(nil, int64(len(payload)))
+	b, err = aahReq.BodyBytes()
+	assert.Nil(t, err)
+	assert.Equal(t, payload, string(b))
+	ReleaseRequest(aahReq)
+}
+
+func TestHTTPRequestLooksLikeJSON(t *testing.T) {
+	testcases := []struct {
+		label    string
+		body     string
+		expected bool
+	}{
+		{label: "json object", body: `{"name":"aah"}`, expected: true},
+		{label: "json array", body: `[1,2,3]`, expected: true},
+		{label: "leading whitespace", body: " \r\n\t {\"name\":\"aah\"}", expected: true},
+		{label: "empty", body: "", expected: false},
+		{label: "whitespace only", body: "  \n ", expected: false},
+		{label: "html", body: "<!DOCTYPE html><html><body>502 Bad Gateway</body></html>", expected: false},
+		{label: "json scalar", body: `"aah"`, expected: false},
+		{label: "whitespace within peek size", body: strings.Repeat(" ", maxJSONPeekSize-1) + "{}", expected: true},
+		{label: "whitespace beyond peek size", body: strings.Repeat("\n", maxJSONPeekSize) + "{}", expected: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			req := httptest.NewRequest(MethodPost, "http://localhost:8080/users", strings.NewReader(tc.body))
+			req.Header.Set(HeaderContentType, ContentTypeJSON.String())
+			aahReq := AcquireRequest(req)
+			defer ReleaseRequest(aahReq)
+
+			assert.Equal(t, tc.expected, aahReq.LooksLikeJSON())
+			assert.Equal(t, tc.expected, aahReq.LooksLikeJSON()) // peek is repeatable
+
+			// body is intact for the handler
+			b, err := aahReq.BodyBytes()
+			assert.Nil(t, err)
+			assert.Equal(t, tc.body, string(b))
+			assert.Nil(t, aahReq.Body().Close())
+		})
+	}
+
+	// no body
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080/users", nil))
+	defer ReleaseRequest(aahReq)
+	assert.False(t, aahReq.LooksLikeJSON())
+}
+
+func TestHTTPRequestReplaceBody(t *testing.T) {
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/users?page=2", strings.NewReader("bmFtZT1hYWg="))
+	req.Header.Set(HeaderContentType, ContentTypeForm.String())
+	aahReq := AcquireRequest(req)
+	assert.Equal(t, int64(12), aahReq.Unwrap().ContentLength)
+
+	// middleware decodes the body
+	decoded := "name=aah&tags=go"
+	aahReq.ReplaceBody(ioutil.NopCloser(strings.NewReader(decoded)), int64(len(decoded)))
+	assert.Equal(t, int64(len(decoded)), aahReq.Unwrap().ContentLength)
+	assert.Equal(t, "16", aahReq.Header.Get(HeaderContentLength))
+	b, err := aahReq.BodyBytes()
+	assert.Nil(t, err)
+	assert.Equal(t, decoded, string(b))
+
+	// parsed form is cleared and parsed from replaced body
+	aahReq.ReplaceBody(ioutil.NopCloser(strings.NewReader(decoded)), int64(len(decoded)))
+	assert.Equal(t, "aah", aahReq.FormValue("name"))
+	aahReq.ReplaceBody(ioutil.NopCloser(strings.NewReader("name=aah2")), 9)
+	assert.Equal(t, "aah2", aahReq.FormValue("name"))
+	assert.Equal(t, "2", aahReq.FormValue("page"))
+
+	// unknown length and nil body
+	aahReq.ReplaceBody(ioutil.NopCloser(strings.NewReader(decoded)), -1)
+	assert.Equal(t, int64(-1), aahReq.Unwrap().ContentLength)
+	assert.Equal(t, "", aahReq.Header.Get(HeaderContentLength))
+
+	aahReq.ReplaceBody(nil, 10)
+	assert.Equal(t, int64(0), aahReq.Unwrap().ContentLength)
+	b, err = aahReq.BodyBytes()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(b))
+	ReleaseRequest(aahReq)
+}
+
+func TestHTTPRequestExpectsContinue(t *testing.T) {
+	aahReq := AcquireRequest(createRawHTTPRequest(HeaderExpect, "100-Continue"))
+	assert.True(t, aahReq.ExpectsContinue())
+	ReleaseRequest(aahReq)
+
+	aahReq = AcquireRequest(createRawHTTPRequest(HeaderExpect, "101-switching"))
+	assert.False(t, aahReq.ExpectsContinue())
+	ReleaseRequest(aahReq)
+
+	aahReq = AcquireRequest(createRawHTTPRequest(HeaderAccept, "*/*"))
+	assert.False(t, aahReq.ExpectsContinue())
+	ReleaseRequest(aahReq)
+}
+
+func TestHTTPRequestRawQueryValue(t *testing.T) {
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet,
+		"http://localhost:8080/search?q=a+b%2Fc&sig=abc%3D%3D&name=hello%20world&q=second&flag&empty=&my%20key=v1", nil))
+	defer ReleaseRequest(aahReq)
+
+	testcases := []struct {
+		key, raw, decoded string
+	}{
+		{"q", "a+b%2Fc", "a b/c"},
+		{"sig", "abc%3D%3D", "abc=="},
+		{"name", "hello%20world", "hello world"},
+		{"flag", "", ""},
+		{"empty", "", ""},
+		{"my key", "v1", "v1"},
+		{"not-exists", "", ""},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, tc.raw, aahReq.RawQueryValue(tc.key), tc.key)
+		assert.Equal(t, tc.decoded, aahReq.QueryValue(tc.key), tc.key)
+	}
+}
+
+func TestHTTPRequestQueryValuesFiltered(t *testing.T) {
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet,
+		"http://localhost:8080/search?q=aah&tag=go&tag=web&page=2&utm_source=mail&utm_medium=email&Q=upper", nil))
+	defer ReleaseRequest(aahReq)
+
+	values := aahReq.QueryValuesFiltered("q", "tag", "sort")
+	assert.Equal(t, "q=aah&tag=go&tag=web", values.Encode())
+
+	values = aahReq.QueryValuesExcept("utm_source", "utm_medium", "q")
+	assert.Equal(t, "Q=upper&page=2&tag=go&tag=web", values.Encode())
+
+	// copy doesn't modify the request
+	values.Set("page", "3")
+	values["tag"][0] = "changed"
+	assert.Equal(t, "2", aahReq.QueryValue("page"))
+	assert.Equal(t, []string{"go", "web"}, aahReq.QueryArrayValue("tag"))
+
+	assert.Equal(t, 0, len(aahReq.QueryValuesFiltered()))
+	assert.Equal(t, 6, len(aahReq.QueryValuesExcept()))
+}
+
+func TestHTTPRequestFormValues(t *testing.T) {
+	// urlencoded
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/users?page=2",
+		strings.NewReader("name=jeeva&roles=admin&roles=editor"))
+	req.Header.Set(HeaderContentType, ContentTypeForm.String())
+	aahReq := AcquireRequest(req)
+
+	values := aahReq.FormValues()
+	assert.Equal(t, []string{"admin", "editor"}, values["roles"])
+	assert.Equal(t, "jeeva", values.Get("name"))
+	assert.Equal(t, "2", values.Get("page"))
+	assert.Equal(t, []string{"name", "page", "roles"}, aahReq.FormKeys())
+
+	// returned values is a copy
+	values.Set("name", "modified")
+	values["roles"][0] = "guest"
+	values.Del("page")
+	assert.Equal(t, "jeeva", aahReq.FormValue("name"))
+	assert.Equal(t, []string{"admin", "editor"}, aahReq.FormArrayValue("roles"))
+	assert.Equal(t, "2", aahReq.FormValues().Get("page"))
+	ReleaseRequest(aahReq)
+
+	// multipart
+	buf := new(bytes.Buffer)
+	multipartWriter := multipart.NewWriter(buf)
+	_ = multipartWriter.WriteField("title", "aah")
+	_ = multipartWriter.WriteField("tags", "go")
+	_ = multipartWriter.WriteField("tags", "web")
+	ess.CloseQuietly(multipartWriter)
+	req = httptest.NewRequest(MethodPost, "http://localhost:8080/articles", buf)
+	req.Header.Set(HeaderContentType, multipartWriter.FormDataContentType())
+	aahReq = AcquireRequest(req)
+	assert.Equal(t, []string{"tags", "title"}, aahReq.FormKeys())
+	assert.Equal(t, []string{"go", "web"}, aahReq.FormValues()["tags"])
+	ReleaseRequest(aahReq)
+
+	// no form
+	aahReq = AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080/users", nil))
+	assert.Equal(t, 0, len(aahReq.FormValues()))
+	assert.Equal(t, []string{}, aahReq.FormKeys())
+	ReleaseRequest(aahReq)
+}
+
+func TestHTTPRequestFormFileHeaders(t *testing.T) {
+	buf := new(bytes.Buffer)
+	multipartWriter := multipart.NewWriter(buf)
+	w, err := multipartWriter.CreateFormFile("images", "aah.png")
+	assert.Nil(t, err)
+	_, _ = w.Write([]byte("\x89PNG\x0D\x0A\x1A\x0A" + strings.Repeat("a", 600)))
+	w, err = multipartWriter.CreateFormFile("images", "aah.txt")
+	assert.Nil(t, err)
+	_, _ = w.Write([]byte("aah web framework"))
+	ess.CloseQuietly(multipartWriter)
+
+	req, _ := http.NewRequest("POST", "http://localhost:8080/upload", buf)
+	req.Header.Add(HeaderContentType, multipartWriter.FormDataContentType())
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	// multipart form not parsed yet
+	fhs := aahReq.FormFileHeaders("images")
+	assert.Equal(t, 2, len(fhs))
+	assert.Equal(t, "aah.png", fhs[0].Filename)
+	assert.Equal(t, int64(608), fhs[0].Size)
+	assert.Equal(t, "aah.txt", fhs[1].Filename)
+	assert.Equal(t, 0, len(aahReq.FormFileHeaders("not-exists")))
+
+	contentType, err := DetectFileContentType(fhs[0])
+	assert.Nil(t, err)
+	assert.Equal(t, "image/png", contentType)
+
+	contentType, err = DetectFileContentType(fhs[1])
+	assert.Nil(t, err)
+	assert.Equal(t, "text/plain; charset=utf-8", contentType)
+
+	_, err = DetectFileContentType(&multipart.FileHeader{Filename: "notexists.txt"})
+	assert.NotNil(t, err)
+
+	// non multipart request
+	aahReq2 := AcquireRequest(createRawHTTPRequest(HeaderContentType, ContentTypeJSON.String()))
+	assert.Equal(t, 0, len(aahReq2.FormFileHeaders("images")))
+	ReleaseRequest(aahReq2)
+}
+
+func TestHTTPRequestStripPrefix(t *testing.T) {
+	testcases := []struct {
+		prefix, path, expected string
+		matched                bool
+	}{
+		{prefix: "/api/v1", path: "/api/v1/users", expected: "/users", matched: true},
+		{prefix: "/api/v1/", path: "/api/v1/users/1", expected: "/users/1", matched: true},
+		{prefix: "/api/v1", path: "/api/v1", expected: "/", matched: true},
+		{prefix: "/api/v1", path: "/api/v1/", expected: "/", matched: true},
+		{prefix: "/", path: "/users", expected: "/users", matched: true},
+		{prefix: "/api/v1", path: "/api/v10/users", expected: "/api/v10/users", matched: false},
+		{prefix: "/api/v1", path: "/api", expected: "/api", matched: false},
+		{prefix: "/api/v1", path: "/users", expected: "/users", matched: false},
+	}
+
+	for _, tc := range testcases {
+		aahReq := AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080"+tc.path, nil))
+		path, matched := aahReq.StripPrefix(tc.prefix)
+		assert.Equal(t, tc.expected, path, tc.prefix+" "+tc.path)
+		assert.Equal(t, tc.matched, matched, tc.prefix+" "+tc.path)
+		assert.Equal(t, tc.path, aahReq.Path)
+		ReleaseRequest(aahReq)
+	}
+}
+
+func TestHTTPRequestPathSegments(t *testing.T) {
+	testcases := []struct {
+		path     string
+		segments []string
+	}{
+		{"/", []string{}},
+		{"/files/docs/readme.txt", []string{"files", "docs", "readme.txt"}},
+		{"/files/docs/", []string{"files", "docs"}},
+		{"//files///docs", []string{"files", "docs"}},
+		{"/files/a%2Fb.txt", []string{"files", "a/b.txt"}},
+		{"/files/hello%20world/%E2%9C%93", []string{"files", "hello world", "✓"}},
+	}
+
+	for _, tc := range testcases {
+		aahReq := AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080"+tc.path, nil))
+		assert.Equal(t, tc.segments, aahReq.PathSegments(), tc.path)
+		ReleaseRequest(aahReq)
+	}
+
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080/files/a%2Fb.txt/", nil))
+	defer ReleaseRequest(aahReq)
+	assert.Equal(t, "files", aahReq.PathSegment(0))
+	assert.Equal(t, "a/b.txt", aahReq.PathSegment(1))
+	assert.Equal(t, "", aahReq.PathSegment(2))
+	assert.Equal(t, "", aahReq.PathSegment(-1))
+
+	// request path modified by application
+	aahReq.Path = "/static/css/app.css"
+	assert.Equal(t, []string{"static", "css", "app.css"}, aahReq.PathSegments())
+}
+
+func TestHTTPRequestCanonicalPath(t *testing.T) {
+	testcases := []struct {
+		label     string
+		paths     []string
+		canonical string
+	}{
+		{"root", []string{"/", "//", "/./", "/../"}, "/"},
+		{"unicode nfc", []string{"/caf\u00e9", "/cafe\u0301", "/caf%C3%A9", "/caf%c3%a9", "/cafe%CC%81", "/cafe%cc%81/"}, "/caf%c3%a9"},
+		{"dot segments", []string{"/admin/users", "/public/../admin/users", "/admin/./users", "//admin//users/",
+			"/public/%2E%2E/admin/users", "/admin/%2e/users"}, "/admin/users"},
+		{"encoded slash", []string{"/files/a%2Fb.txt", "/files/a%2fb.txt"}, "/files/a%2fb.txt"},
+		{"reserved chars", []string{"/search/hello%20world", "/search/hello world"}, "/search/hello%20world"},
+		{"case preserved", []string{"/Admin"}, "/Admin"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			for _, p := range tc.paths {
+				u, err := url.Parse("http://localhost:8080" + p)
+				assert.Nil(t, err, p)
+				req := httptest.NewRequest(MethodGet, "http://localhost:8080/", nil)
+				req.URL = u
+				aahReq := AcquireRequest(req)
+				assert.Equal(t, tc.canonical, aahReq.CanonicalPath(), p)
+				ReleaseRequest(aahReq)
+			}
+		})
+	}
+
+	// request path modified by application
+	aahReq := AcquireRequest(httptest.NewRequest(MethodGet, "http://localhost:8080/users", nil))
+	defer ReleaseRequest(aahReq)
+	aahReq.Path = "/static/cafe\u0301/../app.css"
+	assert.Equal(t, "/static/app.css", aahReq.CanonicalPath())
+}
+
+func TestHTTPRequestOrigin(t *testing.T) {
+	allowed := []string{"https://example.com", "https://*.aahframework.org", "*.Example.org", "http://localhost:8080"}
+	testcases := []struct {
+		origin  string
+		matched bool
+	}{
+		{"https://example.com", true},
+		{"HTTPS://Example.COM", true},
+		{"http://example.com", false},
+		{"https://www.example.com", false},
+		{"https://docs.aahframework.org", true},
+		{"https://a.b.aahframework.org", true},
+		{"https://aahframework.org", false},
+		{"http://docs.aahframework.org", false},
+		{"https://evilaahframework.org", false},
+		{"http://api.example.org", true},
+		{"https://api.example.org", true},
+		{"https://example.org", false},
+		{"http://localhost:8080", true},
+		{"http://localhost:9090", false},
+		{"null", false},
+		{"", false},
+		{"not a url", false},
+	}
+
+	for _, tc := range testcases {
+		req := httptest.NewRequest(MethodGet, "http://localhost:8080/users", nil)
+		if len(tc.origin) > 0 {
+			req.Header.Set(HeaderOrigin, tc.origin)
+		}
+		aahReq := AcquireRequest(req)
+		assert.Equal(t, tc.origin, aahReq.Origin())
+		assert.Equal(t, tc.matched, aahReq.OriginMatches(allowed), tc.origin)
+		ReleaseRequest(aahReq)
+	}
+
+	req := httptest.NewRequest(MethodOptions, "http://localhost:8080/users", nil)
+	req.Header.Set(HeaderOrigin, "null")
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+	assert.False(t, aahReq.IsPreflight())
+	assert.False(t, aahReq.OriginMatches([]string{"*"}))
+	assert.True(t, aahReq.OriginMatches([]string{"null"}))
+
+	req.Header.Set(HeaderOrigin, "https://anything.com")
+	req.Header.Set(HeaderAccessControlRequestMethod, MethodPut)
+	assert.True(t, aahReq.IsPreflight())
+	assert.True(t, aahReq.OriginMatches([]string{"*"}))
+}
+
+func TestHTTPRequestIsSameOrigin(t *testing.T) {
+	testcases := []struct {
+		label, target, origin, referer string
+		sameOrigin                     bool
+	}{
+		{"origin matches", "http://localhost:8080/users", "http://localhost:8080", "", true},
+		{"origin case-insensitive", "http://localhost:8080/users", "HTTP://LocalHost:8080", "", true},
+		{"origin default port", "https://example.com/users", "https://example.com:443", "", true},
+		{"origin scheme mismatch", "https://example.com/users", "http://example.com", "", false},
+		{"origin host mismatch", "http://localhost:8080/users", "http://evil.com", "", false},
+		{"origin port mismatch", "http://localhost:8080/users", "http://localhost:9090", "", false},
+		{"origin wins over referer", "http://localhost:8080/users", "http://evil.com", "http://localhost:8080/form", false},
+		{"null origin", "http://localhost:8080/users", "null", "http://localhost:8080/form", false},
+		{"referer fallback", "http://localhost:8080/users", "", "http://localhost:8080/form?id=1", true},
+		{"referer mismatch", "http://localhost:8080/users", "", "http://localhost.evil.com/form", false},
+		{"relative referer", "http://localhost:8080/users", "", "/form", false},
+		{"missing headers", "http://localhost:8080/users", "", "", false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			req := httptest.NewRequest(MethodPost, tc.target, nil)
+			if len(tc.origin) > 0 {
+				req.Header.Set(HeaderOrigin, tc.origin)
+			}
+			if len(tc.referer) > 0 {
+				req.Header.Set(HeaderReferer, tc.referer)
+			}
+			aahReq := AcquireRequest(req)
+			assert.Equal(t, tc.sameOrigin, aahReq.IsSameOrigin())
+			ReleaseRequest(aahReq)
+		})
+	}
+}
+
+func TestHTTPRequestRefererMatches(t *testing.T) {
+	allowed := []string{"https://example.com", "https://*.aahframework.org"}
+	testcases := []struct {
+		referer string
+		matched bool
+	}{
+		{"https://example.com/checkout?step=2", true},
+		{"HTTPS://Example.COM/", true},
+		{"https://docs.aahframework.org/security.html", true},
+		{"http://example.com/checkout", false},
+		{"https://example.com.evil.com/checkout", false},
+		{"/checkout", false},
+		{"", false},
+	}
+
+	for _, tc := range testcases {
+		req := httptest.NewRequest(MethodPost, "https://example.com/orders", nil)
+		if len(tc.referer) > 0 {
+			req.Header.Set(HeaderReferer, tc.referer)
+		}
+		aahReq := AcquireRequest(req)
+		assert.Equal(t, tc.matched, aahReq.RefererMatches(allowed), tc.referer)
+		ReleaseRequest(aahReq)
+	}
+}
+
+func TestHTTPRequestSecureCompare(t *testing.T) {
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/reports", nil)
+	req.Header.Set("X-API-Key", "s3cr3t-t0k3n")
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	testcases := []struct {
+		provided, expected string
+		equal              bool
+	}{
+		{"s3cr3t-t0k3n", "s3cr3t-t0k3n", true},
+		{"", "", true},
+		{"s3cr3t-t0k3x", "s3cr3t-t0k3n", false},
+		{"s3cr3t", "s3cr3t-t0k3n", false},
+		{"s3cr3t-t0k3n-extra", "s3cr3t-t0k3n", false},
+		{"", "s3cr3t-t0k3n", false},
+		{"S3CR3T-T0K3N", "s3cr3t-t0k3n", false},
+	}
+	for _, tc := range testcases {
+		assert.Equal(t, tc.equal, aahReq.SecureCompare(tc.provided, tc.expected), tc.provided)
+	}
+
+	assert.True(t, aahReq.VerifyHeaderToken("X-API-Key", "s3cr3t-t0k3n"))
+	assert.False(t, aahReq.VerifyHeaderToken("X-API-Key", "s3cr3t"))
+	assert.False(t, aahReq.VerifyHeaderToken("X-Missing-Key", "s3cr3t-t0k3n"))
+	assert.False(t, aahReq.VerifyHeaderToken("X-Missing-Key", ""))
+}
+
+func TestHTTPRequestNotModified(t *testing.T) {
+	modtime := time.Date(2018, 6, 15, 10, 30, 45, 500, time.UTC)
+	lastModified := modtime.Format(http.TimeFormat)
+	testcases := []struct {
+		label       string
+		method      string
+		headers     map[string]string
+		etag        string
+		notModified bool
+	}{
+		{"no conditional headers", MethodGet, nil, `"v1"`, false},
+		{"etag match", MethodGet, map[string]string{HeaderIfNoneMatch: `"v1"`}, `"v1"`, true},
+		{"etag match unquoted", MethodHead, map[string]string{HeaderIfNoneMatch: `"v1"`}, "v1", true},
+		{"etag weak match", MethodGet, map[string]string{HeaderIfNoneMatch: `W/"v1"`}, `"v1"`, true},
+		{"etag list match", MethodGet, map[string]string{HeaderIfNoneMatch: `"v0", W/"v1"`}, `W/"v1"`, true},
+		{"etag mismatch", MethodGet, map[string]string{HeaderIfNoneMatch: `"v0"`}, `"v1"`, false},
+		{"etag any", MethodGet, map[string]string{HeaderIfNoneMatch: "*"}, `"v1"`, true},
+		{"etag any without current etag", MethodGet, map[string]string{HeaderIfNoneMatch: "*"}, "", false},
+		{"etag takes precedence", MethodGet, map[string]string{
+			HeaderIfNoneMatch: `"v0"`, HeaderIfModifiedSince: lastModified}, `"v1"`, false},
+		{"modified since equal", MethodGet, map[string]string{HeaderIfModifiedSince: lastModified}, "", true},
+		{"modified since later", MethodGet, map[string]string{
+			HeaderIfModifiedSince: modtime.Add(time.Hour).Format(http.TimeFormat)}, "", true},
+		{"modified since earlier", MethodGet, map[string]string{
+			HeaderIfModifiedSince: modtime.Add(-time.Second).Format(http.TimeFormat)}, "", false},
+		{"modified since invalid", MethodGet, map[string]string{HeaderIfModifiedSince: "yesterday"}, "", false},
+		{"not applicable for POST", MethodPost, map[string]string{HeaderIfNoneMatch: `"v1"`}, `"v1"`, false},
+	}
+
+	for _, tc := range testcases {
+		req := httptest.NewRequest(tc.method, "http://localhost:8080/assets/app.js", nil)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		aahReq := AcquireRequest(req)
+		assert.Equal(t, tc.notModified, aahReq.NotModified(tc.etag, modtime), tc.label)
+		ReleaseRequest(aahReq)
+	}
+
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/assets/app.js", nil)
+	req.Header.Set(HeaderIfModifiedSince, lastModified)
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+	assert.False(t, aahReq.NotModified("", time.Time{}))
+}
+
+func TestHTTPRequestRedactedParams(t *testing.T) {
+	form := url.Values{}
+	form.Add("username", "jeeva")
+	form.Add("Password", "welcome123")
+	form.Add("ssn", "123-45-6789")
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/login?API_KEY=abc&next=/home&Token=xyz",
+		strings.NewReader(form.Encode()))
+	req.Header.Set(HeaderContentType, ContentTypeForm.String())
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	// form not parsed yet
+	assert.Equal(t, map[string]string{"API_KEY": "***", "next": "/home", "Token": "***"},
+		aahReq.RedactedParams())
+
+	_ = req.ParseForm()
+	assert.Equal(t, map[string]string{
+		"API_KEY":  "***",
+		"next":     "/home",
+		"Token":    "***",
+		"username": "jeeva",
+		"Password": "***",
+		"ssn":      "***",
+	}, aahReq.RedactedParams("SSN"))
+
+	defer func(keys []string) { RedactParamKeys = keys }(RedactParamKeys)
+	RedactParamKeys = append(RedactParamKeys, "next")
+	assert.Equal(t, "***", aahReq.RedactedParams()["next"])
+	assert.Equal(t, "123-45-6789", aahReq.RedactedParams()["ssn"])
+}
+
+func TestHTTPRequestIsBot(t *testing.T) {
+	testcases := []struct {
+		userAgent string
+		botName   string
+	}{
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", "Googlebot"},
+		{"Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", "Bingbot"},
+		{"Mozilla/5.0 (compatible; Yahoo! Slurp; http://help.yahoo.com/help/us/ysearch/slurp)", "Slurp"},
+		{"facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", "facebookexternalhit"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_13_5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/67.0.3396.99 Safari/537.36", ""},
+		{"", ""},
+	}
+
+	for _, tc := range testcases {
+		req := httptest.NewRequest(MethodGet, "http://localhost:8080/", nil)
+		req.Header.Set(HeaderUserAgent, tc.userAgent)
+		aahReq := AcquireRequest(req)
+		assert.Equal(t, tc.botName, aahReq.BotName())
+		assert.Equal(t, len(tc.botName) > 0, aahReq.IsBot())
+		ReleaseRequest(aahReq)
+	}
+
+	defer func(signatures []string) { botSignatures = signatures }(botSignatures)
+	SetBotSignatures([]string{"", "aah-monitor"})
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/", nil)
+	req.Header.Set(HeaderUserAgent, "AAH-Monitor/1.0")
+	aahReq := AcquireRequest(req)
+	assert.Equal(t, "aah-monitor", aahReq.BotName())
+	req.Header.Set(HeaderUserAgent, "Googlebot/2.1")
+	assert.False(t, aahReq.IsBot())
+	ReleaseRequest(aahReq)
+}
+
+func TestHTTPRequestDeviceType(t *testing.T) {
+	testcases := []struct {
+		label     string
+		userAgent string
+		device    string
+	}{
+		{"iphone", "Mozilla/5.0 (iPhone; CPU iPhone OS 11_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/11.0 Mobile/15E148 Safari/604.1", DeviceMobile},
+		{"android phone", "Mozilla/5.0 (Linux; Android 8.0.0; SM-G960F Build/R16NW) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/62.0.3202.84 Mobile Safari/537.36", DeviceMobile},
+		{"windows phone", "Mozilla/5.0 (Windows Phone 10.0; Android 6.0.1; Microsoft; Lumia 950) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/52.0.2743.116 Mobile Safari/537.36 Edge/15.15063", DeviceMobile},
+		{"ipad", "Mozilla/5.0 (iPad; CPU OS 11_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/11.0 Mobile/15E148 Safari/604.1", DeviceTablet},
+		{"android tablet", "Mozilla/5.0 (Linux; Android 7.0; SM-T827R4 Build/NRD90M) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/60.0.3112.116 Safari/537.36", DeviceTablet},
+		{"kindle", "Mozilla/5.0 (Linux; U; en-us; KFAPWI Build/JDQ39) AppleWebKit/535.19 (KHTML, like Gecko) Silk/3.13 Safari/535.19 Silk-Accelerated=true", DeviceTablet},
+		{"mac chrome", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_13_5) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/67.0.3396.99 Safari/537.36", DeviceDesktop},
+		{"windows firefox", "Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:61.0) Gecko/20100101 Firefox/61.0", DeviceDesktop},
+		{"googlebot", "Mozilla/5.0 (Linux; Android 6.0.1; Nexus 5X Build/MMB29P) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/41.0.2272.96 Mobile Safari/537.36 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", DeviceBot},
+		{"empty", "", DeviceDesktop},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			req := httptest.NewRequest(MethodGet, "http://localhost:8080/", nil)
+			req.Header.Set(HeaderUserAgent, tc.userAgent)
+			aahReq := AcquireRequest(req)
+			assert.Equal(t, tc.device, aahReq.DeviceType())
+			ReleaseRequest(aahReq)
+		})
+	}
+
+	// custom signatures
+	defer func(signatures map[string][]string) {
+		deviceSignatures = signatures
+	}(map[string][]string{DeviceTablet: deviceSignatures[DeviceTablet], DeviceMobile: deviceSignatures[DeviceMobile]})
+	assert.Nil(t, SetDeviceSignatures(DeviceMobile, []string{"aah-watch"}))
+	assert.Equal(t, "ahttp: unsupported device type 'bot'", SetDeviceSignatures(DeviceBot, nil).Error())
+
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/", nil)
+	req.Header.Set(HeaderUserAgent, "AAH-Watch/1.0")
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+	assert.Equal(t, DeviceMobile, aahReq.DeviceType())
+	req.Header.Set(HeaderUserAgent, "Mozilla/5.0 (iPhone; CPU iPhone OS 11_4 like Mac OS X) Mobile/15E148")
+	assert.Equal(t, DeviceDesktop, aahReq.DeviceType())
+}
+
+func TestHTTPRequestWithQuery(t *testing.T) {
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/users?status=active&tag=a&tag=b&page=2", nil)
+	req.Header.Set(HeaderXForwardedProto, "https")
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	u := aahReq.WithQuery("page", "3")
+	assert.Equal(t, "https://localhost:8080/users?page=3&status=active&tag=a&tag=b", u.String())
+
+	u = aahReq.WithQuery("sort", "name")
+	assert.Equal(t, "https://localhost:8080/users?page=2&sort=name&status=active&tag=a&tag=b", u.String())
+
+	u = aahReq.WithoutQuery("tag")
+	assert.Equal(t, "https://localhost:8080/users?page=2&status=active", u.String())
+
+	u = aahReq.WithoutQuery("unknown")
+	assert.Equal(t, "https://localhost:8080/users?page=2&status=active&tag=a&tag=b", u.String())
+
+	// request is not modified
+	assert.Equal(t, "status=active&tag=a&tag=b&page=2", aahReq.URL().RawQuery)
+	assert.Equal(t, "2", aahReq.QueryValue("page"))
+}
+
+func TestHTTPRequestTLS(t *testing.T) {
+	req := httptest.NewRequest(MethodGet, "http://localhost:8080/", nil)
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	version, ok := aahReq.TLSVersion()
+	assert.False(t, ok)
+	assert.Equal(t, uint16(0), version)
+	cipher, ok := aahReq.TLSCipherSuite()
+	assert.False(t, ok)
+	assert.Equal(t, uint16(0), cipher)
+	assert.Equal(t, "", aahReq.TLSVersionName())
+
+	testcases := []struct {
+		version uint16
+		name    string
+	}{
+		{tls.VersionTLS10, "TLS1.0"},
+		{tls.VersionTLS11, "TLS1.1"},
+		{tls.VersionTLS12, "TLS1.2"},
+		{0x0304, "TLS1.3"},
+		{0x0300, "unknown(0x0300)"}, // SSL3.0 is not supported by crypto/tls
+		{0x0999, "unknown(0x0999)"},
+	}
+	for _, tc := range testcases {
+		req.TLS = &tls.ConnectionState{Version: tc.version, CipherSuite: tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256}
+		version, ok = aahReq.TLSVersion()
+		assert.True(t, ok)
+		assert.Equal(t, tc.version, version)
+		assert.Equal(t, tc.name, aahReq.TLSVersionName())
+		cipher, ok = aahReq.TLSCipherSuite()
+		assert.True(t, ok)
+		assert.Equal(t, tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256, cipher)
+	}
+}
+
+func TestHTTPRequestProtocolCapabilities(t *testing.T) {
+	testcases := []struct {
+		label     string
+		method    string
+		proto     string
+		headers   map[string]string
+		http2     bool
+		websocket bool
+	}{
+		{"http/1.1", MethodGet, "HTTP/1.1", nil, false, false},
+		{"http/2", MethodGet, "HTTP/2.0", nil, true, false},
+		{"websocket upgrade", MethodGet, "HTTP/1.1",
+			map[string]string{HeaderConnection: "Upgrade", HeaderUpgrade: "websocket"}, false, true},
+		{"websocket upgrade token list", MethodGet, "HTTP/1.1",
+			map[string]string{HeaderConnection: "keep-alive, Upgrade", HeaderUpgrade: "WebSocket"}, false, true},
+		{"websocket without connection upgrade", MethodGet, "HTTP/1.1",
+			map[string]string{HeaderConnection: "keep-alive", HeaderUpgrade: "websocket"}, false, false},
+		{"h2c upgrade", MethodGet, "HTTP/1.1",
+			map[string]string{HeaderConnection: "Upgrade, HTTP2-Settings", HeaderUpgrade: "h2c"}, false, false},
+		{"websocket upgrade over http/1.0", MethodGet, "HTTP/1.0",
+			map[string]string{HeaderConnection: "Upgrade", HeaderUpgrade: "websocket"}, false, false},
+		{"websocket upgrade over http/2", MethodGet, "HTTP/2.0",
+			map[string]string{HeaderConnection: "Upgrade", HeaderUpgrade: "websocket"}, true, false},
+		{"websocket upgrade with POST", MethodPost, "HTTP/1.1",
+			map[string]string{HeaderConnection: "Upgrade", HeaderUpgrade: "websocket"}, false, false},
+	}
+
+	for _, tc := range testcases {
+		req := httptest.NewRequest(tc.method, "http://localhost:8080/ws", nil)
+		req.Proto = tc.proto
+		req.ProtoMajor, req.ProtoMinor, _ = http.ParseHTTPVersion(tc.proto)
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+		aahReq := AcquireRequest(req)
+		assert.Equal(t, tc.http2, aahReq.SupportsHTTP2(), tc.label)
+		assert.Equal(t, tc.websocket, aahReq.SupportsWebSocketUpgrade(), tc.label)
+		ReleaseRequest(aahReq)
+	}
+}
+
 func TestRequestSchemeDerived(t *testing.T) {
 	req := httptest.NewRequest("GET", "http://127.0.0.1:8080/welcome.html", nil)
 	assert.Equal(t, "http", Scheme(req))
@@ -262,6 +1208,20 @@ func TestRequestSaveFileForExistingFile(t *testing.T) {
 	assert.Equal(t, int64(0), size)
 }
 
+func TestHTTPRequestRoutePattern(t *testing.T) {
+	req := AcquireRequest(createRawHTTPRequest(HeaderAccept, "*/*"))
+	assert.Equal(t, "", req.RoutePattern())
+
+	req.URLParams = URLParams{{Key: "id", Value: "42"}}
+	req.SetRoutePattern("/users/:id")
+	assert.Equal(t, "/users/:id", req.RoutePattern())
+	assert.Equal(t, "42", req.PathValue("id"))
+
+	req.Reset()
+	assert.Equal(t, "", req.RoutePattern())
+	assert.Equal(t, 0, len(req.URLParams))
+}
+
 func TestURLParams(t *testing.T) {
 	params := URLParams{
 		{
@@ -284,6 +1244,134 @@ func TestURLParams(t *testing.T) {
 	assert.Equal(t, map[string]string{"test1": "value1", "test2": "value2", "test3": "value3"}, params.ToMap())
 }
 
+func TestURLParamsMerge(t *testing.T) {
+	params := URLParams{{Key: "test1", Value: "value1"}, {Key: "test2", Value: "value2"}}
+
+	merged := params.Merge(URLParams{{Key: "test2", Value: "newvalue2"}, {Key: "test3", Value: "value3"}})
+	assert.Equal(t, URLParams{
+		{Key: "test1", Value: "value1"},
+		{Key: "test2", Value: "newvalue2"},
+		{Key: "test3", Value: "value3"},
+	}, merged)
+
+	// source is not mutated
+	assert.Equal(t, "value2", params.Get("test2"))
+
+	// nil params
+	var empty URLParams
+	assert.Equal(t, 2, len(empty.Merge(params)))
+	assert.Equal(t, 2, len(params.Merge(nil)))
+}
+
+func TestRequestForwardingHeaders(t *testing.T) {
+	// direct client, no upstream proxy
+	req := httptest.NewRequest(MethodGet, "http://aahframework.org:8080/users", nil)
+	req.RemoteAddr = "192.168.0.10:52301"
+	aahReq := AcquireRequest(req)
+	hdr := aahReq.ForwardingHeaders()
+	assert.Equal(t, "192.168.0.10", hdr.Get(HeaderXForwardedFor))
+	assert.Equal(t, "http", hdr.Get(HeaderXForwardedProto))
+	assert.Equal(t, "aahframework.org", hdr.Get(HeaderXForwardedHost))
+	assert.Equal(t, "8080", hdr.Get(HeaderXForwardedPort))
+	ReleaseRequest(aahReq)
+
+	// existing chain grows with immediate peer
+	req = httptest.NewRequest(MethodGet, "http://internal:9000/users", nil)
+	req.RemoteAddr = "10.0.0.2:41000"
+	req.Header.Add(HeaderXForwardedFor, "203.0.113.7, 198.51.100.3")
+	req.Header.Add(HeaderXForwardedFor, "10.0.0.1")
+	req.Header.Set(HeaderXForwardedProto, "https")
+	req.Header.Set(HeaderXForwardedHost, "aahframework.org")
+	aahReq = AcquireRequest(req)
+	hdr = aahReq.ForwardingHeaders()
+	assert.Equal(t, "203.0.113.7, 198.51.100.3, 10.0.0.1, 10.0.0.2", hdr.Get(HeaderXForwardedFor))
+	assert.Equal(t, "https", hdr.Get(HeaderXForwardedProto))
+	assert.Equal(t, "aahframework.org", hdr.Get(HeaderXForwardedHost))
+	assert.Equal(t, "443", hdr.Get(HeaderXForwardedPort))
+
+	// request headers are not mutated
+	assert.Equal(t, []string{"203.0.113.7, 198.51.100.3", "10.0.0.1"}, req.Header[HeaderXForwardedFor])
+	ReleaseRequest(aahReq)
+
+	// upstream port is preserved
+	req = httptest.NewRequest(MethodGet, "http://aahframework.org/users", nil)
+	req.RemoteAddr = "[::1]:41000"
+	req.Header.Set(HeaderXForwardedPort, "8443")
+	aahReq = AcquireRequest(req)
+	hdr = aahReq.ForwardingHeaders()
+	assert.Equal(t, "::1", hdr.Get(HeaderXForwardedFor))
+	assert.Equal(t, "8443", hdr.Get(HeaderXForwardedPort))
+	ReleaseRequest(aahReq)
+}
+
+func TestHTTPRequestMergeParams(t *testing.T) {
+	req, _ := http.NewRequest("POST", "http://localhost:8080/users/100?page=1&sort=name",
+		strings.NewReader("name=jeeva&role=admin"))
+	req.Header.Add(HeaderContentType, ContentTypeForm.String())
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+	aahReq.URLParams = URLParams{{Key: "id", Value: "100"}, {Key: "lang", Value: "en"}}
+
+	buf := new(bytes.Buffer)
+	multipartWriter := multipart.NewWriter(buf)
+	assert.Nil(t, multipartWriter.WriteField("role", "user"))
+	w, err := multipartWriter.CreateFormFile("avatar", "aah.txt")
+	assert.Nil(t, err)
+	_, _ = w.Write([]byte("aah web framework"))
+	ess.CloseQuietly(multipartWriter)
+
+	oreq, _ := http.NewRequest("POST", "http://localhost:8080/profile?page=2&debug=true", buf)
+	oreq.Header.Add(HeaderContentType, multipartWriter.FormDataContentType())
+	other := AcquireRequest(oreq)
+	defer ReleaseRequest(other)
+	other.URLParams = URLParams{{Key: "id", Value: "200"}, {Key: "tab", Value: "photos"}}
+	assert.Nil(t, oreq.ParseMultipartForm(32 << 20))
+
+	aahReq.MergeParams(other)
+
+	// path
+	assert.Equal(t, URLParams{{Key: "id", Value: "200"}, {Key: "lang", Value: "en"},
+		{Key: "tab", Value: "photos"}}, aahReq.URLParams)
+
+	// query
+	assert.Equal(t, []string{"1", "2"}, aahReq.QueryArrayValue("page"))
+	assert.Equal(t, "name", aahReq.QueryValue("sort"))
+	assert.Equal(t, "true", aahReq.QueryValue("debug"))
+
+	// form
+	assert.Equal(t, []string{"admin", "user"}, aahReq.FormArrayValue("role"))
+	assert.Equal(t, "jeeva", aahReq.FormValue("name"))
+	assert.Equal(t, []string{"1", "2"}, aahReq.FormArrayValue("page"))
+
+	// file
+	fhs := aahReq.FormFileHeaders("avatar")
+	assert.Equal(t, 1, len(fhs))
+	assert.Equal(t, "aah.txt", fhs[0].Filename)
+
+	// given request is not modified
+	assert.Equal(t, "200", other.PathValue("id"))
+	assert.Equal(t, []string{"2"}, other.QueryArrayValue("page"))
+	assert.Equal(t, []string{"user"}, other.FormArrayValue("role"))
+
+	// nil request
+	aahReq.MergeParams(nil)
+	assert.Equal(t, 3, len(aahReq.URLParams))
+
+	// given request form is not parsed, its body is not read
+	oreq, _ = http.NewRequest("POST", "http://localhost:8080/profile?debug=false",
+		strings.NewReader("role=guest"))
+	oreq.Header.Add(HeaderContentType, ContentTypeForm.String())
+	unparsed := AcquireRequest(oreq)
+	defer ReleaseRequest(unparsed)
+	aahReq.MergeParams(unparsed)
+	assert.Nil(t, oreq.Form)
+	assert.Equal(t, []string{"true", "false"}, aahReq.FormArrayValue("debug"))
+	assert.Equal(t, []string{"admin", "user"}, aahReq.FormArrayValue("role"))
+	b, err := ioutil.ReadAll(oreq.Body)
+	assert.Nil(t, err)
+	assert.Equal(t, "role=guest", string(b))
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // test unexported methods
 //___________________________________
@@ -293,6 +1381,67 @@ func createRequestWithHost(host, remote string) *http.Request {
 	return &http.Request{URL: url, Host: host, RemoteAddr: remote, Header: http.Header{}}
 }
 
+func TestRequestSaveFilesResult(t *testing.T) {
+	dstPath, err := ioutil.TempDir("", "aah-uploads")
+	assert.Nil(t, err)
+	defer func() { _ = os.RemoveAll(dstPath) }()
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dstPath, "exists.txt"), []byte("old"), 0644))
+
+	buf := new(bytes.Buffer)
+	multipartWriter := multipart.NewWriter(buf)
+	uploads := []struct{ name, content string }{
+		{"aah.txt", "aah framework"},
+		{"exists.txt", "new content"},
+		{"../../escape.txt", "escape"},
+		{"empty.txt", ""},
+	}
+	for _, u := range uploads {
+		w, err := multipartWriter.CreateFormFile("files", u.name)
+		assert.Nil(t, err)
+		_, _ = w.Write([]byte(u.content))
+	}
+	ess.CloseQuietly(multipartWriter)
+
+	req := httptest.NewRequest(MethodPost, "http://localhost:8080/upload", buf)
+	req.Header.Set(HeaderContentType, multipartWriter.FormDataContentType())
+	aahReq := AcquireRequest(req)
+	defer ReleaseRequest(aahReq)
+
+	results := aahReq.SaveFilesResult("files", dstPath)
+	assert.Equal(t, 4, len(results))
+
+	assert.Equal(t, "aah.txt", results[0].Filename)
+	assert.Equal(t, int64(13), results[0].Size)
+	assert.Nil(t, results[0].Err)
+	b, _ := ioutil.ReadFile(filepath.Join(dstPath, "aah.txt"))
+	assert.Equal(t, "aah framework", string(b))
+
+	assert.Equal(t, "exists.txt", results[1].Filename)
+	assert.Equal(t, int64(0), results[1].Size)
+	assert.NotNil(t, results[1].Err)
+	assert.True(t, strings.Contains(results[1].Err.Error(), "file exists"))
+	b, _ = ioutil.ReadFile(filepath.Join(dstPath, "exists.txt"))
+	assert.Equal(t, "old", string(b))
+
+	assert.Equal(t, "escape.txt", results[2].Filename)
+	assert.Equal(t, int64(6), results[2].Size)
+	assert.Nil(t, results[2].Err)
+	assert.True(t, ess.IsFileExists(filepath.Join(dstPath, "escape.txt")))
+
+	assert.Equal(t, "empty.txt", results[3].Filename)
+	assert.Equal(t, int64(0), results[3].Size)
+	assert.Nil(t, results[3].Err)
+
+	// no files for key
+	assert.Equal(t, 0, len(aahReq.SaveFilesResult("unknown-key", dstPath)))
+
+	// validation
+	results = aahReq.SaveFilesResult("", dstPath)
+	assert.Equal(t, "ahttp: key or dstPath is empty", results[0].Err.Error())
+	results = aahReq.SaveFilesResult("files", filepath.Join(dstPath, "aah.txt"))
+	assert.Equal(t, "ahttp: dstPath should be a directory", results[0].Err.Error())
+}
+
 func setUpRequestSaveFile(t *testing.T) (*Request, string, func()) {
 	buf := new(bytes.Buffer)
 	multipartWriter := multipart.NewWriter(buf)
@@ -0,0 +1,249 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServeContent method replies to the request using the content from given
+// `io.ReadSeeker`, it's analogous to `http.ServeContent` driven by
+// `ahttp.Request`. It handles-
+//
+//  - preconditions `If-Match` and `If-Unmodified-Since`, replies with
+//    `412 Precondition Failed`
+//
+//  - conditional `If-None-Match` and `If-Modified-Since` via method
+//    `Request.NotModified`, replies with `304 Not Modified`
+//
+//  - byte ranges via method `Request.Ranges` honoring `If-Range`, replies
+//    with `206 Partial Content`; multiple ranges as `multipart/byteranges`
+//    and `416 Range Not Satisfiable` for unsatisfiable ranges
+//
+// Entity tag is taken from response header `ETag` if set by the handler.
+// Response header `Content-Type` is detected from content when not set.
+// Header `Last-Modified` is set unless modtime is zero.
+//
+// 	For e.g.:
+// 		f, _ := os.Open(filename)
+// 		defer f.Close()
+// 		fi, _ := f.Stat()
+// 		req.ServeContent(w, fi.ModTime(), f)
+func (r *Request) ServeContent(w http.ResponseWriter, modtime time.Time, content io.ReadSeeker) {
+	size, err := contentSize(content)
+	if err != nil {
+		http.Error(w, "seeker can't seek", http.StatusInternalServerError)
+		return
+	}
+
+	hdr := w.Header()
+	if !modtime.IsZero() && modtime.Unix() != 0 {
+		hdr.Set(HeaderLastModified, modtime.UTC().Format(http.TimeFormat))
+	}
+	etag := hdr.Get(HeaderETag)
+
+	if !r.preconditionMet(etag, modtime) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	if r.NotModified(etag, modtime) {
+		hdr.Del(HeaderContentType)
+		hdr.Del(HeaderContentLength)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if len(hdr.Get(HeaderContentType)) == 0 {
+		var buf [sniffLen]byte
+		n, _ := io.ReadFull(content, buf[:])
+		hdr.Set(HeaderContentType, http.DetectContentType(buf[:n]))
+		if _, err = content.Seek(0, io.SeekStart); err != nil {
+			http.Error(w, "seeker can't seek", http.StatusInternalServerError)
+			return
+		}
+	}
+	hdr.Set(HeaderAcceptRanges, "bytes")
+
+	var ranges []HTTPRange
+	if r.isRangeApplicable(etag, modtime) {
+		if ranges, err = r.Ranges(size); err != nil {
+			hdr.Set(HeaderContentRange, fmt.Sprintf("bytes */%d", size))
+			http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		if sumRangesLength(ranges) > size {
+			// ranges overhead is more than the content, send the content
+			ranges = nil
+		}
+	}
+
+	switch len(ranges) {
+	case 0:
+		hdr.Set(HeaderContentLength, strconv.FormatInt(size, 10))
+		w.WriteHeader(http.StatusOK)
+		if r.Method != MethodHead {
+			_, _ = io.CopyN(w, content, size)
+		}
+	case 1:
+		ra := ranges[0]
+		if _, err = content.Seek(ra.Start, io.SeekStart); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestedRangeNotSatisfiable)
+			return
+		}
+		hdr.Set(HeaderContentRange, ra.ContentRange(size))
+		hdr.Set(HeaderContentLength, strconv.FormatInt(ra.Length, 10))
+		w.WriteHeader(http.StatusPartialContent)
+		if r.Method != MethodHead {
+			_, _ = io.CopyN(w, content, ra.Length)
+		}
+	default:
+		ctype := hdr.Get(HeaderContentType)
+		boundary := multipart.NewWriter(ioutil.Discard).Boundary()
+		hdr.Set(HeaderContentType, "multipart/byteranges; boundary="+boundary)
+		hdr.Set(HeaderContentLength, strconv.FormatInt(rangesMIMESize(ranges, ctype, size, boundary), 10))
+		w.WriteHeader(http.StatusPartialContent)
+		if r.Method != MethodHead {
+			_ = writeRangesMIME(w, content, ranges, ctype, size, boundary)
+		}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// preconditionMet method evaluates headers `If-Match` and, in its absence,
+// `If-Unmodified-Since` per RFC 7232.
+func (r *Request) preconditionMet(etag string, modtime time.Time) bool {
+	if im := r.Header.Get(HeaderIfMatch); len(im) > 0 {
+		return etagStrongMatch(im, etag)
+	}
+	ius := r.Header.Get(HeaderIfUnmodifiedSince)
+	if len(ius) == 0 || modtime.IsZero() || modtime.Unix() == 0 {
+		return true
+	}
+	t, err := http.ParseTime(ius)
+	if err != nil {
+		return true
+	}
+	return !modtime.Truncate(time.Second).After(t)
+}
+
+// isRangeApplicable method returns true if header `Range` to be honored as
+// per header `If-Range` and the request method.
+func (r *Request) isRangeApplicable(etag string, modtime time.Time) bool {
+	if r.Method != MethodGet && r.Method != MethodHead {
+		return false
+	}
+	ir := strings.TrimSpace(r.Header.Get(HeaderIfRange))
+	if len(ir) == 0 {
+		return true
+	}
+	if strings.HasPrefix(ir, `"`) || strings.HasPrefix(ir, "W/") {
+		return etagStrongMatch(ir, etag)
+	}
+	t, err := http.ParseTime(ir)
+	return err == nil && !modtime.IsZero() && modtime.Truncate(time.Second).Equal(t)
+}
+
+// etagStrongMatch method reports whether given header value matches the etag
+// with strong comparison, weak entity tags never match.
+func etagStrongMatch(header, etag string) bool {
+	etag = strings.TrimSpace(etag)
+	if len(etag) == 0 || strings.HasPrefix(etag, "W/") {
+		return false
+	}
+	etag = normalizeETag(etag)
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || (!strings.HasPrefix(v, "W/") && normalizeETag(v) == etag) {
+			return true
+		}
+	}
+	return false
+}
+
+// contentSize method returns the size of content and rewinds it.
+func contentSize(content io.Seeker) (int64, error) {
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = content.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if size < 0 {
+		return 0, errors.New("ahttp: negative content size")
+	}
+	return size, nil
+}
+
+func sumRangesLength(ranges []HTTPRange) int64 {
+	var n int64
+	for _, ra := range ranges {
+		n += ra.Length
+	}
+	return n
+}
+
+func rangePartHeader(ra HTTPRange, ctype string, size int64) textproto.MIMEHeader {
+	return textproto.MIMEHeader{
+		HeaderContentRange: {ra.ContentRange(size)},
+		HeaderContentType:  {ctype},
+	}
+}
+
+// rangesMIMESize method returns the size of `multipart/byteranges` body.
+func rangesMIMESize(ranges []HTTPRange, ctype string, size int64, boundary string) int64 {
+	var cw countingWriter
+	mw := multipart.NewWriter(&cw)
+	_ = mw.SetBoundary(boundary)
+	for _, ra := range ranges {
+		_, _ = mw.CreatePart(rangePartHeader(ra, ctype, size))
+		cw += countingWriter(ra.Length)
+	}
+	_ = mw.Close()
+	return int64(cw)
+}
+
+// writeRangesMIME method writes the given ranges of the content as
+// `multipart/byteranges` body.
+func writeRangesMIME(w io.Writer, content io.ReadSeeker, ranges []HTTPRange, ctype string,
+	size int64, boundary string) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+	for _, ra := range ranges {
+		part, err := mw.CreatePart(rangePartHeader(ra, ctype, size))
+		if err != nil {
+			return err
+		}
+		if _, err = content.Seek(ra.Start, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err = io.CopyN(part, content, ra.Length); err != nil {
+			return err
+		}
+	}
+	return mw.Close()
+}
+
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPRequestServeContent(t *testing.T) {
+	content := "0123456789abcdefghij"
+	modtime := time.Date(2018, 5, 14, 10, 24, 11, 0, time.UTC)
+	serve := func(method string, hdr map[string]string, etag string) *httptest.ResponseRecorder {
+		raw := httptest.NewRequest(method, "http://localhost:8080/file.txt", nil)
+		for k, v := range hdr {
+			raw.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		if len(etag) > 0 {
+			w.Header().Set(HeaderETag, etag)
+		}
+		AcquireRequest(raw).ServeContent(w, modtime, strings.NewReader(content))
+		return w
+	}
+
+	// no range
+	w := serve(MethodGet, nil, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, content, w.Body.String())
+	assert.Equal(t, "20", w.Header().Get(HeaderContentLength))
+	assert.Equal(t, "bytes", w.Header().Get(HeaderAcceptRanges))
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get(HeaderContentType))
+	assert.Equal(t, "Mon, 14 May 2018 10:24:11 GMT", w.Header().Get(HeaderLastModified))
+
+	// HEAD
+	w = serve(MethodHead, nil, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 0, w.Body.Len())
+	assert.Equal(t, "20", w.Header().Get(HeaderContentLength))
+
+	// single range
+	w = serve(MethodGet, map[string]string{HeaderRange: "bytes=2-5"}, "")
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "2345", w.Body.String())
+	assert.Equal(t, "bytes 2-5/20", w.Header().Get(HeaderContentRange))
+	assert.Equal(t, "4", w.Header().Get(HeaderContentLength))
+
+	// multiple ranges
+	w = serve(MethodGet, map[string]string{HeaderRange: "bytes=0-1,-3"}, "")
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	mediaType, params, err := mime.ParseMediaType(w.Header().Get(HeaderContentType))
+	assert.Nil(t, err)
+	assert.Equal(t, "multipart/byteranges", mediaType)
+	assert.Equal(t, strconv.Itoa(w.Body.Len()), w.Header().Get(HeaderContentLength))
+	mr := multipart.NewReader(w.Body, params["boundary"])
+	for _, expected := range []struct{ contentRange, body string }{
+		{"bytes 0-1/20", "01"},
+		{"bytes 17-19/20", "hij"},
+	} {
+		part, err := mr.NextPart()
+		assert.Nil(t, err)
+		assert.Equal(t, expected.contentRange, part.Header.Get(HeaderContentRange))
+		assert.Equal(t, "text/plain; charset=utf-8", part.Header.Get(HeaderContentType))
+		b, _ := ioutil.ReadAll(part)
+		assert.Equal(t, expected.body, string(b))
+	}
+
+	// unsatisfiable range
+	w = serve(MethodGet, map[string]string{HeaderRange: "bytes=50-60"}, "")
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+	assert.Equal(t, "bytes */20", w.Header().Get(HeaderContentRange))
+
+	// 304 precondition hit
+	w = serve(MethodGet, map[string]string{HeaderIfNoneMatch: `"v1"`}, `"v1"`)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, 0, w.Body.Len())
+	w = serve(MethodGet, map[string]string{HeaderIfModifiedSince: "Mon, 14 May 2018 10:24:11 GMT"}, "")
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// 412 precondition failed
+	w = serve(MethodGet, map[string]string{HeaderIfMatch: `"v0"`}, `"v1"`)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	w = serve(MethodGet, map[string]string{HeaderIfUnmodifiedSince: "Sun, 13 May 2018 10:24:11 GMT"}, "")
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+
+	// If-Range mismatch sends full content
+	w = serve(MethodGet, map[string]string{HeaderRange: "bytes=2-5", HeaderIfRange: `"v0"`}, `"v1"`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, content, w.Body.String())
+	w = serve(MethodGet, map[string]string{HeaderRange: "bytes=2-5", HeaderIfRange: `"v1"`}, `"v1"`)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+}
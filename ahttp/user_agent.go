@@ -0,0 +1,128 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import "strings"
+
+// UserAgentInfo is the structured breakdown of HTTP header `User-Agent`,
+// refer to method `ahttp.ParseUserAgent`. Unknown values are empty.
+type UserAgentInfo struct {
+	Raw            string
+	Browser        string
+	BrowserVersion string
+	OS             string
+	IsMobile       bool
+}
+
+// uaToken maps the `User-Agent` product token into browser name, version is
+// read from the token itself or from versionToken if present.
+type uaToken struct {
+	token        string
+	name         string
+	versionToken string
+}
+
+var (
+	// order matters, Chromium based browsers carry `Chrome/` and `Safari/`
+	// tokens too
+	uaBrowsers = []uaToken{
+		{token: "Edg/", name: "Edge"},
+		{token: "EdgA/", name: "Edge"},
+		{token: "EdgiOS/", name: "Edge"},
+		{token: "Edge/", name: "Edge"},
+		{token: "OPR/", name: "Opera"},
+		{token: "Opera/", name: "Opera", versionToken: "Version/"},
+		{token: "SamsungBrowser/", name: "Samsung Internet"},
+		{token: "YaBrowser/", name: "Yandex"},
+		{token: "Firefox/", name: "Firefox"},
+		{token: "FxiOS/", name: "Firefox"},
+		{token: "CriOS/", name: "Chrome"},
+		{token: "Chromium/", name: "Chromium"},
+		{token: "Chrome/", name: "Chrome"},
+		{token: "Safari/", name: "Safari", versionToken: "Version/"},
+		{token: "MSIE ", name: "Internet Explorer"},
+		{token: "Trident/", name: "Internet Explorer", versionToken: "rv:"},
+	}
+
+	// order matters, iOS carries `like Mac OS X` and Android carries `Linux`
+	uaOSes = []uaToken{
+		{token: "Windows Phone", name: "Windows Phone"},
+		{token: "Windows", name: "Windows"},
+		{token: "iPhone", name: "iOS"},
+		{token: "iPad", name: "iOS"},
+		{token: "iPod", name: "iOS"},
+		{token: "Android", name: "Android"},
+		{token: "CrOS", name: "ChromeOS"},
+		{token: "Mac OS X", name: "macOS"},
+		{token: "Macintosh", name: "macOS"},
+		{token: "Linux", name: "Linux"},
+	}
+)
+
+// ParseUserAgent method parses the given `User-Agent` header value into
+// browser family, browser version, OS and mobile flag. It covers mainstream
+// browsers and OSes with lightweight token matching.
+//
+// Note: It's best-effort, `User-Agent` is client supplied and often spoofed,
+// it's meant for analytics and compatibility tweaks.
+func ParseUserAgent(ua string) *UserAgentInfo {
+	info := &UserAgentInfo{Raw: ua}
+	if len(ua) == 0 {
+		return info
+	}
+
+	for _, b := range uaBrowsers {
+		idx := strings.Index(ua, b.token)
+		if idx == -1 {
+			continue
+		}
+		info.Browser = b.name
+		if len(b.versionToken) > 0 {
+			info.BrowserVersion = uaTokenValue(ua, b.versionToken)
+		} else {
+			info.BrowserVersion = uaValue(ua[idx+len(b.token):])
+		}
+		break
+	}
+
+	for _, o := range uaOSes {
+		if strings.Contains(ua, o.token) {
+			info.OS = o.name
+			break
+		}
+	}
+
+	info.IsMobile = strings.Contains(ua, "Mobi") ||
+		strings.Contains(ua, "iPhone") || strings.Contains(ua, "iPod")
+
+	return info
+}
+
+// ParsedUserAgent method returns the parsed HTTP header `User-Agent`, it's
+// parsed once and cached on the request. Refer to `ahttp.ParseUserAgent`.
+func (r *Request) ParsedUserAgent() *UserAgentInfo {
+	if r.userAgent == nil {
+		r.userAgent = ParseUserAgent(r.UserAgent())
+	}
+	return r.userAgent
+}
+
+// uaTokenValue method returns the value of given token from `User-Agent`
+// otherwise empty string.
+func uaTokenValue(ua, token string) string {
+	idx := strings.Index(ua, token)
+	if idx == -1 {
+		return ""
+	}
+	return uaValue(ua[idx+len(token):])
+}
+
+// uaValue method returns the value up to the next delimiter.
+func uaValue(s string) string {
+	if idx := strings.IndexAny(s, " ;)"); idx != -1 {
+		return s[:idx]
+	}
+	return s
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ahttp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPParseUserAgent(t *testing.T) {
+	testcases := []struct {
+		label, ua, browser, version, os string
+		mobile                          bool
+	}{
+		{label: "chrome windows",
+			ua:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.109 Safari/537.36",
+			browser: "Chrome", version: "120.0.6099.109", os: "Windows"},
+		{label: "chrome android",
+			ua:      "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.6099.144 Mobile Safari/537.36",
+			browser: "Chrome", version: "120.0.6099.144", os: "Android", mobile: true},
+		{label: "chrome ios",
+			ua:      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) CriOS/120.0.6099.119 Mobile/15E148 Safari/604.1",
+			browser: "Chrome", version: "120.0.6099.119", os: "iOS", mobile: true},
+		{label: "safari macos",
+			ua:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/605.1.15",
+			browser: "Safari", version: "17.2", os: "macOS"},
+		{label: "safari iphone",
+			ua:      "Mozilla/5.0 (iPhone; CPU iPhone OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Mobile/15E148 Safari/604.1",
+			browser: "Safari", version: "17.2", os: "iOS", mobile: true},
+		{label: "safari ipad",
+			ua:      "Mozilla/5.0 (iPad; CPU OS 17_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.2 Safari/604.1",
+			browser: "Safari", version: "17.2", os: "iOS"},
+		{label: "firefox linux",
+			ua:      "Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0",
+			browser: "Firefox", version: "121.0", os: "Linux"},
+		{label: "firefox android",
+			ua:      "Mozilla/5.0 (Android 14; Mobile; rv:121.0) Gecko/121.0 Firefox/121.0",
+			browser: "Firefox", version: "121.0", os: "Android", mobile: true},
+		{label: "edge windows",
+			ua:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			browser: "Edge", version: "120.0.2210.91", os: "Windows"},
+		{label: "opera",
+			ua:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 OPR/106.0.0.0",
+			browser: "Opera", version: "106.0.0.0", os: "Windows"},
+		{label: "internet explorer 11",
+			ua:      "Mozilla/5.0 (Windows NT 6.1; Trident/7.0; rv:11.0) like Gecko",
+			browser: "Internet Explorer", version: "11.0", os: "Windows"},
+		{label: "unknown", ua: "curl/8.4.0"},
+		{label: "empty", ua: ""},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			info := ParseUserAgent(tc.ua)
+			assert.Equal(t, tc.ua, info.Raw)
+			assert.Equal(t, tc.browser, info.Browser)
+			assert.Equal(t, tc.version, info.BrowserVersion)
+			assert.Equal(t, tc.os, info.OS)
+			assert.Equal(t, tc.mobile, info.IsMobile)
+		})
+	}
+}
+
+func TestHTTPRequestParsedUserAgent(t *testing.T) {
+	req := AcquireRequest(createRawHTTPRequest(HeaderUserAgent,
+		"Mozilla/5.0 (X11; Ubuntu; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0"))
+	info := req.ParsedUserAgent()
+	assert.Equal(t, "Firefox", info.Browser)
+	assert.True(t, info == req.ParsedUserAgent(), "parsed once and cached")
+
+	req.Reset()
+	assert.Nil(t, req.userAgent)
+}
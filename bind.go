@@ -74,8 +74,7 @@ func BindMiddleware(ctx *Context, m *Middleware) {
 		}
 
 		// Prevent DDoS attacks by large HTTP request bodies by enforcing configured hard limit
-		// TODO: integrate the max bytes reader error into aah error handling flow
-		ctx.Req.Unwrap().Body = http.MaxBytesReader(ctx.Res, ctx.Req.Body(), ctx.route.MaxBodySize)
+		ctx.Req.LimitBody(ctx.Res, ctx.route.MaxBodySize)
 
 		// Set the tee reader if dump log enabled with request body enabled
 		if ctx.a.settings.DumpLogEnabled && ctx.a.dumpLog.logRequestBody {
@@ -175,6 +174,7 @@ type bindManager struct {
 func multipartFormParser(ctx *Context) flowResult {
 	if err := ctx.Req.Unwrap().ParseMultipartForm(ctx.route.MaxBodySize); err != nil {
 		ctx.Log().Errorf("Unable to parse multipart form: %s", err)
+		return replyBodyTooLarge(ctx, err)
 	}
 	return flowCont
 }
@@ -182,6 +182,18 @@ func multipartFormParser(ctx *Context) flowResult {
 func formParser(ctx *Context) flowResult {
 	if err := ctx.Req.Unwrap().ParseForm(); err != nil {
 		ctx.Log().Errorf("Unable to parse form: %s", err)
+		return replyBodyTooLarge(ctx, err)
+	}
+	return flowCont
+}
+
+// replyBodyTooLarge method replies `413 Request Entity Too Large` and aborts
+// the flow if given error is body limit error, otherwise continues.
+func replyBodyTooLarge(ctx *Context, err error) flowResult {
+	if err == ahttp.ErrBodyTooLarge {
+		ctx.Reply().Status(http.StatusRequestEntityTooLarge).
+			Error(newErrorWithData(ErrRequestBodyTooLarge, http.StatusRequestEntityTooLarge, err))
+		return flowAbort
 	}
 	return flowCont
 }
@@ -218,6 +230,9 @@ func (ctx *Context) parseParameters() ([]reflect.Value, *Error) {
 
 		// check error
 		if err != nil {
+			if err == ahttp.ErrBodyTooLarge {
+				return nil, newErrorWithData(ErrRequestBodyTooLarge, http.StatusRequestEntityTooLarge, err)
+			}
 			if !result.IsValid() {
 				ctx.Log().Errorf("Parsed parameter value is invalid or value parser not found [param: %s, type: %s]",
 					val.Name, val.Type)
@@ -24,6 +24,7 @@ var (
 	ErrStaticFileNotFound         = errors.New("aah: static file not found")
 	ErrControllerOrActionNotFound = errors.New("aah: controller or action not found")
 	ErrInvalidRequestParameter    = errors.New("aah: invalid request parameter")
+	ErrRequestBodyTooLarge        = errors.New("aah: request body too large")
 	ErrContentTypeNotAccepted     = errors.New("aah: content type not accepted")
 	ErrContentTypeNotOffered      = errors.New("aah: content type not offered")
 	ErrHTTPMethodNotAllowed       = errors.New("aah: http method not allowed")
@@ -0,0 +1,200 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Async buffer full policies of config `log.async.on_full`.
+//
+// Async logging is enabled via config `log.async`, log entries are queued
+// into buffer of given size (default 1024 entries) and written by background
+// goroutine. Policy decides what happens when buffer is full-
+//
+//  - `block` (default) waits for the space in the buffer, all the log entries
+//    are preserved at the cost of request latency on slow receiver
+//
+//  - `drop_new` drops the incoming log entry, logging never waits
+//
+//  - `drop_oldest` drops the oldest queued log entry to make space, logging
+//    never waits and the most recent log entries are preserved
+//
+// Dropped log entries are counted, refer to `Logger.Dropped`. Buffer is
+// drained before `FATAL`/`PANIC` entries and on `Logger.Close`.
+//
+// 	For e.g.:
+// 		log {
+// 		  async {
+// 		    size = 4096
+// 		    on_full = "drop_oldest"
+// 		  }
+// 		}
+const (
+	AsyncOnFullBlock      = "block"
+	AsyncOnFullDropNew    = "drop_new"
+	AsyncOnFullDropOldest = "drop_oldest"
+)
+
+const defaultAsyncSize = 1024
+
+// asyncWriter queues the log entries and writes them into receiver from
+// background goroutine, so that logging doesn't wait for the receiver I/O.
+type asyncWriter struct {
+	mu     sync.RWMutex
+	queue  chan asyncItem
+	onFull string
+	closed bool
+	done   chan struct{}
+	stats  *receiverStats
+}
+
+// asyncItem is queued log entry along with its receiver, since category
+// loggers share the async writer. Item with flushed channel is flush marker.
+type asyncItem struct {
+	e        *Entry
+	receiver Receiver
+	flushed  chan struct{}
+}
+
+// Dropped method returns the number of log entries dropped by async buffer
+// full policy `drop_new` and `drop_oldest`. It's zero when async is not
+// enabled.
+func (l *Logger) Dropped() int64 {
+	if l.async == nil {
+		return 0
+	}
+	return l.async.stats.Dropped()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// initAsync method enables the async logging if config `log.async` exists.
+func (l *Logger) initAsync() error {
+	if !l.cfg.IsExists("log.async") {
+		return nil
+	}
+
+	size := l.cfg.IntDefault("log.async.size", defaultAsyncSize)
+	if size <= 0 {
+		return fmt.Errorf("log: async size '%d' must be greater than zero", size)
+	}
+
+	onFull := strings.ToLower(strings.TrimSpace(l.cfg.StringDefault("log.async.on_full", AsyncOnFullBlock)))
+	switch onFull {
+	case AsyncOnFullBlock, AsyncOnFullDropNew, AsyncOnFullDropOldest:
+	default:
+		return fmt.Errorf("log: unsupported async on_full policy '%s', valid values are %s, %s, %s",
+			onFull, AsyncOnFullBlock, AsyncOnFullDropNew, AsyncOnFullDropOldest)
+	}
+
+	l.async = newAsyncWriter(size, onFull)
+	return nil
+}
+
+func newAsyncWriter(size int, onFull string) *asyncWriter {
+	w := &asyncWriter{
+		queue:  make(chan asyncItem, size),
+		onFull: onFull,
+		done:   make(chan struct{}),
+		stats:  &receiverStats{},
+	}
+	go w.run()
+	return w
+}
+
+// write method queues the copy of given entry as per buffer full policy. It
+// returns false if the writer is closed, then caller writes it directly.
+func (w *asyncWriter) write(receiver Receiver, e *Entry) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return false
+	}
+
+	ce := *e
+	item := asyncItem{e: &ce, receiver: receiver}
+	switch w.onFull {
+	case AsyncOnFullDropNew:
+		select {
+		case w.queue <- item:
+		default:
+			w.stats.addDropped()
+		}
+	case AsyncOnFullDropOldest:
+		for {
+			select {
+			case w.queue <- item:
+				return true
+			default:
+			}
+			select {
+			case old := <-w.queue:
+				if old.flushed != nil {
+					close(old.flushed)
+				} else {
+					w.stats.addDropped()
+				}
+			default:
+			}
+		}
+	default:
+		w.queue <- item
+	}
+	return true
+}
+
+// flush method waits until the queued log entries are written.
+func (w *asyncWriter) flush() {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	w.queue <- asyncItem{flushed: flushed}
+	w.mu.RUnlock()
+	<-flushed
+}
+
+// close method stops accepting the log entries and waits until the queued
+// log entries are written. It's safe to call multiple times.
+func (w *asyncWriter) close() {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+	<-w.done
+}
+
+func (w *asyncWriter) run() {
+	defer close(w.done)
+	for item := range w.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		w.log(item)
+	}
+}
+
+// log method writes the queued entry into its receiver, receiver panic is
+// recovered and counted, so that the writer keeps draining the buffer.
+func (w *asyncWriter) log(item asyncItem) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.stats.addPanic()
+			fmt.Fprintf(os.Stderr, "log: recovered from panic in async writer: %v\n", r)
+		}
+	}()
+	item.receiver.Log(item.e)
+}
@@ -0,0 +1,196 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogAsyncOnFullPolicies(t *testing.T) {
+	testcases := []struct {
+		policy  string
+		output  string
+		dropped int64
+	}{
+		{policy: "drop_new", output: "INFO 1 \nINFO 2 \nINFO 3 \n", dropped: 2},
+		{policy: "drop_oldest", output: "INFO 1 \nINFO 4 \nINFO 5 \n", dropped: 2},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.policy, func(t *testing.T) {
+			logger, sink := newAsyncTestLogger(t, tc.policy)
+
+			// first entry is being written by slow sink, next two fill the buffer
+			logger.Info("1")
+			<-sink.started
+			for _, msg := range []string{"2", "3", "4", "5"} {
+				logger.Info(msg)
+			}
+			assert.Equal(t, tc.dropped, logger.Dropped())
+
+			close(sink.gate)
+			assert.Nil(t, logger.Close())
+			assert.Equal(t, tc.output, sink.String())
+		})
+	}
+}
+
+func TestLogAsyncOnFullBlock(t *testing.T) {
+	logger, sink := newAsyncTestLogger(t, "block")
+	logger.Info("1")
+	<-sink.started
+	logger.Info("2")
+	logger.Info("3")
+
+	// buffer is full, logging waits for the slow sink
+	done := make(chan struct{})
+	go func() {
+		logger.Info("4")
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("logging should wait when buffer is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(sink.gate)
+	<-done
+	assert.Nil(t, logger.Close())
+	assert.Equal(t, "INFO 1 \nINFO 2 \nINFO 3 \nINFO 4 \n", sink.String())
+	assert.Equal(t, int64(0), logger.Dropped())
+
+	// logging after close doesn't panic
+	logger.Info("5")
+	assert.Equal(t, "INFO 1 \nINFO 2 \nINFO 3 \nINFO 4 \n", sink.String())
+}
+
+func TestLogAsyncFatalDrainsBuffer(t *testing.T) {
+	exit = func(code int) {}
+	defer func() { exit = os.Exit }()
+
+	logger, sink := newAsyncTestLogger(t, "drop_new")
+	close(sink.gate)
+	logger.Info("1")
+	logger.WithField("key", "value").Warn("2")
+	logger.Fatal("3")
+	assert.Equal(t, "INFO 1 \nWARN 2 \nFATAL 3 \n", sink.String())
+	assert.Nil(t, logger.Close())
+}
+
+func TestLogAsyncReceiverPanic(t *testing.T) {
+	logger, _ := newAsyncTestLogger(t, "block")
+	sink := &panicWriter{}
+	logger.SetWriter(sink)
+
+	logger.Info("1")
+	logger.Info("boom")
+	logger.Info("2")
+	logger.Info("boom")
+	logger.Info("3")
+	logger.Info("4")
+
+	// writer goroutine is alive, flush before fatal doesn't hang
+	exit = func(code int) {}
+	defer func() { exit = os.Exit }()
+	logger.Fatal("5")
+
+	assert.Nil(t, logger.Close())
+	assert.Equal(t, "INFO 1 \nINFO 2 \nINFO 3 \nINFO 4 \nFATAL 5 \n", sink.String())
+	assert.Equal(t, int64(2), logger.Stats().Panics)
+}
+
+func TestLogAsyncConfigError(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    async {
+      on_full = "discard"
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: unsupported async on_full policy 'discard', valid values are block, drop_new, drop_oldest", err.Error())
+
+	cfg, _ = config.ParseString(`log {
+    async {
+      size = 0
+    }
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: async size '0' must be greater than zero", err.Error())
+
+	cfg, _ = config.ParseString(`log { }`)
+	logger, err = New(cfg)
+	assert.Nil(t, err)
+	assert.Nil(t, logger.async)
+	assert.Equal(t, int64(0), logger.Dropped())
+}
+
+func newAsyncTestLogger(t *testing.T, policy string) (*Logger, *slowWriter) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level %message"
+    async {
+      size = 2
+      on_full = "` + policy + `"
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	sink := &slowWriter{started: make(chan struct{}), gate: make(chan struct{})}
+	logger.SetWriter(sink)
+	return logger, sink
+}
+
+// slowWriter blocks the writes until gate is closed, started is closed on
+// first write.
+type slowWriter struct {
+	mu      sync.Mutex
+	once    sync.Once
+	started chan struct{}
+	gate    chan struct{}
+	buf     bytes.Buffer
+}
+
+func (w *slowWriter) Write(p []byte) (int, error) {
+	w.once.Do(func() { close(w.started) })
+	<-w.gate
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *slowWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+// panicWriter panics on the message containing `boom`.
+type panicWriter struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (w *panicWriter) Write(p []byte) (int, error) {
+	if bytes.Contains(p, []byte("boom")) {
+		panic("writer exploded")
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *panicWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
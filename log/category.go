@@ -0,0 +1,162 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"fmt"
+
+	"aahframe.work/config"
+)
+
+// DefaultCategory is the category name of logger's own receiver, unknown
+// categories fall back to it on `Logger.WithCategory`.
+const DefaultCategory = "default"
+
+const (
+	categoryReceiver  = "CATEGORY"
+	categoryKeyPrefix = "log.categories"
+)
+
+// inheritable category config keys from `log { ... }`
+var categoryInheritKeys = []string{"format", "line_ending"}
+
+// WithCategory method returns the logger which writes the log entries into
+// given category receiver. Category logger shares the level, context and
+// hooks with the logger. Unknown categories fall back to the default receiver.
+//
+// Categories are configured with receiver `category`, each category is a file
+// receiver with independent rotation; `log.file` is the default category.
+//
+// 	For e.g.:
+// 		log {
+// 		  receiver = "category"
+// 		  file = "app.log"
+// 		  categories {
+// 		    access {
+// 		      file = "access.log"
+// 		    }
+// 		    error {
+// 		      file = "error.log"
+// 		      rotate {
+// 		        policy = "size"
+// 		        size = "100mb"
+// 		      }
+// 		    }
+// 		  }
+// 		}
+//
+// 		logger.WithCategory("access").Info("GET /users 200")
+func (l *Logger) WithCategory(name string) *Logger {
+	l.m.RLock()
+	receiver, found := l.categories[name]
+	if !found {
+		receiver, found = l.categories[DefaultCategory]
+	}
+	l.m.RUnlock()
+
+	nl := *l
+	if found {
+		nl.receiver = receiver
+	}
+	return &nl
+}
+
+// Categories method returns the configured category names.
+func (l *Logger) Categories() []string {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	names := make([]string, 0, len(l.categories))
+	for name := range l.categories {
+		names = append(names, name)
+	}
+	return names
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (l *Logger) initCategories(pattern string) (err error) {
+	categories := map[string]Receiver{DefaultCategory: l.receiver}
+	defer func() {
+		// logger is not created on error, so close the default receiver too
+		if err != nil {
+			closeReceivers(categories, nil)
+		}
+	}()
+
+	for _, name := range l.cfg.KeysByPath(categoryKeyPrefix) {
+		if name == DefaultCategory {
+			return fmt.Errorf("log: category name '%s' is reserved", name)
+		}
+
+		cfg, err := categoryConfig(l.cfg, name)
+		if err != nil {
+			return err
+		}
+
+		receiver := &FileReceiver{}
+		if err = receiver.Init(cfg); err != nil {
+			return err
+		}
+		categories[name] = receiver
+		if err = receiver.SetPattern(cfg.StringDefault("log.pattern", pattern)); err != nil {
+			return err
+		}
+	}
+
+	l.m.Lock()
+	l.categories = categories
+	l.m.Unlock()
+	return nil
+}
+
+// categoryConfig method creates the config for category file receiver, config
+// `log.categories.<name>` becomes `log { ... }`.
+func categoryConfig(appCfg *config.Config, name string) (*config.Config, error) {
+	key := categoryKeyPrefix + "." + name
+	sub, found := appCfg.GetSubConfig(key)
+	if !found {
+		return nil, fmt.Errorf("log: category '%s' is not a section", name)
+	}
+	if _, found = sub.String("file"); !found {
+		return nil, fmt.Errorf("log: category '%s' file is not configured", name)
+	}
+
+	cfg := config.NewEmpty()
+	if err := cfg.Merge2Section("log", sub); err != nil {
+		return nil, err
+	}
+	for _, k := range categoryInheritKeys {
+		if v, found := appCfg.String("log." + k); found && !cfg.IsExists("log."+k) {
+			cfg.SetString("log."+k, v)
+		}
+	}
+	return cfg, nil
+}
+
+// closeReceivers method closes the given receivers except skip, receiver
+// shared by multiple categories is closed once.
+func closeReceivers(receivers map[string]Receiver, skip Receiver) {
+	closed := make([]Receiver, 0, len(receivers))
+	for _, r := range receivers {
+		if r == skip || containsReceiver(closed, r) {
+			continue
+		}
+		closed = append(closed, r)
+		if c, ok := r.(Closer); ok {
+			_ = c.Close()
+		}
+	}
+}
+
+func containsReceiver(receivers []Receiver, r Receiver) bool {
+	for _, rr := range receivers {
+		if rr == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogCategoryRouting(t *testing.T) {
+	cleaupFiles("category-*.log")
+	defer cleaupFiles("category-*.log")
+
+	cfg, _ := config.ParseString(`
+  log {
+    receiver = "category"
+    level = "debug"
+    pattern = "%level:-5 %message"
+    file = "category-app.log"
+    categories {
+      access {
+        file = "category-access.log"
+        pattern = "%message"
+      }
+      error {
+        file = "category-error.log"
+        format = "json"
+        rotate {
+          policy = "lines"
+          lines = 2
+        }
+      }
+    }
+  }
+  `)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	names := logger.Categories()
+	sort.Strings(names)
+	assert.Equal(t, []string{"access", "default", "error"}, names)
+
+	logger.Info("application started")
+	logger.WithCategory("access").Info("GET /users 200")
+	logger.WithCategory("unknown").Warn("falls back to default")
+	errLogger := logger.WithCategory("error")
+	errLogger.WithField("code", 500).Error("internal error")
+	assert.True(t, errLogger.IsLevelDebug())
+	assert.Nil(t, logger.Close())
+
+	assert.Equal(t, "INFO  application started \nWARN  falls back to default \n", readFile("category-app.log"))
+	assert.Equal(t, "GET /users 200 \n", readFile("category-access.log"))
+	errContent := readFile("category-error.log")
+	assert.True(t, strings.Contains(errContent, `"message":"internal error"`))
+	assert.True(t, strings.Contains(errContent, `"code":500`))
+
+	// category receivers are closed along with logger
+	assert.True(t, logger.Closed())
+	assert.True(t, logger.WithCategory("access").Closed())
+}
+
+func TestLogCategoryConfigError(t *testing.T) {
+	testcases := []struct {
+		label  string
+		cfg    string
+		errMsg string
+	}{
+		{"file missing", `log { receiver = "category", file = "category-app.log", categories { access { pattern = "%message" } } }`,
+			"log: category 'access' file is not configured"},
+		{"not a section", `log { receiver = "category", file = "category-app.log", categories { access = "category-access.log" } }`,
+			"log: category 'access' is not a section"},
+		{"reserved name", `log { receiver = "category", file = "category-app.log", categories { default { file = "category-default.log" } } }`,
+			"log: category name 'default' is reserved"},
+		{"invalid format", `log { receiver = "category", file = "category-app.log", categories { access { file = "category-access.log", format = "xml" } } }`,
+			"log: unsupported format 'xml'"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			defer cleaupFiles("category-*.log")
+			cfg, err := config.ParseString(tc.cfg)
+			assert.Nil(t, err)
+			logger, err := New(cfg)
+			assert.Nil(t, logger)
+			assert.Equal(t, tc.errMsg, err.Error())
+		})
+	}
+
+	// default receiver is closed on error
+	defer cleaupFiles("category-*.log")
+	cfg, _ := config.ParseString(`log { receiver = "file", file = "category-app.log", categories { access { file = "category-access.log", format = "xml" } } }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	err = logger.initCategories(DefaultPattern)
+	assert.Equal(t, "log: unsupported format 'xml'", err.Error())
+	assert.True(t, logger.Closed())
+	assert.Nil(t, logger.categories)
+
+	// shared receiver is closed once
+	r := &closeCounter{}
+	closeReceivers(map[string]Receiver{DefaultCategory: r, "access": r, "audit": r}, nil)
+	assert.Equal(t, 1, r.count)
+
+	// without categories, logger is a plain logger
+	cfg, _ = config.ParseString(`log { receiver = "console" }`)
+	logger, err = New(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, logger.receiver, logger.WithCategory("access").receiver)
+	assert.Equal(t, 0, len(logger.Categories()))
+}
+
+type closeCounter struct {
+	discardReceiver
+	count int
+}
+
+func (c *closeCounter) Close() error {
+	c.count++
+	return nil
+}
+
+func (c *closeCounter) Closed() bool {
+	return c.count > 0
+}
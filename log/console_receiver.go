@@ -5,12 +5,15 @@
 package log
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"runtime"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"aahframe.work/config"
 	"aahframe.work/essentials"
@@ -29,17 +32,59 @@ var (
 		LevelTrace: []byte("\033[0;35m"), // magenta (purple)
 	}
 
-	_ Receiver = (*ConsoleReceiver)(nil)
+	// named colors of config `log.colors`, standard ANSI foreground colors
+	namedColors = map[string]string{
+		"black":   "0;30",
+		"red":     "0;31",
+		"green":   "0;32",
+		"yellow":  "0;33",
+		"blue":    "0;34",
+		"magenta": "0;35",
+		"purple":  "0;35",
+		"cyan":    "0;36",
+		"white":   "0;37",
+		"gray":    "1;30",
+		"grey":    "1;30",
+	}
+
+	_ Receiver        = (*ConsoleReceiver)(nil)
+	_ HealthChecker   = (*ConsoleReceiver)(nil)
+	_ Closer          = (*ConsoleReceiver)(nil)
+	_ StatsReporter   = (*ConsoleReceiver)(nil)
+	_ FormatterSetter = (*ConsoleReceiver)(nil)
+	_ LevelEnabler    = (*ConsoleReceiver)(nil)
 )
 
-// ConsoleReceiver writes the log entry into os.Stderr.
-// For non-windows it  writes with color.
+// ConsoleReceiver writes the log entry into os.Stderr (default) or os.Stdout
+// based on config `log.output`. For non-windows terminal it writes with color.
+//
+// Level colors can be customized via config `log.colors`, value is either
+// named color (black, red, green, yellow, blue, magenta, cyan, white, gray)
+// or ANSI SGR code with or without escape sequence, e.g. `1;32`. Invalid
+// value is reported on logger creation.
+//
+// 	For e.g.:
+// 		log {
+// 		  colors {
+// 		    info = "green"
+// 		    debug = "1;34"
+// 		  }
+// 		}
 type ConsoleReceiver struct {
 	out          io.Writer
 	formatter    string
+	format       Formatter
+	lineEnding   []byte
+	maxMsgLen    int
+	minLevel     LogLevel
+	stats        *receiverStats
 	flags        []ess.FmtFlagPart
-	isCallerInfo bool
+	isCallerInfo uint32
 	isColor      bool
+	colorCfg     *bool
+	levelColors  [][]byte
+	isClosed     bool
+	lastErr      error
 	mu           sync.Mutex
 }
 
@@ -49,18 +94,55 @@ type ConsoleReceiver struct {
 
 // Init method initializes the console logger.
 func (c *ConsoleReceiver) Init(cfg *config.Config) error {
-	c.out = os.Stderr
-	c.isColor = runtime.GOOS != "windows"
+	switch output := cfg.StringDefault("log.output", "stderr"); output {
+	case "stderr":
+		c.out = os.Stderr
+	case "stdout":
+		c.out = os.Stdout
+	default:
+		return fmt.Errorf("log: unsupported console output '%s'", output)
+	}
 
 	if v, found := cfg.Bool("log.color"); found {
-		c.isColor = v
+		c.colorCfg = &v
 	}
+	c.isColor = c.detectColor(c.out)
+
+	levelColors, err := parseLevelColors(cfg)
+	if err != nil {
+		return err
+	}
+	c.levelColors = levelColors
 
 	c.formatter = cfg.StringDefault("log.format", "text")
-	if !(c.formatter == textFmt || c.formatter == jsonFmt) {
+	switch c.formatter {
+	case textFmt:
+		format, err := textFormatterByConfig(cfg)
+		if err != nil {
+			return err
+		}
+		c.format = format
+	case jsonFmt, jsonlFmt:
+		c.formatter, c.format = jsonFmt, jsonFormatterByConfig(cfg)
+	default:
 		return fmt.Errorf("log: unsupported format '%s'", c.formatter)
 	}
 
+	lineEnding, err := parseLineEnding(cfg)
+	if err != nil {
+		return err
+	}
+	c.lineEnding = lineEnding
+
+	if c.maxMsgLen, err = parseMaxMessageLen(cfg); err != nil {
+		return err
+	}
+
+	if c.minLevel, err = parseMinLevel(cfg); err != nil {
+		return err
+	}
+	c.stats = &receiverStats{}
+
 	c.mu = sync.Mutex{}
 
 	return nil
@@ -73,8 +155,8 @@ func (c *ConsoleReceiver) SetPattern(pattern string) error {
 		return err
 	}
 	c.flags = flags
-	if c.formatter == textFmt {
-		c.isCallerInfo = isCallerInfo(c.flags)
+	if c.formatter != jsonFmt {
+		setCallerInfo(&c.isCallerInfo, c.flags)
 	}
 	return nil
 }
@@ -84,36 +166,163 @@ func (c *ConsoleReceiver) SetWriter(w io.Writer) {
 	c.out = w
 }
 
+// SetOutput method swaps the console receiver output writer with given writer
+// safely while logging is in progress. Color mode is re-evaluated for the
+// given writer unless it's configured via `log.color`.
+func (c *ConsoleReceiver) SetOutput(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.out = w
+	c.isColor = c.detectColor(w)
+}
+
+// SetFormatter method sets the given log entry formatter, it's safe to call
+// while logging is in progress.
+func (c *ConsoleReceiver) SetFormatter(fn Formatter) {
+	if fn == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.format = fn
+	c.formatter = customFmt
+	setCallerInfo(&c.isCallerInfo, c.flags)
+}
+
 // IsCallerInfo method returns true if log receiver is configured with caller info
 // otherwise false.
 func (c *ConsoleReceiver) IsCallerInfo() bool {
-	return c.isCallerInfo
+	return atomic.LoadUint32(&c.isCallerInfo) == 1
 }
 
-// Log method writes the log entry into os.Stderr.
+// Log method writes the log entry into console output.
 func (c *ConsoleReceiver) Log(entry *Entry) {
+	if entry.Level > c.minLevel {
+		return
+	}
+
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.isColor {
-		_, _ = c.out.Write(levelToColor[entry.Level])
+	if c.isClosed {
+		c.lastErr = ErrWriterIsClosed
+		return
+	}
+
+	if e, truncated := truncateEntry(entry, c.maxMsgLen); truncated {
+		entry = e
+		c.stats.addTruncated()
 	}
 
-	var msg []byte
-	if c.formatter == textFmt {
-		msg = textFormatter(c.flags, entry)
-	} else {
-		msg, _ = json.Marshal(entry)
-		msg = append(msg, '\n')
+	if c.isColor {
+		_, _ = c.out.Write(c.levelColors[entry.Level])
 	}
-	_, _ = c.out.Write(msg)
+
+	msg := terminate(c.format(c.flags, entry), c.lineEnding)
+	var size int
+	size, c.lastErr = c.out.Write(msg)
+	c.stats.add(1, int64(size))
 
 	if c.isColor {
 		_, _ = c.out.Write(resetColor)
 	}
 }
 
+// Enabled method returns true if the given level is within the console
+// receiver minimum level `log.min_level` otherwise false.
+func (c *ConsoleReceiver) Enabled(lvl LogLevel) bool {
+	return lvl <= c.minLevel
+}
+
+// Stats method returns the point-in-time copy of console receiver counters.
+func (c *ConsoleReceiver) Stats() ReceiverStats {
+	return c.stats.snapshot()
+}
+
 // Writer method returns the current log writer.
 func (c *ConsoleReceiver) Writer() io.Writer {
 	return c.out
 }
+
+// Healthy method returns false and error if the last log write failed
+// otherwise true.
+func (c *ConsoleReceiver) Healthy() (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lastErr == nil, c.lastErr
+}
+
+// Close method marks the console receiver as closed, standard output
+// streams are not closed. It's idempotent, log entries written after close
+// are dropped and reported as `ErrWriterIsClosed` via `Healthy`.
+func (c *ConsoleReceiver) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.isClosed = true
+	return nil
+}
+
+// Closed method returns true if the console receiver is closed otherwise false.
+func (c *ConsoleReceiver) Closed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.isClosed
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// ConsoleReceiver Unexported methods
+//___________________________________
+
+// detectColor method returns color mode from config `log.color` if present
+// otherwise true for non-windows terminal writer.
+func (c *ConsoleReceiver) detectColor(w io.Writer) bool {
+	if c.colorCfg != nil {
+		return *c.colorCfg
+	}
+	return runtime.GOOS != "windows" && isTerminal(w)
+}
+
+// parseLevelColors method returns the level colors with config `log.colors`
+// applied on the defaults.
+func parseLevelColors(cfg *config.Config) ([][]byte, error) {
+	colors := make([][]byte, len(levelToColor))
+	copy(colors, levelToColor)
+	for _, name := range cfg.KeysByPath("log.colors") {
+		lvl := levelByName(name)
+		if lvl == LevelUnknown {
+			return nil, fmt.Errorf("log: colors: unknown log level '%s'", name)
+		}
+		value := cfg.StringDefault("log.colors."+name, "")
+		code, err := colorCode(value)
+		if err != nil {
+			return nil, fmt.Errorf("log: colors: invalid color '%s' for level '%s': %s", value, name, err)
+		}
+		colors[lvl] = code
+	}
+	return colors, nil
+}
+
+// colorCode method returns the ANSI escape sequence for given named color or
+// SGR code, e.g. `red`, `1;31`, `\033[1;31m`.
+func colorCode(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if code, found := namedColors[strings.ToLower(value)]; found {
+		value = code
+	} else if strings.HasPrefix(value, "\033[") {
+		if !strings.HasSuffix(value, "m") {
+			return nil, errors.New("escape sequence must end with 'm'")
+		}
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "\033["), "m")
+	}
+
+	if len(value) == 0 {
+		return nil, errors.New("color is empty")
+	}
+	for _, p := range strings.Split(value, ";") {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > 255 {
+			return nil, errors.New("not a named color or SGR code")
+		}
+	}
+	return []byte("\033[" + value + "m"), nil
+}
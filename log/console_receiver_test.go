@@ -5,11 +5,20 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"aahframe.work/config"
+	"aahframe.work/essentials"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -70,7 +79,7 @@ func TestConsoleLoggerUnknownFormatFlag(t *testing.T) {
 	cfg, _ := config.ParseString(configStr)
 	logger, err := New(cfg)
 	assert.Nil(t, logger)
-	assert.Equal(t, "fmtflag: unknown flag 'myfile'", err.Error())
+	assert.Equal(t, "log: unknown flag '%myfile'", err.Error())
 }
 
 func TestConsoleLoggerUnknownLevel(t *testing.T) {
@@ -84,7 +93,7 @@ func TestConsoleLoggerUnknownLevel(t *testing.T) {
 	cfg, _ := config.ParseString(configStr)
 	logger, err := New(cfg)
 	assert.Nil(t, logger)
-	assert.Equal(t, "log: unknown log level 'MYLEVEL'", err.Error())
+	assert.Equal(t, "log: unknown log level 'MYLEVEL', valid values are FATAL, PANIC, ERROR, WARN, INFO, DEBUG, TRACE (or 0-6)", err.Error())
 }
 
 func TestConsoleLoggerDefaults(t *testing.T) {
@@ -105,6 +114,319 @@ func TestConsoleLoggerDefaults(t *testing.T) {
 	assert.Equal(t, "log: receiver is nil", err.Error())
 }
 
+func TestConsoleLoggerHealthy(t *testing.T) {
+	cfg, _ := config.ParseString("log { }")
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	logger.SetWriter(ioutil.Discard)
+	logger.Info("healthy log entry")
+	healthy, err := logger.Healthy()
+	assert.True(t, healthy)
+	assert.Nil(t, err)
+
+	logger.SetWriter(&failWriter{})
+	logger.Info("unhealthy log entry")
+	healthy, err = logger.Healthy()
+	assert.False(t, healthy)
+	assert.Equal(t, "disk full", err.Error())
+
+	// recovers on next successful write
+	logger.SetWriter(ioutil.Discard)
+	logger.Info("healthy log entry")
+	healthy, _ = logger.Healthy()
+	assert.True(t, healthy)
+
+	logger.receiver = nil
+	healthy, err = logger.Healthy()
+	assert.False(t, healthy)
+	assert.Equal(t, ErrLogReceiverIsNil, err)
+}
+
+func TestConsoleLoggerOutput(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    output = "stdout"
+    pattern = "%level:-5 %message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, os.Stdout, logger.receiver.Writer())
+
+	buf := &bytes.Buffer{}
+	receiver := logger.receiver.(*ConsoleReceiver)
+	receiver.SetOutput(buf)
+	assert.False(t, receiver.isColor)
+	logger.Info("captured into buffer")
+	assert.Equal(t, "INFO  captured into buffer \n", buf.String())
+
+	// color explicitly configured
+	cfg, _ = config.ParseString(`log {
+    color = true
+    pattern = "%level:-5 %message"
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, os.Stderr, logger.receiver.Writer())
+	logger.receiver.(*ConsoleReceiver).SetOutput(buf)
+	assert.True(t, logger.receiver.(*ConsoleReceiver).isColor)
+
+	cfg, _ = config.ParseString(`log {
+    output = "syslog"
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: unsupported console output 'syslog'", err.Error())
+}
+
+func TestConsoleLoggerLineEnding(t *testing.T) {
+	testcases := []struct {
+		lineEnding string
+		format     string
+		expected   string
+	}{
+		{"", "text", "INFO  line ending \n"},
+		{"lf", "text", "INFO  line ending \n"},
+		{"crlf", "text", "INFO  line ending \r\n"},
+		{"CRLF", "json", `"message":"line ending"}` + "\r\n"},
+		{"none", "text", "INFO  line ending "},
+		{"none", "json", `"message":"line ending"}`},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.lineEnding+" "+tc.format, func(t *testing.T) {
+			cfg, _ := config.ParseString(fmt.Sprintf(`log {
+    pattern = "%%level:-5 %%message"
+    format = "%s"
+    line_ending = "%s"
+  }`, tc.format, tc.lineEnding))
+			logger, err := New(cfg)
+			assert.Nil(t, err)
+
+			buf := &bytes.Buffer{}
+			logger.receiver.(*ConsoleReceiver).SetOutput(buf)
+			logger.Info("line ending")
+			assert.True(t, strings.HasSuffix(buf.String(), tc.expected), "got %q", buf.String())
+		})
+	}
+
+	// custom formatter without trailing newline is terminated too
+	cfg, _ := config.ParseString(`log {
+    line_ending = "crlf"
+  }`)
+	logger, _ := New(cfg)
+	buf := &bytes.Buffer{}
+	logger.receiver.(*ConsoleReceiver).SetOutput(buf)
+	_ = logger.SetFormatter(func(_ []ess.FmtFlagPart, e *Entry) []byte { return []byte(e.Message) })
+	logger.Info("custom")
+	assert.Equal(t, "custom\r\n", buf.String())
+
+	cfg, _ = config.ParseString(`log {
+    line_ending = "cr"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: unsupported line ending 'cr', valid values are lf, crlf, none", err.Error())
+}
+
+func TestConsoleLoggerHostnamePID(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%hostname %pid %level:-5 %message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	logger.receiver.(*ConsoleReceiver).SetOutput(buf)
+	logger.Info("hostname and pid")
+	assert.Equal(t, fmt.Sprintf("%s %d INFO  hostname and pid \n", hostname, os.Getpid()), buf.String())
+
+	cfg, _ = config.ParseString(`log {
+    format = "json"
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, err)
+
+	buf.Reset()
+	logger.receiver.(*ConsoleReceiver).SetOutput(buf)
+	logger.Info("hostname and pid")
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, hostname, m["hostname"])
+	assert.Equal(t, float64(os.Getpid()), m["pid"])
+}
+
+func TestConsoleLoggerJSONFieldOrder(t *testing.T) {
+	SetClock(func() time.Time { return time.Date(2018, 6, 15, 10, 30, 45, 0, time.UTC) })
+	defer SetClock(nil)
+
+	cfg, _ := config.ParseString(`log {
+    format = "json"
+    json {
+      field_order = ["timestamp", "level", "message", "unknown"]
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	logger.receiver.(*ConsoleReceiver).SetOutput(buf)
+	h, _ := json.Marshal(hostname)
+	expected := fmt.Sprintf(`{"timestamp":"2018-06-15T10:30:45Z","level":"INFO","message":"ordered",`+
+		`"fields":{"a":1,"m":true,"z":"last"},"hostname":%s,"pid":%d}`+"\n", h, os.Getpid())
+	for i := 0; i < 5; i++ {
+		buf.Reset()
+		logger.WithFields(Fields{"z": "last", "a": 1, "m": true}).Info("ordered")
+		assert.Equal(t, expected, buf.String())
+	}
+}
+
+func TestConsoleLoggerDuration(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level:-5 %duration %duration:.1 %duration:8.0 %message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	logger.receiver.(*ConsoleReceiver).SetOutput(buf)
+	logger.WithFields(Fields{DurationFieldKey: 12345678 * time.Nanosecond}).Info("request completed")
+	assert.Equal(t, "INFO  12.346ms 12.3ms       12ms request completed \n", buf.String())
+
+	// no duration field or unsupported value type
+	buf.Reset()
+	logger.Info("no duration")
+	logger.WithFields(Fields{DurationFieldKey: "12ms"}).Info("string duration")
+	assert.Equal(t, "INFO  no duration \nINFO  string duration \n", buf.String())
+
+	// start time
+	buf.Reset()
+	logger.WithFields(Fields{DurationFieldKey: time.Now().Add(-2 * time.Second)}).Info("since start")
+	assert.True(t, strings.HasPrefix(buf.String(), "INFO  20"), buf.String())
+
+	cfg, _ = config.ParseString(`log {
+    format = "json"
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, err)
+
+	buf.Reset()
+	logger.receiver.(*ConsoleReceiver).SetOutput(buf)
+	defer func(p int) { DurationPrecision = p }(DurationPrecision)
+	DurationPrecision = 1
+	logger.WithFields(Fields{DurationFieldKey: 12345678 * time.Nanosecond}).Info("request completed")
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, 12.3, m["duration_ms"])
+
+	buf.Reset()
+	logger.Info("no duration")
+	m = nil
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &m))
+	_, found := m["duration_ms"]
+	assert.False(t, found)
+}
+
+func TestConsoleLoggerClose(t *testing.T) {
+	cfg, _ := config.ParseString("log { }")
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+	assert.Nil(t, logger.Close())
+	assert.Nil(t, logger.Close())
+	assert.True(t, logger.Closed())
+
+	logger.Info("after close")
+	assert.Equal(t, 0, buf.Len())
+	healthy, err := logger.Healthy()
+	assert.False(t, healthy)
+	assert.Equal(t, ErrWriterIsClosed, err)
+}
+
+func TestConsoleLoggerSetFormatter(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level:-5 %message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	logger.receiver.(*ConsoleReceiver).SetOutput(buf)
+	logger.Info("text entry")
+	assert.Equal(t, "INFO  text entry \n", buf.String())
+
+	// swap to JSON
+	buf.Reset()
+	assert.Nil(t, logger.SetFormatter(JSONFormatter))
+	logger.Info("json entry")
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "INFO", m["level"])
+	assert.Equal(t, "json entry", m["message"])
+
+	// custom formatter
+	buf.Reset()
+	assert.Nil(t, logger.SetFormatter(func(flags []ess.FmtFlagPart, e *Entry) []byte {
+		return []byte(fmt.Sprintf("[%s] %s\n", e.Level, e.Message))
+	}))
+	logger.SetFormatter(nil) // ignored
+	logger.Warn("custom entry")
+	assert.Equal(t, "[WARN] custom entry\n", buf.String())
+
+	// back to text
+	buf.Reset()
+	assert.Nil(t, logger.SetFormatter(TextFormatter))
+	logger.Info("text entry")
+	assert.Equal(t, "INFO  text entry \n", buf.String())
+
+	logger.receiver = &noFormatterReceiver{}
+	assert.Equal(t, ErrFormatterNotSupported, logger.SetFormatter(JSONFormatter))
+	logger.receiver = nil
+	assert.Equal(t, ErrLogReceiverIsNil, logger.SetFormatter(JSONFormatter))
+}
+
+func TestConsoleLoggerSetFormatterConcurrent(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level:-5 %func %message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	logger.receiver.(*ConsoleReceiver).SetOutput(ioutil.Discard)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Info("concurrent entry")
+			}
+		}()
+	}
+	for i := 0; i < 50; i++ {
+		assert.Nil(t, logger.SetFormatter(JSONFormatter))
+		assert.Nil(t, logger.SetFormatter(TextFormatter))
+	}
+	wg.Wait()
+	assert.True(t, logger.receiver.IsCallerInfo())
+}
+
+type noFormatterReceiver struct{}
+
+func (noFormatterReceiver) Init(cfg *config.Config) error   { return nil }
+func (noFormatterReceiver) SetPattern(pattern string) error { return nil }
+func (noFormatterReceiver) SetWriter(w io.Writer)           {}
+func (noFormatterReceiver) IsCallerInfo() bool              { return false }
+func (noFormatterReceiver) Writer() io.Writer               { return ioutil.Discard }
+func (noFormatterReceiver) Log(e *Entry)                    {}
+
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
 func testConsoleLogger(t *testing.T, cfgStr string) {
 	cfg, _ := config.ParseString(cfgStr)
 	logger, err := New(cfg)
@@ -135,3 +457,106 @@ func testConsoleLogger(t *testing.T, cfgStr string) {
 
 	assert.NotNil(t, logger.ToGoLogger())
 }
+
+func TestConsoleLoggerMaxMessageLen(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level %message"
+    max_message_len = 10
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+	receiver := logger.receiver.(*ConsoleReceiver)
+
+	// within limit
+	logger.Info("0123456789")
+	assert.Equal(t, "INFO 0123456789 \n", buf.String())
+	assert.Equal(t, int64(0), receiver.stats.Truncated())
+
+	// over limit
+	buf.Reset()
+	logger.Info(strings.Repeat("x", 1<<20))
+	assert.Equal(t, "INFO xxxxxxxxxx...[truncated] \n", buf.String())
+	assert.Equal(t, int64(1), receiver.stats.Truncated())
+
+	// multibyte rune at boundary, 'é' is 2 bytes at index 9
+	buf.Reset()
+	logger.Info("012345678é-rest")
+	assert.Equal(t, "INFO 012345678...[truncated] \n", buf.String())
+	assert.Equal(t, int64(2), receiver.stats.Truncated())
+
+	cfg, _ = config.ParseString(`log {
+    max_message_len = -1
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: max message length '-1' must not be negative", err.Error())
+}
+
+func TestConsoleLoggerLevelColors(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    color = true
+    level = "debug"
+    pattern = "%level %message"
+    colors {
+      info = "Green"
+      warning = "1;33"
+      debug = "0;38;5;208"
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+	assert.True(t, logger.receiver.(*ConsoleReceiver).isColor)
+
+	logger.Info("info message")
+	logger.Warn("warn message")
+	logger.Debug("debug message")
+	logger.Error("error message")
+	assert.Equal(t, "\033[0;32mINFO info message \n\033[0m"+
+		"\033[1;33mWARN warn message \n\033[0m"+
+		"\033[0;38;5;208mDEBUG debug message \n\033[0m"+
+		"\033[0;31mERROR error message \n\033[0m", buf.String())
+
+	// package defaults are untouched
+	assert.Equal(t, []byte("\033[0;37m"), levelToColor[LevelInfo])
+
+	testcases := []struct {
+		label  string
+		colors string
+		errMsg string
+	}{
+		{"unknown color name", `info = "pink"`,
+			"log: colors: invalid color 'pink' for level 'info': not a named color or SGR code"},
+		{"out of range code", `error = "1;256"`,
+			"log: colors: invalid color '1;256' for level 'error': not a named color or SGR code"},
+		{"empty", `warn = ""`,
+			"log: colors: invalid color '' for level 'warn': color is empty"},
+		{"unknown level", `notice = "red"`,
+			"log: colors: unknown log level 'notice'"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			cfg, _ := config.ParseString(fmt.Sprintf(`log {
+    colors {
+      %s
+    }
+  }`, tc.colors))
+			logger, err := New(cfg)
+			assert.Nil(t, logger)
+			assert.Equal(t, tc.errMsg, err.Error())
+		})
+	}
+
+	code, err := colorCode("\033[1;31m")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("\033[1;31m"), code)
+
+	_, err = colorCode("\033[1;31")
+	assert.Equal(t, "escape sequence must end with 'm'", err.Error())
+}
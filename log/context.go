@@ -0,0 +1,67 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"aahframe.work/config"
+)
+
+type ctxKey struct{}
+
+// NewContext method returns a copy of given context which carries the given
+// logger, typically field-enriched request-scoped logger stashed by the
+// middleware. Retrieve it via method `log.FromContext` deep in the call stack
+// without parameter threading.
+//
+// 	For e.g.:
+// 		reqLog := logger.New(log.Fields{"reqid": reqID, "traceid": traceID})
+// 		ctx = log.NewContext(ctx, reqLog)
+//
+// 		// in the service layer
+// 		log.FromContext(ctx).Info("order created")
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, logger)
+}
+
+// FromContext method returns the logger carried by given context, refer to
+// method `log.NewContext`. It never returns nil, no-op logger is returned
+// when context doesn't carry logger; it discards log entries, however `FATAL`
+// and `PANIC` still exit and panic respectively. No-op logger is created per
+// call, so changes made on it don't affect other callers.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if l, ok := ctx.Value(ctxKey{}).(*Logger); ok && l != nil {
+			return l
+		}
+	}
+	return newNoopLogger()
+}
+
+// newNoopLogger method returns the logger which discards log entries, it's
+// returned by `FromContext` when context doesn't carry logger.
+func newNoopLogger() *Logger {
+	return &Logger{
+		m:        &sync.RWMutex{},
+		level:    new(uint32), // LevelFatal
+		receiver: discardReceiver{},
+		ctx:      make(Fields),
+		hooks:    make(map[string]HookFunc),
+	}
+}
+
+// discardReceiver is no-op receiver of the logger created by `newNoopLogger`.
+type discardReceiver struct{}
+
+func (discardReceiver) Init(cfg *config.Config) error   { return nil }
+func (discardReceiver) SetPattern(pattern string) error { return nil }
+func (discardReceiver) SetWriter(w io.Writer)           {}
+func (discardReceiver) IsCallerInfo() bool              { return false }
+func (discardReceiver) Writer() io.Writer               { return ioutil.Discard }
+func (discardReceiver) Log(e *Entry)                    {}
@@ -0,0 +1,58 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogContext(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level %reqid %message %fields"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	// store and retrieve
+	reqLog := logger.New(Fields{"reqid": "req-1", "traceid": "trace-1"})
+	ctx := NewContext(context.Background(), reqLog)
+	assert.True(t, FromContext(ctx) == reqLog)
+
+	FromContext(ctx).Info("order created")
+	assert.Equal(t, "INFO req-1 order created fields[traceid: trace-1] \n", buf.String())
+
+	// child context carries the logger
+	child, cancel := context.WithCancel(ctx)
+	defer cancel()
+	assert.True(t, FromContext(child) == reqLog)
+
+	// missing logger falls back to no-op logger
+	buf.Reset()
+	noop := FromContext(context.Background())
+	assert.NotNil(t, noop)
+	noop.Info("discarded")
+	noop.WithField("key", "value").Error("discarded")
+	noop.WithName("svc").Warn("discarded")
+	assert.Equal(t, 0, buf.Len())
+	assert.NotNil(t, FromContext(nil))
+	assert.NotNil(t, FromContext(NewContext(context.Background(), nil)))
+
+	// changes on no-op logger don't leak into other callers
+	noop.AddContext(Fields{"key": "value"})
+	assert.Nil(t, noop.SetLevel("trace"))
+	assert.Nil(t, noop.AddHook("noop", func(e Entry) {}))
+	other := FromContext(context.Background())
+	assert.False(t, noop == other)
+	assert.Equal(t, 0, len(other.ctx))
+	assert.Equal(t, 0, len(other.hooks))
+	assert.Equal(t, "FATAL", other.Level())
+}
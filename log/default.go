@@ -17,6 +17,18 @@ var dl *Logger
 // Logger methods
 //_______________________________________
 
+// Log logs message with given level. Arguments handled in the mananer of
+// `fmt.Print`.
+func Log(lvl LogLevel, v ...interface{}) {
+	dl.Log(lvl, v...)
+}
+
+// Logf logs message with given level. Arguments handled in the mananer of
+// `fmt.Printf`.
+func Logf(lvl LogLevel, format string, v ...interface{}) {
+	dl.Logf(lvl, format, v...)
+}
+
 // Error logs message as `ERROR`. Arguments handled in the mananer of `fmt.Print`.
 func Error(v ...interface{}) {
 	dl.Error(v...)
@@ -128,6 +140,12 @@ func AddHook(name string, hook HookFunc) error {
 	return dl.AddHook(name, hook)
 }
 
+// WithName method creates a child logger of default logger with given
+// component name.
+func WithName(component string) *Logger {
+	return dl.WithName(component)
+}
+
 // WithFields method to add multiple key-value pairs into log.
 func WithFields(fields Fields) Loggerer {
 	return dl.WithFields(fields)
@@ -148,6 +166,11 @@ func SetWriter(w io.Writer) {
 	dl.SetWriter(w)
 }
 
+// Healthy method reports the health of default logger receiver.
+func Healthy() (bool, error) {
+	return dl.Healthy()
+}
+
 // ToGoLogger method wraps the current log writer into Go Logger instance.
 func ToGoLogger() *slog.Logger {
 	return dl.ToGoLogger()
@@ -177,6 +200,12 @@ func Level() string {
 	return dl.Level()
 }
 
+// Enabled method returns true if the log entry of given level would be
+// written by default logger otherwise false. Refer to `Logger.Enabled`.
+func Enabled(lvl LogLevel) bool {
+	return dl.Enabled(lvl)
+}
+
 // IsLevelInfo method returns true if log level is INFO otherwise false.
 func IsLevelInfo() bool {
 	return dl.IsLevelInfo()
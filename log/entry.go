@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	slog "log"
+	"math"
 	"strings"
 	"sync"
 	"time"
@@ -18,20 +19,42 @@ var (
 	_         Loggerer = (*Entry)(nil)
 )
 
+// DurationFieldKey is the field key for elapsed time of the log entry, e.g.
+// request processing time. Field value type is `time.Duration` or start time as
+// `time.Time` (elapsed is calculated until entry time). It's written by format
+// flag `%duration` and JSON field `duration_ms` in milliseconds.
+//
+// 	For e.g.:
+// 		log.WithFields(log.Fields{log.DurationFieldKey: time.Since(start)}).Info("request completed")
+const DurationFieldKey = "duration"
+
 // Fields type is used to log fields values in the logger.
 type Fields map[string]interface{}
 
+// Lazy type is deferred log value, it's evaluated only when the log entry
+// actually gets written, for e.g. serializing a large struct. Log arguments
+// and field values of type `Lazy` or `func() interface{}` are evaluated after
+// the level check, so it costs nothing when the level is disabled.
+//
+// 	For e.g.:
+// 		log.Debug("request payload: ", log.Lazy(func() interface{} {
+// 			return dump(payload)
+// 		}))
+type Lazy func() interface{}
+
 // Entry represents a log entry and contains the timestamp when the entry
 // was created, level, etc.
 type Entry struct {
-	Level        level     `json:"-"`
+	Level        LogLevel  `json:"-"`
 	Line         int       `json:"line,omitempty"`
 	AppName      string    `json:"app_name,omitempty"`
 	InstanceName string    `json:"instance_name,omitempty"`
 	RequestID    string    `json:"request_id,omitempty"`
 	Principal    string    `json:"principal,omitempty"`
+	Component    string    `json:"component,omitempty"`
 	Message      string    `json:"message,omitempty"`
 	File         string    `json:"file,omitempty"`
+	Func         string    `json:"func,omitempty"`
 	Fields       Fields    `json:"fields,omitempty"`
 	Time         time.Time `json:"-"`
 	logger       *Logger
@@ -45,13 +68,24 @@ type Entry struct {
 func (e *Entry) MarshalJSON() ([]byte, error) {
 	type alias Entry
 	ne := struct {
-		Level string `json:"level,omitempty"`
-		Time  string `json:"timestamp,omitempty"`
+		Level    string   `json:"level,omitempty"`
+		Time     string   `json:"timestamp,omitempty"`
+		Hostname string   `json:"hostname,omitempty"`
+		PID      int      `json:"pid,omitempty"`
+		Duration *float64 `json:"duration_ms,omitempty"`
 		*alias
 	}{
-		Level: e.Level.String(),
-		Time:  formatTime(e.Time),
-		alias: (*alias)(e),
+		Level:    e.Level.String(),
+		Time:     formatTime(e.Time),
+		Hostname: hostname,
+		PID:      pid,
+		alias:    (*alias)(e),
+	}
+
+	if d, found := e.duration(); found {
+		p := math.Pow10(DurationPrecision)
+		ms := math.Round(durationMillis(d)*p) / p
+		ne.Duration = &ms
 	}
 
 	// delete skip fields
@@ -66,74 +100,71 @@ func (e *Entry) MarshalJSON() ([]byte, error) {
 // Entry logger methods
 //_______________________________________
 
+// Log logs message with given level. Arguments handled in the mananer of
+// `fmt.Print`. Level `LevelFatal` and `LevelPanic` behave as method `Fatal`
+// and `Panic`.
+func (e *Entry) Log(lvl LogLevel, v ...interface{}) {
+	if e.isEnabled(lvl) {
+		e.outputLevel(lvl, fmt.Sprint(evalLazy(v)...))
+	}
+}
+
+// Logf logs message with given level. Arguments handled in the mananer of
+// `fmt.Printf`. Refer to method `Log`.
+func (e *Entry) Logf(lvl LogLevel, format string, v ...interface{}) {
+	if e.isEnabled(lvl) {
+		e.outputLevel(lvl, fmt.Sprintf(format, evalLazy(v)...))
+	}
+}
+
 // Error logs message as `ERROR`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Error(v ...interface{}) {
-	if e.logger.level >= LevelError {
-		e.output(LevelError, fmt.Sprint(v...))
-	}
+	e.Log(LevelError, v...)
 }
 
 // Errorf logs message as `ERROR`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Errorf(format string, v ...interface{}) {
-	if e.logger.level >= LevelError {
-		e.output(LevelError, fmt.Sprintf(format, v...))
-	}
+	e.Logf(LevelError, format, v...)
 }
 
 // Warn logs message as `WARN`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Warn(v ...interface{}) {
-	if e.logger.level >= LevelWarn {
-		e.output(LevelWarn, fmt.Sprint(v...))
-	}
+	e.Log(LevelWarn, v...)
 }
 
 // Warnf logs message as `WARN`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Warnf(format string, v ...interface{}) {
-	if e.logger.level >= LevelWarn {
-		e.output(LevelWarn, fmt.Sprintf(format, v...))
-	}
+	e.Logf(LevelWarn, format, v...)
 }
 
 // Info logs message as `INFO`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Info(v ...interface{}) {
-	if e.logger.level >= LevelInfo {
-		e.output(LevelInfo, fmt.Sprint(v...))
-	}
+	e.Log(LevelInfo, v...)
 }
 
 // Infof logs message as `INFO`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Infof(format string, v ...interface{}) {
-	if e.logger.level >= LevelInfo {
-		e.output(LevelInfo, fmt.Sprintf(format, v...))
-	}
+	e.Logf(LevelInfo, format, v...)
 }
 
 // Debug logs message as `DEBUG`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Debug(v ...interface{}) {
-	if e.logger.level >= LevelDebug {
-		e.output(LevelDebug, fmt.Sprint(v...))
-	}
+	e.Log(LevelDebug, v...)
 }
 
 // Debugf logs message as `DEBUG`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Debugf(format string, v ...interface{}) {
-	if e.logger.level >= LevelDebug {
-		e.output(LevelDebug, fmt.Sprintf(format, v...))
-	}
+	e.Logf(LevelDebug, format, v...)
 }
 
 // Trace logs message as `TRACE`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Trace(v ...interface{}) {
-	if e.logger.level >= LevelTrace {
-		e.output(LevelTrace, fmt.Sprint(v...))
-	}
+	e.Log(LevelTrace, v...)
 }
 
 // Tracef logs message as `TRACE`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Tracef(format string, v ...interface{}) {
-	if e.logger.level >= LevelTrace {
-		e.output(LevelTrace, fmt.Sprintf(format, v...))
-	}
+	e.Logf(LevelTrace, format, v...)
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -143,52 +174,52 @@ func (e *Entry) Tracef(format string, v ...interface{}) {
 
 // Print logs message as `INFO`. Arguments handled in the mananer of `fmt.Print`.
 func (e *Entry) Print(v ...interface{}) {
-	e.output(LevelInfo, fmt.Sprint(v...))
+	e.output(LevelInfo, fmt.Sprint(evalLazy(v)...))
 }
 
 // Printf logs message as `INFO`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Printf(format string, v ...interface{}) {
-	e.output(LevelInfo, fmt.Sprintf(format, v...))
+	e.output(LevelInfo, fmt.Sprintf(format, evalLazy(v)...))
 }
 
 // Println logs message as `INFO`. Arguments handled in the mananer of `fmt.Printf`.
 func (e *Entry) Println(v ...interface{}) {
-	e.output(LevelInfo, fmt.Sprint(v...))
+	e.output(LevelInfo, fmt.Sprint(evalLazy(v)...))
 }
 
 // Fatal logs message as `FATAL` and call to os.Exit(1).
 func (e *Entry) Fatal(v ...interface{}) {
-	e.output(LevelFatal, fmt.Sprint(v...))
+	e.output(LevelFatal, fmt.Sprint(evalLazy(v)...))
 	exit(1)
 }
 
 // Fatalf logs message as `FATAL` and call to os.Exit(1).
 func (e *Entry) Fatalf(format string, v ...interface{}) {
-	e.output(LevelFatal, fmt.Sprintf(format, v...))
+	e.output(LevelFatal, fmt.Sprintf(format, evalLazy(v)...))
 	exit(1)
 }
 
 // Fatalln logs message as `FATAL` and call to os.Exit(1).
 func (e *Entry) Fatalln(v ...interface{}) {
-	e.output(LevelFatal, fmt.Sprint(v...))
+	e.output(LevelFatal, fmt.Sprint(evalLazy(v)...))
 	exit(1)
 }
 
 // Panic logs message as `PANIC` and call to panic().
 func (e *Entry) Panic(v ...interface{}) {
-	e.output(LevelPanic, fmt.Sprint(v...))
+	e.output(LevelPanic, fmt.Sprint(evalLazy(v)...))
 	panic(e)
 }
 
 // Panicf logs message as `PANIC` and call to panic().
 func (e *Entry) Panicf(format string, v ...interface{}) {
-	e.output(LevelPanic, fmt.Sprintf(format, v...))
+	e.output(LevelPanic, fmt.Sprintf(format, evalLazy(v)...))
 	panic(e)
 }
 
 // Panicln logs message as `PANIC` and call to panic().
 func (e *Entry) Panicln(v ...interface{}) {
-	e.output(LevelPanic, fmt.Sprint(v...))
+	e.output(LevelPanic, fmt.Sprint(evalLazy(v)...))
 	panic(e)
 }
 
@@ -258,10 +289,12 @@ func (e *Entry) Reset() {
 	e.AppName = ""
 	e.RequestID = ""
 	e.Principal = ""
+	e.Component = ""
 	e.Level = LevelUnknown
 	e.Time = time.Time{}
 	e.Message = ""
 	e.File = ""
+	e.Func = ""
 	e.Line = 0
 	e.Fields = make(Fields)
 	e.logger = nil
@@ -282,14 +315,35 @@ func (f Fields) str(key string) string {
 // Unexported methods
 //___________________________________
 
-func (e *Entry) output(lvl level, msg string) {
-	e.Time = time.Now()
+func (e *Entry) output(lvl LogLevel, msg string) {
+	e.Time = now()
 	e.Level = lvl
 	e.Message = msg
 	e.processFields()
 	e.logger.output(e)
 }
 
+// isEnabled method reports whether the entry with given level to be written
+// as per logger level, receiver minimum level and sampling.
+func (e *Entry) isEnabled(lvl LogLevel) bool {
+	if !e.logger.Enabled(lvl) {
+		return false
+	}
+	return lvl < LevelDebug || e.isSampled()
+}
+
+// outputLevel method writes the entry and exits or panics for level `FATAL`
+// and `PANIC` respectively.
+func (e *Entry) outputLevel(lvl LogLevel, msg string) {
+	e.output(lvl, msg)
+	switch lvl {
+	case LevelFatal:
+		exit(1)
+	case LevelPanic:
+		panic(e)
+	}
+}
+
 func (e *Entry) addFields(fields Fields) {
 	for k, v := range fields {
 		e.Fields[k] = v
@@ -298,10 +352,69 @@ func (e *Entry) addFields(fields Fields) {
 
 func (e *Entry) processFields() {
 	e.addFields(e.logger.ctx)
+	for k, v := range e.logger.static {
+		if _, found := e.Fields[k]; !found {
+			e.Fields[k] = v
+		}
+	}
+	for k, v := range e.Fields {
+		if lv, ok := lazyValue(v); ok {
+			e.Fields[k] = lv
+		}
+	}
 	e.AppName = e.Fields.str("appname")
 	e.InstanceName = e.Fields.str("insname")
 	e.RequestID = e.Fields.str("reqid")
 	e.Principal = e.Fields.str("principal")
+	e.Component = e.logger.name
+}
+
+// evalLazy method returns the log arguments with lazy values evaluated.
+// Given slice is not modified.
+func evalLazy(v []interface{}) []interface{} {
+	var args []interface{}
+	for i, a := range v {
+		lv, ok := lazyValue(a)
+		if !ok {
+			continue
+		}
+		if args == nil {
+			args = make([]interface{}, len(v))
+			copy(args, v)
+		}
+		args[i] = lv
+	}
+	if args == nil {
+		return v
+	}
+	return args
+}
+
+func lazyValue(v interface{}) (interface{}, bool) {
+	switch fn := v.(type) {
+	case Lazy:
+		if fn == nil {
+			return nil, true
+		}
+		return fn(), true
+	case func() interface{}:
+		if fn == nil {
+			return nil, true
+		}
+		return fn(), true
+	}
+	return nil, false
+}
+
+// duration method returns the elapsed time from field `DurationFieldKey`.
+func (e *Entry) duration() (time.Duration, bool) {
+	switch v := e.Fields[DurationFieldKey].(type) {
+	case time.Duration:
+		return v, true
+	case time.Time:
+		return e.Time.Sub(v), true
+	}
+	return 0, false
 }
 
 func (e *Entry) isSkipField(key string) bool {
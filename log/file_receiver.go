@@ -5,12 +5,14 @@
 package log
 
 import (
-	"encoding/json"
+	"bufio"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"aahframe.work/config"
@@ -23,24 +25,60 @@ var (
 	// backupTimeFormat is used for timestamp with filename on rotation
 	backupTimeFormat = "2006-01-02-15-04-05.000"
 
-	_ Receiver = (*FileReceiver)(nil)
+	_ Receiver        = (*FileReceiver)(nil)
+	_ HealthChecker   = (*FileReceiver)(nil)
+	_ Closer          = (*FileReceiver)(nil)
+	_ FormatterSetter = (*FileReceiver)(nil)
+	_ Rotator         = (*FileReceiver)(nil)
+	_ StatsReporter   = (*FileReceiver)(nil)
+	_ LevelEnabler    = (*FileReceiver)(nil)
 )
 
+// RotationHook func type is invoked with backup file path after each log file
+// rotation. For e.g.: upload the rotated file to cloud object storage and
+// delete it locally. It runs off the logging write path.
+type RotationHook func(rotatedFilePath string) error
+
+// FileError is returned when log file can't be opened, it records the
+// operation, file path and underlying OS error. Use `os.IsNotExist`,
+// `os.IsPermission`, etc. on field `Err` to identify the cause.
+type FileError struct {
+	Op       string
+	Filename string
+	Err      error
+}
+
+func (e *FileError) Error() string {
+	return "log: unable to " + e.Op + " file '" + e.Filename + "': " + e.Err.Error()
+}
+
 // FileReceiver writes the log entry into file.
 type FileReceiver struct {
 	filename     string
 	out          io.Writer
 	formatter    string
+	format       Formatter
+	lineEnding   []byte
+	maxMsgLen    int
+	minLevel     LogLevel
 	flags        []ess.FmtFlagPart
-	isCallerInfo bool
+	isCallerInfo uint32
 	stats        *receiverStats
 	mu           sync.Mutex
 	isClosed     bool
-	rotatePolicy string
+	rotatePolicy []string
 	openDay      int
 	isUTC        bool
 	maxSize      int64
 	maxLines     int64
+	lastErr      error
+	rotationHook RotationHook
+	hookErr      error
+	hookQueue    []string
+	hookSignal   chan struct{}
+	buf          *bufio.Writer
+	bufSize      int
+	stopFlush    chan struct{}
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -48,7 +86,34 @@ type FileReceiver struct {
 //___________________________________
 
 // Init method initializes the file receiver instance.
+//
+// Buffered write is enabled via config `log.buffer.size` (e.g. "64kb"), buffer
+// is flushed every `log.buffer.flush_interval` (default "1s"), on rotation and
+// on `Flush`/`Close`. Note: buffered log entries may be lost on hard kill
+// of the process.
+//
+// Rotation policies `daily`, `lines` and `size` can be combined via config
+// `log.rotate.policy`, file is rotated when any one of them is reached.
+//
+// 	For e.g.: rotate daily, but also if the file exceeds 500MB before midnight
+// 		rotate {
+// 		  policy = "daily,size"
+// 		  size = "500mb"
+// 		}
 func (f *FileReceiver) Init(cfg *config.Config) error {
+	// Buffer
+	var flushInterval time.Duration
+	if bufSize, found := cfg.String("log.buffer.size"); found {
+		size, err := ess.StrToBytes(bufSize)
+		if err != nil {
+			return err
+		}
+		f.bufSize = int(size)
+		if flushInterval, err = time.ParseDuration(cfg.StringDefault("log.buffer.flush_interval", "1s")); err != nil {
+			return fmt.Errorf("log: invalid buffer flush interval: %s", err)
+		}
+	}
+
 	// File
 	f.filename = cfg.StringDefault("log.file", "")
 	if err := f.openFile(); err != nil {
@@ -56,37 +121,76 @@ func (f *FileReceiver) Init(cfg *config.Config) error {
 	}
 
 	f.formatter = cfg.StringDefault("log.format", "text")
-	if !(f.formatter == textFmt || f.formatter == jsonFmt) {
+	switch f.formatter {
+	case textFmt:
+		format, err := textFormatterByConfig(cfg)
+		if err != nil {
+			return err
+		}
+		f.format = format
+	case jsonFmt, jsonlFmt:
+		f.formatter, f.format = jsonFmt, jsonFormatterByConfig(cfg)
+	default:
 		return fmt.Errorf("log: unsupported format '%s'", f.formatter)
 	}
 
-	if policy, found := cfg.String("log.rotate.mode"); found {
-		f.rotatePolicy = policy
-		if ess.IsStrEmpty(f.rotatePolicy) {
-			f.rotatePolicy = defaultRotatePolicy
+	lineEnding, err := parseLineEnding(cfg)
+	if err != nil {
+		return err
+	}
+	f.lineEnding = lineEnding
+
+	if f.maxMsgLen, err = parseMaxMessageLen(cfg); err != nil {
+		return err
+	}
+
+	if f.minLevel, err = parseMinLevel(cfg); err != nil {
+		return err
+	}
+
+	policy, found := cfg.String("log.rotate.mode")
+	if found {
+		if ess.IsStrEmpty(policy) {
+			policy = defaultRotatePolicy
 		}
 
 		// DEPRECATED, to be removed in v1.0
-		Warnf("DEPRECATED: Config 'log.rotate.mode' is deprecated in v0.7, use 'log.rotate.policy = \"%s\"' instead. Deprecated config will not break your functionality, its good to update to latest config.", f.rotatePolicy)
+		Warnf("DEPRECATED: Config 'log.rotate.mode' is deprecated in v0.7, use 'log.rotate.policy = \"%s\"' instead. Deprecated config will not break your functionality, its good to update to latest config.", policy)
 	} else {
-		f.rotatePolicy = cfg.StringDefault("log.rotate.policy", defaultRotatePolicy)
+		policy = cfg.StringDefault("log.rotate.policy", defaultRotatePolicy)
 	}
 
-	switch f.rotatePolicy {
-	case defaultRotatePolicy:
-		f.openDay = f.getDay()
-	case "lines":
-		f.maxLines = int64(cfg.IntDefault("log.rotate.lines", 0))
-	case "size":
-		maxSize, err := ess.StrToBytes(cfg.StringDefault("log.rotate.size", "512mb"))
-		if err != nil {
-			return err
+	// policies can be combined, e.g. "daily,size", rotation happens
+	// when any one of them is reached
+	f.rotatePolicy = f.rotatePolicy[:0]
+	for _, p := range strings.Split(policy, ",") {
+		if p = strings.TrimSpace(p); len(p) > 0 {
+			f.rotatePolicy = append(f.rotatePolicy, p)
+		}
+	}
+
+	for _, p := range f.rotatePolicy {
+		switch p {
+		case defaultRotatePolicy:
+			f.openDay = f.getDay()
+		case "lines":
+			f.maxLines = int64(cfg.IntDefault("log.rotate.lines", 0))
+		case "size":
+			maxSize, err := ess.StrToBytes(cfg.StringDefault("log.rotate.size", "512mb"))
+			if err != nil {
+				return err
+			}
+			f.maxSize = maxSize
 		}
-		f.maxSize = maxSize
 	}
 
 	f.mu = sync.Mutex{}
 
+	if f.bufSize > 0 && flushInterval > 0 {
+		f.stopFlush = make(chan struct{})
+		go f.flushPeriodically(flushInterval, f.stopFlush)
+	}
+
 	return nil
 }
 
@@ -97,8 +201,8 @@ func (f *FileReceiver) SetPattern(pattern string) error {
 		return err
 	}
 	f.flags = flags
-	if f.formatter == textFmt {
-		f.isCallerInfo = isCallerInfo(f.flags)
+	if f.formatter != jsonFmt {
+		setCallerInfo(&f.isCallerInfo, f.flags)
 	}
 	f.isUTC = isFmtFlagExists(f.flags, FmtFlagUTCTime)
 	f.openDay = f.getDay()
@@ -108,41 +212,105 @@ func (f *FileReceiver) SetPattern(pattern string) error {
 // SetWriter method sets the given writer into file receiver.
 func (f *FileReceiver) SetWriter(w io.Writer) {
 	f.out = w
+	if f.bufSize > 0 {
+		if f.buf != nil {
+			_ = f.buf.Flush()
+		}
+		f.buf = bufio.NewWriterSize(w, f.bufSize)
+	}
+}
+
+// SetFormatter method sets the given log entry formatter, it's safe to call
+// while logging is in progress.
+func (f *FileReceiver) SetFormatter(fn Formatter) {
+	if fn == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.format = fn
+	f.formatter = customFmt
+	setCallerInfo(&f.isCallerInfo, f.flags)
 }
 
 // IsCallerInfo method returns true if log receiver is configured with caller info
 // otherwise false.
 func (f *FileReceiver) IsCallerInfo() bool {
-	return f.isCallerInfo
+	return atomic.LoadUint32(&f.isCallerInfo) == 1
 }
 
 // Log method logs the given entry values into file.
 func (f *FileReceiver) Log(entry *Entry) {
+	if entry.Level > f.minLevel {
+		return
+	}
+
 	f.mu.Lock()
 	defer f.mu.Unlock()
 
-	if f.isRotate() {
-		_ = f.rotateFile()
+	if f.isClosed {
+		f.lastErr = ErrWriterIsClosed
+		return
+	}
 
-		// reset rotation values
-		f.openDay = f.getDay()
-		f.stats.lines = 0
-		f.stats.bytes = 0
+	// rotation decision and file swap happens under receiver lock, so exactly
+	// one goroutine rotates the file at the boundary
+	var rotateErr error
+	if f.isRotate() {
+		rotateErr = f.rotate()
 	}
 
-	var msg []byte
-	if f.formatter == textFmt {
-		msg = textFormatter(f.flags, entry)
-	} else {
-		msg, _ = json.Marshal(entry)
-		msg = append(msg, '\n')
+	if e, truncated := truncateEntry(entry, f.maxMsgLen); truncated {
+		entry = e
+		f.stats.addTruncated()
 	}
 
-	size, _ := f.out.Write(msg)
+	msg := terminate(f.format(f.flags, entry), f.lineEnding)
+
+	size, err := f.writer().Write(msg)
+	if err == nil {
+		err = rotateErr
+	}
+	f.lastErr = err
 
 	// calculate receiver stats
-	f.stats.bytes += int64(size)
-	f.stats.lines++
+	f.stats.add(1, int64(size))
+}
+
+// RotateNow method rotates the log file immediately regardless of rotation
+// policy, e.g. on demand during the deployment. Buffered log entries are
+// flushed into the current file before rotation. Rotation counters are
+// reset, so that policy evaluation starts afresh with the new file.
+//
+// 	For e.g.: rotate on signal SIGUSR1
+// 		sigs := make(chan os.Signal, 1)
+// 		signal.Notify(sigs, syscall.SIGUSR1)
+// 		go func() {
+// 			for range sigs {
+// 				_ = logger.RotateNow()
+// 			}
+// 		}()
+func (f *FileReceiver) RotateNow() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.isClosed {
+		return ErrWriterIsClosed
+	}
+	err := f.rotate()
+	f.lastErr = err
+	return err
+}
+
+// Stats method returns the point-in-time copy of file receiver counters,
+// lines and bytes are of the current log file.
+func (f *FileReceiver) Stats() ReceiverStats {
+	return f.stats.snapshot()
+}
+
+// Enabled method returns true if the given level is within the file receiver
+// minimum level `log.min_level` otherwise false.
+func (f *FileReceiver) Enabled(lvl LogLevel) bool {
+	return lvl <= f.minLevel
 }
 
 // Writer method returns the current log writer.
@@ -150,79 +318,281 @@ func (f *FileReceiver) Writer() io.Writer {
 	return f.out
 }
 
+// SetRotationHook method sets the given hook into file receiver, it's invoked
+// after each log file rotation by a background worker, one at a time in the
+// rotation order. Hook error or panic is reported via method `Healthy` until
+// the next successful hook execution.
+func (f *FileReceiver) SetRotationHook(hook RotationHook) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rotationHook = hook
+}
+
+// Healthy method returns false and error if the last log write, file
+// rotation or rotation hook failed otherwise true.
+func (f *FileReceiver) Healthy() (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.lastErr != nil {
+		return false, f.lastErr
+	}
+	return f.hookErr == nil, f.hookErr
+}
+
+// Flush method writes the buffered log entries into file, if buffered write
+// is enabled.
+func (f *FileReceiver) Flush() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.flush()
+}
+
+// Close method flushes the buffered log entries and closes the log file.
+// It's idempotent, log entries written after close are dropped and reported
+// as `ErrWriterIsClosed` via `Healthy`.
+func (f *FileReceiver) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.isClosed {
+		return nil
+	}
+	if f.stopFlush != nil {
+		close(f.stopFlush)
+		f.stopFlush = nil
+	}
+	if f.hookSignal != nil {
+		// worker runs the queued hooks and exits
+		close(f.hookSignal)
+		f.hookSignal = nil
+	}
+	err := f.flush()
+	f.isClosed = true
+	if c, ok := f.out.(io.Closer); ok {
+		if cerr := c.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Closed method returns true if the file receiver is closed otherwise false.
+func (f *FileReceiver) Closed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.isClosed
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // FileReceiver Unexported methods
 //___________________________________
 
+// isRotate method returns true if any one of the rotation policy is reached.
 func (f *FileReceiver) isRotate() bool {
-	switch f.rotatePolicy {
-	case "daily":
-		return f.openDay != f.getDay()
-	case "lines":
-		return f.maxLines != 0 && f.stats.lines >= f.maxLines
-	case "size":
-		return f.maxSize != 0 && f.stats.bytes >= f.maxSize
-	default:
-		return false
+	for _, p := range f.rotatePolicy {
+		switch p {
+		case defaultRotatePolicy:
+			if f.openDay != f.getDay() {
+				return true
+			}
+		case "lines":
+			if f.maxLines != 0 && f.stats.Lines() >= f.maxLines {
+				return true
+			}
+		case "size":
+			if f.maxSize != 0 && f.stats.Bytes() >= f.maxSize {
+				return true
+			}
+		}
 	}
+	return false
+}
+
+// rotate method rotates the log file and resets the rotation values.
+func (f *FileReceiver) rotate() error {
+	err := f.rotateFile()
+	f.openDay = f.getDay()
+	f.stats.set(0, 0)
+	return err
 }
 
 func (f *FileReceiver) rotateFile() error {
 	if _, err := os.Lstat(f.filename); err == nil {
 		f.close()
-		if err = os.Rename(f.filename, f.backupFileName()); err != nil {
+		backupFile := f.backupFileName()
+		if err = os.Rename(f.filename, backupFile); err != nil {
 			return err
 		}
+		if f.rotationHook != nil {
+			f.queueRotationHook(backupFile)
+		}
 	}
 
 	return f.openFile()
 }
 
+// queueRotationHook method queues the rotated file for the rotation hook
+// worker, worker is started on first rotation.
+func (f *FileReceiver) queueRotationHook(backupFile string) {
+	f.hookQueue = append(f.hookQueue, backupFile)
+	if f.hookSignal == nil {
+		f.hookSignal = make(chan struct{}, 1)
+		go f.runRotationHooks(f.hookSignal)
+	}
+	select {
+	case f.hookSignal <- struct{}{}:
+	default:
+	}
+}
+
+// runRotationHooks method runs the queued rotation hooks in order until
+// signal is closed.
+func (f *FileReceiver) runRotationHooks(signal <-chan struct{}) {
+	for range signal {
+		for {
+			f.mu.Lock()
+			if len(f.hookQueue) == 0 {
+				f.mu.Unlock()
+				break
+			}
+			backupFile := f.hookQueue[0]
+			f.hookQueue = f.hookQueue[1:]
+			hook := f.rotationHook
+			f.mu.Unlock()
+
+			if hook != nil {
+				f.runRotationHook(hook, backupFile)
+			}
+		}
+	}
+}
+
+func (f *FileReceiver) runRotationHook(hook RotationHook, backupFile string) {
+	var err error
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("log: rotation hook panic: %v", r)
+		}
+		f.mu.Lock()
+		f.hookErr = err
+		f.mu.Unlock()
+	}()
+	err = hook(backupFile)
+}
+
 func (f *FileReceiver) openFile() error {
 	dir := filepath.Dir(f.filename)
 	_ = ess.MkDirAll(dir, filePermission)
 
+	// file is opened in write mode, so unwritable log file is reported
+	// upfront instead of on first log entry
 	file, err := os.OpenFile(f.filename, os.O_CREATE|os.O_APPEND|os.O_WRONLY, filePermission)
 	if err != nil {
-		return err
+		return fileError("open", f.filename, err)
 	}
 
 	fileStat, err := file.Stat()
 	if err != nil {
-		return err
+		ess.CloseQuietly(file)
+		return fileError("stat", f.filename, err)
 	}
 
 	f.SetWriter(file)
 	f.isClosed = false
-	f.stats = &receiverStats{}
-	f.stats.bytes = fileStat.Size()
-	f.stats.lines = int64(ess.LineCntr(file))
+	if f.stats == nil {
+		f.stats = &receiverStats{}
+	}
+	f.stats.set(int64(ess.LineCntr(file)), fileStat.Size())
 
 	return nil
 }
 
 func (f *FileReceiver) close() {
 	if !f.isClosed {
+		_ = f.flush()
 		ess.CloseQuietly(f.out)
 		f.isClosed = true
 	}
 }
 
+func (f *FileReceiver) writer() io.Writer {
+	if f.buf != nil {
+		return f.buf
+	}
+	return f.out
+}
+
+func (f *FileReceiver) flush() error {
+	if f.buf == nil || f.isClosed {
+		return nil
+	}
+	err := f.buf.Flush()
+	if err != nil {
+		f.lastErr = err
+	}
+	return err
+}
+
+// flushPeriodically method flushes the buffer on every interval until stop
+// is closed. Panic in background flush (e.g. custom writer bug) is reported
+// to os.Stderr and flusher is restarted, so that logging doesn't silently stop.
+func (f *FileReceiver) flushPeriodically(interval time.Duration, stop <-chan struct{}) {
+	for !f.runFlusher(interval, stop) {
+	}
+}
+
+// runFlusher method returns true on stop otherwise false if recovered from panic.
+func (f *FileReceiver) runFlusher(interval time.Duration, stop <-chan struct{}) (stopped bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			f.stats.addPanic()
+			fmt.Fprintf(os.Stderr, "log: recovered from panic in background flush, restarting: %v\n", r)
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			_ = f.Flush()
+		case <-stop:
+			return true
+		}
+	}
+}
+
+// fileError method returns `FileError` for given operation and file path,
+// underlying OS error is unwrapped from `os.PathError`.
+func fileError(op, filename string, err error) error {
+	if pathErr, ok := err.(*os.PathError); ok {
+		err = pathErr.Err
+	}
+	return &FileError{Op: op, Filename: filename, Err: err}
+}
+
 func (f *FileReceiver) backupFileName() string {
 	dir := filepath.Dir(f.filename)
 	fileName := filepath.Base(f.filename)
 	ext := filepath.Ext(fileName)
 	baseName := ess.StripExt(fileName)
-	t := time.Now()
+	t := now()
 	if f.isUTC {
 		t = t.UTC()
 	}
-	return filepath.Join(dir, fmt.Sprintf("%s-%s%s", baseName, t.Format(backupTimeFormat), ext))
+	backupName := fmt.Sprintf("%s-%s", baseName, t.Format(backupTimeFormat))
+
+	// rotation can happen more than once within a millisecond on high
+	// volume logging, don't overwrite the previous backup file
+	backupFile := filepath.Join(dir, backupName+ext)
+	for i := 1; ess.IsFileExists(backupFile); i++ {
+		backupFile = filepath.Join(dir, fmt.Sprintf("%s-%d%s", backupName, i, ext))
+	}
+	return backupFile
 }
 
 func (f *FileReceiver) getDay() int {
 	if f.isUTC {
-		return time.Now().UTC().Day()
+		return now().UTC().Day()
 	}
-	return time.Now().Day()
+	return now().Day()
 }
@@ -5,11 +5,20 @@
 package log
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"aahframe.work/config"
+	"aahframe.work/essentials"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -88,6 +97,46 @@ func TestFileLoggerRotation(t *testing.T) {
 	cleaupFiles("*.log")
 }
 
+func TestFileLoggerJSONLines(t *testing.T) {
+	defer cleaupFiles("*.log")
+	cfg, _ := config.ParseString(`log {
+    receiver = "file"
+    format = "jsonl"
+    file = "jsonl-aah-filename.log"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, jsonFmt, logger.receiver.(*FileReceiver).formatter)
+
+	logger.Info("multi-line\nmessage\r\nwith\ttabs")
+	logger.WithField("query", "SELECT *\nFROM users").Error("query failed")
+	logger.WithField("payload", indentedJSON{}).Warn("indented payload")
+	assert.Nil(t, logger.Close())
+
+	lines := strings.Split(strings.TrimSuffix(readFile("jsonl-aah-filename.log"), "\n"), "\n")
+	assert.Equal(t, 3, len(lines))
+	for _, line := range lines {
+		var m map[string]interface{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &m), line)
+	}
+	assert.True(t, strings.Contains(lines[0], `"message":"multi-line\nmessage\r\nwith\ttabs"`))
+
+	cfg, _ = config.ParseString(`log {
+    receiver = "file"
+    format = "jsonl"
+    line_ending = "none"
+    file = "jsonl-aah-filename.log"
+  }`)
+	_, err = New(cfg)
+	assert.Equal(t, "log: line ending 'none' is not applicable for format 'jsonl'", err.Error())
+}
+
+type indentedJSON struct{}
+
+func (indentedJSON) MarshalJSON() ([]byte, error) {
+	return []byte("{\n  \"id\": 1\n}"), nil
+}
+
 func TestFileLoggerFileOpenError(t *testing.T) {
 	fileConfigStr := `
   log {
@@ -98,7 +147,54 @@ func TestFileLoggerFileOpenError(t *testing.T) {
 	cfg, _ := config.ParseString(fileConfigStr)
 	logger, err := New(cfg)
 	assert.Nil(t, logger)
-	assert.True(t, os.IsNotExist(err))
+	assert.True(t, os.IsNotExist(err.(*FileError).Err))
+	assert.Equal(t, "log: unable to open file '': no such file or directory", err.Error())
+
+	// directory in place of file
+	configStr := `
+  log {
+    receiver = "file"
+    file = "dir-aah-filename.log"
+  }
+  `
+	_ = os.MkdirAll("dir-aah-filename.log", 0755)
+	defer func() { _ = os.Remove("dir-aah-filename.log") }()
+	cfg, _ = config.ParseString(configStr)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: unable to open file 'dir-aah-filename.log': is a directory", err.Error())
+
+	// file in place of directory
+	configStr = `
+  log {
+    receiver = "file"
+    file = "file-aah-dirname.log/aah.log"
+  }
+  `
+	_ = ioutil.WriteFile("file-aah-dirname.log", []byte{}, 0644)
+	defer func() { _ = os.Remove("file-aah-dirname.log") }()
+	cfg, _ = config.ParseString(configStr)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: unable to open file 'file-aah-dirname.log/aah.log': not a directory", err.Error())
+
+	// read-only file, permission is not enforced for root
+	if os.Geteuid() == 0 {
+		return
+	}
+	configStr = `
+  log {
+    receiver = "file"
+    file = "readonly-aah-filename.log"
+  }
+  `
+	_ = ioutil.WriteFile("readonly-aah-filename.log", []byte{}, 0444)
+	defer func() { _ = os.Remove("readonly-aah-filename.log") }()
+	cfg, _ = config.ParseString(configStr)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.True(t, os.IsPermission(err.(*FileError).Err))
+	assert.Equal(t, "log: unable to open file 'readonly-aah-filename.log': permission denied", err.Error())
 }
 
 func TestFileLoggerUnsupportedFormat(t *testing.T) {
@@ -129,7 +225,7 @@ func TestFileLoggerUnknownFormatFlag(t *testing.T) {
 	cfg, _ := config.ParseString(configStr)
 	logger, err := New(cfg)
 	assert.Nil(t, logger)
-	assert.Equal(t, "fmtflag: unknown flag 'myfile'", err.Error())
+	assert.Equal(t, "log: unknown flag '%myfile'", err.Error())
 }
 
 func TestFileLoggerIncorrectSizeValue(t *testing.T) {
@@ -151,6 +247,473 @@ func TestFileLoggerIncorrectSizeValue(t *testing.T) {
 	assert.Equal(t, "format: invalid input '500kbs'", err.Error())
 }
 
+func TestFileLoggerHealthy(t *testing.T) {
+	defer cleaupFiles("*.log")
+	configStr := `
+  log {
+    receiver = "file"
+    file = "healthy-aah-filename.log"
+  }
+  `
+	cfg, _ := config.ParseString(configStr)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	logger.Info("healthy log entry")
+	healthy, err := logger.Healthy()
+	assert.True(t, healthy)
+	assert.Nil(t, err)
+
+	// write on closed file
+	logger.receiver.(*FileReceiver).close()
+	logger.Info("unhealthy log entry")
+	healthy, err = logger.Healthy()
+	assert.False(t, healthy)
+	assert.NotNil(t, err)
+}
+
+func TestFileLoggerRotationHook(t *testing.T) {
+	defer cleaupFiles("*.log")
+	configStr := `
+  log {
+    receiver = "file"
+    file = "hook-aah-filename.log"
+    rotate {
+      policy = "lines"
+      lines = 2
+    }
+  }
+  `
+	cfg, _ := config.ParseString(configStr)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	rotated := make(chan string, 1)
+	receiver := logger.receiver.(*FileReceiver)
+	receiver.SetRotationHook(func(rotatedFilePath string) error {
+		rotated <- rotatedFilePath
+		return errors.New("upload failed")
+	})
+
+	logger.Info("line 1")
+	logger.Info("line 2")
+	logger.Info("line 3")
+
+	select {
+	case path := <-rotated:
+		assert.True(t, strings.HasPrefix(path, "hook-aah-filename-"))
+		_, err = os.Stat(path)
+		assert.Nil(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("rotation hook not invoked")
+	}
+
+	// hook error reported
+	assert.True(t, waitHealthyErr(logger, "upload failed"))
+
+	// hook panic reported
+	receiver.SetRotationHook(func(rotatedFilePath string) error {
+		panic("boom")
+	})
+	logger.Info("line 4")
+	logger.Info("line 5")
+	assert.True(t, waitHealthyErr(logger, "log: rotation hook panic: boom"))
+}
+
+func TestFileLoggerRotationHookOrder(t *testing.T) {
+	defer cleaupFiles("*.log")
+	cfg, _ := config.ParseString(`log {
+    receiver = "file"
+    file = "hook-order-aah-filename.log"
+    pattern = "%message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	var (
+		mu       sync.Mutex
+		running  int
+		parallel bool
+	)
+	rotated := make(chan string, 5)
+	receiver := logger.receiver.(*FileReceiver)
+	receiver.SetRotationHook(func(rotatedFilePath string) error {
+		mu.Lock()
+		running++
+		parallel = parallel || running > 1
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		b, err := ioutil.ReadFile(rotatedFilePath)
+		rotated <- strings.TrimSpace(string(b))
+		return err
+	})
+
+	for i := 1; i <= 5; i++ {
+		logger.Infof("line %d", i)
+		assert.Nil(t, logger.RotateNow())
+	}
+	assert.Nil(t, logger.Close())
+
+	// queued hooks run one at a time in rotation order, even after close
+	for i := 1; i <= 5; i++ {
+		select {
+		case content := <-rotated:
+			assert.Equal(t, fmt.Sprintf("line %d", i), content)
+		case <-time.After(2 * time.Second):
+			t.Fatal("rotation hook not invoked")
+		}
+	}
+	mu.Lock()
+	assert.False(t, parallel)
+	mu.Unlock()
+}
+
+func waitHealthyErr(logger *Logger, msg string) bool {
+	for i := 0; i < 100; i++ {
+		if _, err := logger.Healthy(); err != nil && err.Error() == msg {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestFileLoggerBuffered(t *testing.T) {
+	defer cleaupFiles("*.log")
+	configStr := `
+  log {
+    receiver = "file"
+    file = "buffer-aah-filename.log"
+    pattern = "%level:-5 %message"
+    buffer {
+      size = "4kb"
+      flush_interval = "50ms"
+    }
+  }
+  `
+	cfg, _ := config.ParseString(configStr)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	receiver := logger.receiver.(*FileReceiver)
+
+	logger.Info("buffered entry 1")
+	assert.Equal(t, "", readFile("buffer-aah-filename.log"))
+
+	// explicit flush
+	assert.Nil(t, receiver.Flush())
+	assert.Equal(t, "INFO  buffered entry 1 \n", readFile("buffer-aah-filename.log"))
+
+	// periodic flush
+	logger.Info("buffered entry 2")
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, "INFO  buffered entry 1 \nINFO  buffered entry 2 \n", readFile("buffer-aah-filename.log"))
+
+	// flush on close
+	logger.Info("buffered entry 3")
+	assert.Nil(t, logger.Close())
+	assert.True(t, strings.HasSuffix(readFile("buffer-aah-filename.log"), "INFO  buffered entry 3 \n"))
+	assert.Nil(t, receiver.Flush())
+
+	cfg, _ = config.ParseString(`log {
+    receiver = "file"
+    file = "buffer-aah-filename.log"
+    buffer {
+      size = "4kb"
+      flush_interval = "often"
+    }
+  }`)
+	_, err = New(cfg)
+	assert.Equal(t, "log: invalid buffer flush interval: time: invalid duration \"often\"", err.Error())
+}
+
+func TestFileLoggerBufferedFlushPanic(t *testing.T) {
+	defer cleaupFiles("*.log")
+	cfg, _ := config.ParseString(`log {
+    receiver = "file"
+    file = "panic-aah-filename.log"
+    pattern = "%level:-5 %message"
+    buffer {
+      size = "4kb"
+      flush_interval = "20ms"
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	receiver := logger.receiver.(*FileReceiver)
+
+	pw := &panicOnceWriter{WriteCloser: receiver.Writer().(io.WriteCloser)}
+	receiver.mu.Lock()
+	receiver.SetWriter(pw)
+	receiver.mu.Unlock()
+
+	logger.Info("entry before panic")
+	waitFileContent(t, "panic-aah-filename.log", "INFO  entry before panic \n")
+	assert.Equal(t, int64(1), receiver.stats.Panics())
+
+	// flusher restarted and continues processing
+	logger.Info("entry after panic")
+	waitFileContent(t, "panic-aah-filename.log", "INFO  entry before panic \nINFO  entry after panic \n")
+	assert.Equal(t, int64(1), receiver.stats.Panics())
+
+	assert.Nil(t, logger.Close())
+	assert.True(t, logger.Closed())
+}
+
+type panicOnceWriter struct {
+	io.WriteCloser
+	panicked bool
+}
+
+func (p *panicOnceWriter) Write(b []byte) (int, error) {
+	if !p.panicked {
+		p.panicked = true
+		panic("writer failure")
+	}
+	return p.WriteCloser.Write(b)
+}
+
+func waitFileContent(t *testing.T, name, expected string) {
+	for i := 0; i < 100 && readFile(name) != expected; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, expected, readFile(name))
+}
+
+func BenchmarkFileReceiverUnbuffered(b *testing.B) {
+	benchmarkFileReceiver(b, "")
+}
+
+func BenchmarkFileReceiverBuffered(b *testing.B) {
+	benchmarkFileReceiver(b, `buffer {
+      size = "64kb"
+    }`)
+}
+
+func benchmarkFileReceiver(b *testing.B, bufferCfg string) {
+	defer cleaupFiles("*.log")
+	cfg, _ := config.ParseString(`log {
+    receiver = "file"
+    file = "bench-aah-filename.log"
+    ` + bufferCfg + `
+  }`)
+	logger, _ := New(cfg)
+	receiver := logger.receiver.(*FileReceiver)
+	cw := &countWriter{w: receiver.Writer()}
+	receiver.SetWriter(cw)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Info("benchmark log entry for buffered and unbuffered file writes")
+	}
+	_ = receiver.Flush()
+	b.Logf("%.2f writes/op", float64(cw.writes)/float64(b.N))
+}
+
+type countWriter struct {
+	w      io.Writer
+	writes int
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	c.writes++
+	return c.w.Write(p)
+}
+
+func readFile(name string) string {
+	b, _ := ioutil.ReadFile(name)
+	return string(b)
+}
+
+func TestFileLoggerClose(t *testing.T) {
+	defer cleaupFiles("*.log")
+	configStr := `
+  log {
+    receiver = "file"
+    file = "close-aah-filename.log"
+  }
+  `
+	cfg, _ := config.ParseString(configStr)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	assert.False(t, logger.Closed())
+
+	logger.Info("before close")
+	assert.Nil(t, logger.Close())
+	assert.Nil(t, logger.Close()) // double close
+	assert.True(t, logger.Closed())
+
+	// log after close
+	receiver := logger.receiver.(*FileReceiver)
+	lines := receiver.stats.Lines()
+	logger.Info("after close")
+	assert.Equal(t, lines, receiver.stats.Lines())
+
+	healthy, err := logger.Healthy()
+	assert.False(t, healthy)
+	assert.Equal(t, ErrWriterIsClosed, err)
+
+	logger.receiver = nil
+	assert.Equal(t, ErrLogReceiverIsNil, logger.Close())
+	assert.False(t, logger.Closed())
+}
+
+func TestFileLoggerRotationConcurrent(t *testing.T) {
+	cleaupFiles("concurrent-aah-filename*.log")
+	defer cleaupFiles("concurrent-aah-filename*.log")
+
+	configStr := `
+  log {
+    receiver = "file"
+    level = "info"
+    pattern = "%level:-5 %message"
+    file = "concurrent-aah-filename.log"
+    rotate {
+      policy = "lines"
+      lines = 50
+    }
+  }
+  `
+	cfg, _ := config.ParseString(configStr)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	goroutines, entries := 20, 100
+	wg := sync.WaitGroup{}
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < entries; i++ {
+				logger.Infof("goroutine %d entry %d", g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.Nil(t, logger.Close())
+
+	files, _ := filepath.Glob("concurrent-aah-filename*.log")
+	assert.True(t, len(files) >= goroutines*entries/50)
+
+	var total int
+	for _, file := range files {
+		lines := strings.Count(readFile(file), "\n")
+		assert.True(t, lines <= 50, "file %s has %d lines", file, lines)
+		total += lines
+	}
+	assert.Equal(t, goroutines*entries, total)
+}
+
+func TestFileLoggerRotationCombinedPolicy(t *testing.T) {
+	cleaupFiles("combined-aah-filename*.log")
+	defer cleaupFiles("combined-aah-filename*.log")
+
+	clockTime := time.Date(2018, 6, 15, 23, 0, 0, 0, time.UTC)
+	SetClock(func() time.Time { return clockTime })
+	defer SetClock(nil)
+
+	newLogger := func(size string) *Logger {
+		cfg, _ := config.ParseString(`
+  log {
+    receiver = "file"
+    level = "info"
+    pattern = "%utctime:2006-01-02 %message"
+    file = "combined-aah-filename.log"
+    rotate {
+      policy = "daily, size"
+      size = "` + size + `"
+    }
+  }
+  `)
+		logger, err := New(cfg)
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"daily", "size"}, logger.receiver.(*FileReceiver).rotatePolicy)
+		return logger
+	}
+	backups := func() []string {
+		files, _ := filepath.Glob("combined-aah-filename-*.log")
+		return files
+	}
+
+	// size threshold reached before the daily boundary
+	logger := newLogger("50b")
+	logger.Info("entry 1") // 20 bytes
+	logger.Info("entry 2") // 40 bytes
+	logger.Info("entry 3") // 60 bytes, rotates on next write
+	assert.Equal(t, 0, len(backups()))
+	logger.Info("entry 4")
+	assert.Equal(t, 1, len(backups()))
+	assert.Equal(t, "2018-06-15 entry 4 \n", readFile("combined-aah-filename.log"))
+	assert.Nil(t, logger.Close())
+	cleaupFiles("combined-aah-filename*.log")
+
+	// daily boundary reached before the size threshold
+	logger = newLogger("500mb")
+	logger.Info("entry 1")
+	logger.Info("entry 2")
+	assert.Equal(t, 0, len(backups()))
+	clockTime = clockTime.Add(2 * time.Hour)
+	logger.Info("entry 3")
+	assert.Equal(t, 1, len(backups()))
+	assert.Equal(t, "2018-06-15 entry 1 \n2018-06-15 entry 2 \n", readFile(backups()[0]))
+	assert.Equal(t, "2018-06-16 entry 3 \n", readFile("combined-aah-filename.log"))
+	assert.Nil(t, logger.Close())
+}
+
+func TestFileLoggerRotateNow(t *testing.T) {
+	cleaupFiles("rotatenow-aah-filename*.log")
+	defer cleaupFiles("rotatenow-aah-filename*.log")
+
+	cfg, _ := config.ParseString(`
+  log {
+    receiver = "file"
+    pattern = "%level:-5 %message"
+    file = "rotatenow-aah-filename.log"
+    buffer {
+      size = "4kb"
+      flush_interval = "1h"
+    }
+    rotate {
+      policy = "lines"
+      lines = 3
+    }
+  }
+  `)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	receiver := logger.receiver.(*FileReceiver)
+
+	logger.Info("entry 1")
+	logger.Info("entry 2")
+	assert.Equal(t, "", readFile("rotatenow-aah-filename.log"))
+
+	assert.Nil(t, logger.RotateNow())
+	backups, _ := filepath.Glob("rotatenow-aah-filename-*.log")
+	assert.Equal(t, 1, len(backups))
+	assert.Equal(t, "INFO  entry 1 \nINFO  entry 2 \n", readFile(backups[0]))
+	assert.True(t, ess.IsFileExists("rotatenow-aah-filename.log"))
+	assert.Equal(t, int64(0), receiver.stats.Lines())
+
+	// rotation counters start afresh
+	logger.Info("entry 3")
+	logger.Info("entry 4")
+	logger.Info("entry 5")
+	assert.Nil(t, receiver.Flush())
+	assert.Equal(t, "INFO  entry 3 \nINFO  entry 4 \nINFO  entry 5 \n", readFile("rotatenow-aah-filename.log"))
+	backups, _ = filepath.Glob("rotatenow-aah-filename-*.log")
+	assert.Equal(t, 1, len(backups))
+
+	assert.Nil(t, logger.Close())
+	assert.Equal(t, ErrWriterIsClosed, receiver.RotateNow())
+
+	// console receiver doesn't support rotation
+	cfg, _ = config.ParseString(`log { }`)
+	logger, _ = New(cfg)
+	assert.Equal(t, ErrRotationNotSupported, logger.RotateNow())
+}
+
 func testFileLogger(t *testing.T, cfgStr string, loop int) {
 	cfg, _ := config.ParseString(cfgStr)
 	logger, err := New(cfg)
@@ -6,10 +6,18 @@ package log
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode/utf8"
 
+	"aahframe.work/config"
 	"aahframe.work/essentials"
 )
 
@@ -28,16 +36,33 @@ const (
 	FmtFlagMessage
 	FmtFlagFields
 	FmtFlagCustom
+	FmtFlagHostname
+	FmtFlagPID
+	FmtFlagDuration
+	FmtFlagComponent
+	FmtFlagFunc
 	FmtFlagUnknown
 )
 
 const (
-	textFmt = "text"
-	jsonFmt = "json"
-	space   = " "
+	textFmt   = "text"
+	jsonFmt   = "json"
+	jsonlFmt  = "jsonl"
+	customFmt = "custom"
+	space     = " "
 )
 
 type (
+	// Formatter func type is used to format the log entry as per given format
+	// flags into bytes, including trailing newline.
+	Formatter func(flags []ess.FmtFlagPart, entry *Entry) []byte
+
+	// FormatterSetter interface is implemented by log receiver to swap the
+	// log entry formatter at runtime.
+	FormatterSetter interface {
+		SetFormatter(fn Formatter)
+	}
+
 	// FlagPart is indiviual flag details
 	//  For e.g.:
 	//    part := FlagPart{
@@ -71,9 +96,15 @@ var (
 	//    longfile  - outputs full file name: /a/b/c/d.go
 	//    shortfile - outputs final file name element: d.go
 	//    line      - outputs file line number: L23
+	//    func      - outputs caller function name: (*Request).Locale
 	//    message   - outputs given message along supplied arguments if they present
 	//    fields    - outputs field values into log entry
 	//    custom    - outputs string as-is into log entry
+	//    hostname  - outputs host name of the machine
+	//    pid       - outputs process ID
+	//    duration  - outputs field `duration` value in milliseconds, e.g.: 12.345ms.
+	//                Precision is configurable, e.g.: %duration:.1, refer to `DurationFieldKey`
+	//    component - outputs logger component name, refer to `Logger.WithName`
 	FmtFlags = map[string]ess.FmtFlag{
 		"level":     FmtFlagLevel,
 		"appname":   FmtFlagAppName,
@@ -88,9 +119,85 @@ var (
 		"message":   FmtFlagMessage,
 		"fields":    FmtFlagFields,
 		"custom":    FmtFlagCustom,
+		"hostname":  FmtFlagHostname,
+		"pid":       FmtFlagPID,
+		"duration":  FmtFlagDuration,
+		"component": FmtFlagComponent,
+		"func":      FmtFlagFunc,
 	}
+
+	// DurationPrecision is default number of decimal places of milliseconds
+	// for format flag `duration` and JSON field `duration_ms`.
+	DurationPrecision = 3
+
+	// TextFormatter formats the log entry as per log pattern.
+	TextFormatter Formatter = textFormatter
+
+	// JSONFormatter formats the log entry as JSON, format flags are not
+	// applicable. Output is always single line, newlines in message and
+	// fields are escaped. Config `log.format = "jsonl"` is alias of `json`
+	// for JSON lines (newline-delimited JSON) output.
+	JSONFormatter Formatter = jsonFormatter
+
+	// hostname and pid resolved once at startup, to avoid syscall per log entry
+	hostname = resolveHostname()
+	pid      = os.Getpid()
 )
 
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Package methods
+//___________________________________
+
+// ValidatePattern method validates the given log format pattern and reports
+// the specific problem, so that configuration mistakes are surfaced at startup
+// instead of producing malformed log entries. It reports-
+//
+//  - unknown flag name along with closest known flag name if any
+//
+//  - time flag without layout or malformed time layout
+//
+//  - no `%message` flag present in the pattern
+func ValidatePattern(pattern string) error {
+	pattern = strings.TrimSpace(pattern)
+	if len(pattern) == 0 {
+		return fmt.Errorf("log: pattern is empty")
+	}
+
+	flags, err := ess.ParseFmtFlag(pattern, FmtFlags)
+	if err != nil {
+		for _, f := range strings.Split(pattern, ess.FmtFlagSeparator)[1:] {
+			name := strings.SplitN(strings.TrimSpace(f), ess.FmtFlagValueSeparator, 2)[0]
+			if _, found := FmtFlags[name]; found {
+				continue
+			}
+			if suggest := closestFlagName(name); len(suggest) > 0 {
+				return fmt.Errorf("log: unknown flag '%%%s', did you mean '%%%s'", name, suggest)
+			}
+			return fmt.Errorf("log: unknown flag '%%%s'", name)
+		}
+		return err
+	}
+
+	for _, part := range flags {
+		if part.Flag == FmtFlagTime || part.Flag == FmtFlagUTCTime {
+			layout := strings.TrimSpace(part.Format)
+			if layout == "%v" || len(layout) == 0 {
+				return fmt.Errorf("log: flag '%%%s' requires time layout, e.g. '%%%s:2006-01-02 15:04:05.000'",
+					part.Name, part.Name)
+			}
+			if time.Date(1999, 11, 28, 21, 32, 48, 0, time.UTC).Format(layout) == layout {
+				return fmt.Errorf("log: malformed time layout '%s' for flag '%%%s'", layout, part.Name)
+			}
+		}
+	}
+
+	if !isFmtFlagExists(flags, FmtFlagMessage) {
+		return fmt.Errorf("log: no '%%message' flag present in pattern '%s'", pattern)
+	}
+
+	return nil
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // textFormatter
 //___________________________________
@@ -98,7 +205,147 @@ var (
 // textFormatter formats the `Entry` object details as per log `pattern`
 // 	For e.g.:
 // 		2016-07-02 22:26:01.530 INFO formatter_test.go L29 - Yes, I would love to see
+//
+// Common patterns (e.g. `DefaultPattern`) are rendered via fast path with
+// direct byte appends, others via generic path.
 func textFormatter(flags []ess.FmtFlagPart, entry *Entry) []byte {
+	if isFastTextPattern(flags) {
+		return appendText(make([]byte, 0, 64+len(entry.Message)), flags, entry)
+	}
+	return formatText(flags, entry, defaultTextFields)
+}
+
+// textFieldsFormat is the rendering of format flag `fields` in text format.
+type textFieldsFormat struct {
+	logfmt    bool
+	separator string
+}
+
+var defaultTextFields = textFieldsFormat{separator: ", "}
+
+// textFormatterByConfig method returns the text formatter as per config
+// `log.text.fields` and `log.text.field_separator`.
+//
+// Fields are rendered as `fields[key: value, ...]` by default (`plain`).
+// Value `logfmt` renders them as `key=value` pairs sorted by key, value is
+// quoted if it's empty or contains space, `=`, `"` or control characters,
+// so that logfmt consumers parse it reliably. Separator defaults to `, `
+// for `plain`. Pairs are always separated by space for `logfmt`, so
+// `field_separator` is not allowed with it.
+//
+// 	For e.g.:
+// 		log {
+// 		  pattern = "%time:2006-01-02 15:04:05.000 %level:-5 %message %fields"
+// 		  text {
+// 		    fields = "logfmt"
+// 		  }
+// 		}
+func textFormatterByConfig(cfg *config.Config) (Formatter, error) {
+	tf := defaultTextFields
+	switch mode := cfg.StringDefault("log.text.fields", "plain"); mode {
+	case "plain":
+	case "logfmt":
+		tf = textFieldsFormat{logfmt: true, separator: " "}
+	default:
+		return nil, fmt.Errorf("log: unsupported text fields mode '%s', valid values are plain, logfmt", mode)
+	}
+	if sep, found := cfg.String("log.text.field_separator"); found && len(sep) > 0 {
+		if tf.logfmt {
+			return nil, errors.New("log: text field separator is not supported for logfmt fields mode")
+		}
+		tf.separator = sep
+	}
+	if tf == defaultTextFields {
+		return TextFormatter, nil
+	}
+
+	return func(flags []ess.FmtFlagPart, entry *Entry) []byte {
+		if isFastTextPattern(flags) {
+			return appendText(make([]byte, 0, 64+len(entry.Message)), flags, entry)
+		}
+		return formatText(flags, entry, tf)
+	}, nil
+}
+
+// isFastTextPattern method returns true if all the format flags are
+// supported by fast path `appendText`.
+func isFastTextPattern(flags []ess.FmtFlagPart) bool {
+	for _, part := range flags {
+		switch part.Flag {
+		case FmtFlagTime, FmtFlagUTCTime, FmtFlagMessage, FmtFlagCustom,
+			FmtFlagAppName, FmtFlagInstanceName, FmtFlagRequestID,
+			FmtFlagPrincipal, FmtFlagComponent:
+		case FmtFlagLevel:
+			if part.Format != "%v" && part.Format != "%-5v" {
+				return false
+			}
+		case FmtFlagLongfile, FmtFlagShortfile, FmtFlagLine, FmtFlagFunc:
+			if part.Format != "%v" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// appendText method appends the formatted entry to given bytes without
+// `fmt` package, output is same as generic path `formatText`.
+func appendText(buf []byte, flags []ess.FmtFlagPart, entry *Entry) []byte {
+	appendValue := func(v string) {
+		if len(v) > 0 {
+			buf = append(append(buf, v...), ' ')
+		}
+	}
+
+	for _, part := range flags {
+		switch part.Flag {
+		case FmtFlagLevel:
+			name := entry.Level.String()
+			buf = append(buf, name...)
+			if part.Format == "%-5v" {
+				for i := len(name); i < 5; i++ {
+					buf = append(buf, ' ')
+				}
+			}
+			buf = append(buf, ' ')
+		case FmtFlagAppName:
+			appendValue(entry.AppName)
+		case FmtFlagInstanceName:
+			appendValue(entry.InstanceName)
+		case FmtFlagRequestID:
+			appendValue(entry.RequestID)
+		case FmtFlagPrincipal:
+			appendValue(entry.Principal)
+		case FmtFlagComponent:
+			appendValue(entry.Component)
+		case FmtFlagTime:
+			buf = append(entry.Time.AppendFormat(buf, part.Format), ' ')
+		case FmtFlagUTCTime:
+			buf = append(entry.Time.UTC().AppendFormat(buf, part.Format), ' ')
+		case FmtFlagLongfile, FmtFlagShortfile:
+			if part.Flag == FmtFlagShortfile {
+				entry.File = filepath.Base(entry.File)
+			}
+			buf = append(append(buf, entry.File...), ' ')
+		case FmtFlagLine:
+			buf = append(strconv.AppendInt(append(buf, 'L'), int64(entry.Line), 10), ' ')
+		case FmtFlagFunc:
+			buf = append(append(buf, entry.Func...), ' ')
+		case FmtFlagMessage:
+			buf = append(append(buf, entry.Message...), ' ')
+		case FmtFlagCustom:
+			buf = append(append(buf, part.Format...), ' ')
+		}
+	}
+
+	return append(buf, '\n')
+}
+
+// formatText method is generic path of text formatter, it supports all the
+// format flags.
+func formatText(flags []ess.FmtFlagPart, entry *Entry, tf textFieldsFormat) []byte {
 	buf := new(bytes.Buffer)
 
 	for _, part := range flags {
@@ -132,11 +379,32 @@ func textFormatter(flags []ess.FmtFlagPart, entry *Entry) []byte {
 			buf.WriteString(fmt.Sprintf(part.Format, entry.File) + space)
 		case FmtFlagLine:
 			buf.WriteString("L" + fmt.Sprintf(part.Format, entry.Line) + space)
+		case FmtFlagFunc:
+			buf.WriteString(fmt.Sprintf(part.Format, entry.Func) + space)
 		case FmtFlagMessage:
 			buf.WriteString(entry.Message + space)
 		case FmtFlagCustom:
 			buf.WriteString(part.Format + space)
+		case FmtFlagHostname:
+			buf.WriteString(fmt.Sprintf(part.Format, hostname) + space)
+		case FmtFlagPID:
+			buf.WriteString(fmt.Sprintf(part.Format, pid) + space)
+		case FmtFlagDuration:
+			if d, found := entry.duration(); found {
+				buf.WriteString(formatDuration(part.Format, d) + space)
+			}
+		case FmtFlagComponent:
+			if len(entry.Component) > 0 {
+				buf.WriteString(entry.Component + space)
+			}
 		case FmtFlagFields:
+			if tf.logfmt {
+				if fs := logfmtFields(entry); len(fs) > 0 {
+					buf.WriteString(strings.Join(fs, tf.separator) + space)
+				}
+				continue
+			}
+
 			fs := make([]string, 0)
 			for k, v := range entry.Fields {
 				if !entry.isSkipField(k) {
@@ -145,7 +413,7 @@ func textFormatter(flags []ess.FmtFlagPart, entry *Entry) []byte {
 			}
 
 			if len(fs) > 0 {
-				buf.WriteString("fields[" + strings.Join(fs, ", ") + "] ")
+				buf.WriteString("fields[" + strings.Join(fs, tf.separator) + "] ")
 			}
 		}
 	}
@@ -153,3 +421,196 @@ func textFormatter(flags []ess.FmtFlagPart, entry *Entry) []byte {
 	buf.WriteByte('\n')
 	return buf.Bytes()
 }
+
+// logfmtFields method returns the entry fields as logfmt `key=value` pairs
+// sorted by key.
+func logfmtFields(entry *Entry) []string {
+	keys := make([]string, 0, len(entry.Fields))
+	for k := range entry.Fields {
+		if !entry.isSkipField(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	fs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		fs = append(fs, logfmtValue(k)+"="+logfmtValue(fmt.Sprint(entry.Fields[k])))
+	}
+	return fs
+}
+
+// logfmtValue method quotes the given value if it's empty or contains space,
+// `=`, `"` or control characters, embedded quotes are escaped.
+func logfmtValue(v string) string {
+	if len(v) == 0 {
+		return `""`
+	}
+	for _, r := range v {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || r == 0x7f {
+			return strconv.Quote(v)
+		}
+	}
+	return v
+}
+
+// formatDuration method formats the duration in milliseconds as per format
+// flag value, e.g.: `%.1v` => `12.3ms`.
+func formatDuration(format string, d time.Duration) string {
+	if format == "%v" {
+		format = "%." + strconv.Itoa(DurationPrecision) + "v"
+	}
+	return fmt.Sprintf(strings.TrimSuffix(format, "v")+"fms", durationMillis(d))
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// jsonFormatter formats the `Entry` object as JSON.
+func jsonFormatter(_ []ess.FmtFlagPart, entry *Entry) []byte {
+	msg, _ := json.Marshal(entry)
+	return append(msg, '\n')
+}
+
+// jsonFormatterByConfig method returns the JSON formatter with key order
+// from config `log.json.field_order` (e.g. ["timestamp", "level", "message"]),
+// listed keys are written first in the given order, then remaining keys in
+// sorted order. Otherwise `JSONFormatter`.
+func jsonFormatterByConfig(cfg *config.Config) Formatter {
+	if order, found := cfg.StringList("log.json.field_order"); found && len(order) > 0 {
+		return orderedJSONFormatter(order)
+	}
+	return JSONFormatter
+}
+
+// orderedJSONFormatter method returns the JSON formatter which writes the
+// top-level keys in deterministic order.
+func orderedJSONFormatter(order []string) Formatter {
+	return func(flags []ess.FmtFlagPart, entry *Entry) []byte {
+		msg, _ := json.Marshal(entry)
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(msg, &values); err != nil {
+			return append(msg, '\n')
+		}
+
+		buf := new(bytes.Buffer)
+		buf.WriteByte('{')
+		write := func(key string) {
+			if buf.Len() > 1 {
+				buf.WriteByte(',')
+			}
+			k, _ := json.Marshal(key)
+			buf.Write(k)
+			buf.WriteByte(':')
+			buf.Write(values[key])
+			delete(values, key)
+		}
+
+		for _, key := range order {
+			if _, found := values[key]; found {
+				write(key)
+			}
+		}
+
+		keys := make([]string, 0, len(values))
+		for key := range values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			write(key)
+		}
+
+		buf.WriteString("}\n")
+		return buf.Bytes()
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// line ending
+//___________________________________
+
+// lineEndings maps config `log.line_ending` value to log entry terminator.
+var lineEndings = map[string][]byte{
+	"lf":   []byte("\n"),
+	"crlf": []byte("\r\n"),
+	"none": nil,
+}
+
+// parseLineEnding method returns the log entry terminator for config
+// `log.line_ending` (default `lf`), values are `lf`, `crlf` and `none`.
+func parseLineEnding(cfg *config.Config) ([]byte, error) {
+	value := strings.ToLower(strings.TrimSpace(cfg.StringDefault("log.line_ending", "lf")))
+	if len(value) == 0 {
+		value = "lf"
+	}
+	ending, found := lineEndings[value]
+	if !found {
+		return nil, fmt.Errorf("log: unsupported line ending '%s', valid values are lf, crlf, none", value)
+	}
+	if len(ending) == 0 && cfg.StringDefault("log.format", textFmt) == jsonlFmt {
+		return nil, fmt.Errorf("log: line ending '%s' is not applicable for format '%s'", value, jsonlFmt)
+	}
+	return ending, nil
+}
+
+// terminate method replaces the trailing newline of formatted log entry
+// with given line ending.
+func terminate(msg, ending []byte) []byte {
+	if len(ending) == 1 && ending[0] == '\n' && bytes.HasSuffix(msg, ending) {
+		return msg
+	}
+	msg = bytes.TrimSuffix(bytes.TrimSuffix(msg, []byte("\n")), []byte("\r"))
+	return append(msg, ending...)
+}
+
+// parseMinLevel method returns the value of config `log.min_level`, it's the
+// receiver's own minimum level independent of the logger level. It defaults
+// to `TRACE`, i.e. receiver accepts what logger level allows.
+func parseMinLevel(cfg *config.Config) (LogLevel, error) {
+	name := cfg.StringDefault("log.min_level", "")
+	if ess.IsStrEmpty(name) {
+		return LevelTrace, nil
+	}
+	lvl := levelByName(name)
+	if lvl == LevelUnknown {
+		return LevelUnknown, unknownLevelError(name)
+	}
+	return lvl, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// message truncation
+//___________________________________
+
+// truncatedMarker is appended to the log message truncated by config
+// `log.max_message_len`.
+const truncatedMarker = "...[truncated]"
+
+// parseMaxMessageLen method returns the value of config `log.max_message_len`
+// in bytes, `0` (default) disables the truncation.
+func parseMaxMessageLen(cfg *config.Config) (int, error) {
+	maxLen := cfg.IntDefault("log.max_message_len", 0)
+	if maxLen < 0 {
+		return 0, fmt.Errorf("log: max message length '%d' must not be negative", maxLen)
+	}
+	return maxLen, nil
+}
+
+// truncateEntry method returns the copy of given entry with message truncated
+// to maxLen bytes and `...[truncated]` marker appended, truncation respects the
+// UTF-8 boundary. Entry is returned as-is if it's within the limit, it
+// reports true if truncated.
+func truncateEntry(e *Entry, maxLen int) (*Entry, bool) {
+	if maxLen <= 0 || len(e.Message) <= maxLen {
+		return e, false
+	}
+	n := maxLen
+	for n > 0 && !utf8.RuneStart(e.Message[n]) {
+		n--
+	}
+	ce := *e
+	ce.Message = e.Message[:n] + truncatedMarker
+	return &ce, true
+}
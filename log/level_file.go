@@ -0,0 +1,98 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+const defaultLevelFileInterval = 5 * time.Second
+
+// WatchLevelFile method watches the given file by polling on every interval
+// and applies its contents as log level whenever it changes, e.g. ops team
+// bumps the verbosity in production without restart. It works even when the
+// application HTTP endpoints are unreachable. Invalid contents are ignored
+// with a warning, missing file is ignored. Watching is stopped on
+// `Logger.Close`, calling it again replaces the existing watch.
+//
+// It can be configured via config too, default interval is `5s`.
+//
+// 	For e.g.:
+// 		log {
+// 		  level_file = "/etc/myapp/log-level"
+// 		  level_file_interval = "10s"
+// 		}
+//
+// 		echo "trace" > /etc/myapp/log-level
+func (l *Logger) WatchLevelFile(file string, interval time.Duration) error {
+	if len(file) == 0 {
+		return errors.New("log: level file path is empty")
+	}
+	if interval <= 0 {
+		interval = defaultLevelFileInterval
+	}
+
+	stop := make(chan struct{})
+	l.m.Lock()
+	if l.stopLevelWatch != nil {
+		close(l.stopLevelWatch)
+	}
+	l.stopLevelWatch = stop
+	l.m.Unlock()
+
+	last := l.applyLevelFile(file, "")
+	go l.watchLevelFile(file, interval, last, stop)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (l *Logger) initLevelFile() error {
+	file := l.cfg.StringDefault("log.level_file", "")
+	if len(file) == 0 {
+		return nil
+	}
+	interval, err := time.ParseDuration(l.cfg.StringDefault("log.level_file_interval", "5s"))
+	if err != nil {
+		return fmt.Errorf("log: invalid level file interval: %s", err)
+	}
+	return l.WatchLevelFile(file, interval)
+}
+
+func (l *Logger) watchLevelFile(file string, interval time.Duration, last string, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			last = l.applyLevelFile(file, last)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// applyLevelFile method reads the level file and applies it if contents
+// changed from last, it returns the current contents.
+func (l *Logger) applyLevelFile(file, last string) string {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return last
+	}
+	level := strings.TrimSpace(string(b))
+	if level == last || len(level) == 0 {
+		return level
+	}
+	if err = l.SetLevel(level); err != nil {
+		l.Warnf("log: ignoring level file '%s': %s", file, err)
+	}
+	return level
+}
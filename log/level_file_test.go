@@ -0,0 +1,153 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogLevelFileWatch(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "aah-log-level")
+	defer os.RemoveAll(dir)
+	levelFile := filepath.Join(dir, "log-level")
+	assert.Nil(t, ioutil.WriteFile(levelFile, []byte("warn\n"), 0644))
+
+	cfg, _ := config.ParseString(`log {
+    level = "info"
+    pattern = "%level %message"
+    level_file = "` + filepath.ToSlash(levelFile) + `"
+    level_file_interval = "10ms"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	out := &syncBuffer{}
+	logger.SetWriter(out)
+
+	// initial contents applied on start
+	assert.Equal(t, "WARN", logger.Level())
+
+	assert.Nil(t, ioutil.WriteFile(levelFile, []byte("trace"), 0644))
+	assert.True(t, waitForLevel(logger, "TRACE"), "level is %s", logger.Level())
+
+	// invalid contents are ignored with a warning
+	assert.Nil(t, ioutil.WriteFile(levelFile, []byte("verbose"), 0644))
+	assert.True(t, waitFor(func() bool { return strings.Contains(out.String(), "ignoring level file") }))
+	assert.Equal(t, "TRACE", logger.Level())
+	assert.True(t, strings.Contains(out.String(), "WARN log: ignoring level file '"+filepath.ToSlash(levelFile)+"': log: unknown log level 'verbose'"))
+
+	// missing file is ignored
+	assert.Nil(t, os.Remove(levelFile))
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, "TRACE", logger.Level())
+
+	assert.Nil(t, ioutil.WriteFile(levelFile, []byte("error"), 0644))
+	assert.True(t, waitForLevel(logger, "ERROR"), "level is %s", logger.Level())
+
+	// stopped on close
+	assert.Nil(t, logger.Close())
+	assert.Nil(t, ioutil.WriteFile(levelFile, []byte("debug"), 0644))
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, "ERROR", logger.Level())
+
+	assert.Equal(t, "log: level file path is empty", logger.WatchLevelFile("", 0).Error())
+
+	// invalid interval
+	cfg, _ = config.ParseString(`log {
+    level_file = "log-level"
+    level_file_interval = "often"
+  }`)
+	_, err = New(cfg)
+	assert.Equal(t, "log: invalid level file interval: time: invalid duration \"often\"", err.Error())
+}
+
+func TestLogLevelFileConcurrentReload(t *testing.T) {
+	dir, _ := ioutil.TempDir("", "aah-log-level")
+	defer os.RemoveAll(dir)
+	levelFile := filepath.Join(dir, "log-level")
+	assert.Nil(t, ioutil.WriteFile(levelFile, []byte("info"), 0644))
+
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level %message"
+    level_file = "` + filepath.ToSlash(levelFile) + `"
+    level_file_interval = "1ms"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	defer logger.Close()
+	logger.SetWriter(&syncBuffer{})
+	child := logger.New(Fields{"reqid": "req-1"})
+
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				logger.Debug("debug message")
+				child.WithField("key", "value").Info("info message")
+				_ = logger.Enabled(LevelTrace)
+				_ = child.IsLevelDebug()
+				_ = logger.WithCategory(DefaultCategory)
+			}
+		}()
+	}
+
+	for _, level := range []string{"debug", "warn", "trace", "error", "info"} {
+		assert.Nil(t, ioutil.WriteFile(levelFile, []byte(level), 0644))
+		assert.True(t, waitForLevel(logger, strings.ToUpper(level)), "level is %s", logger.Level())
+	}
+	close(stop)
+	wg.Wait()
+
+	// child logger shares the level
+	assert.Equal(t, "INFO", child.Level())
+}
+
+func waitForLevel(logger *Logger, level string) bool {
+	return waitFor(func() bool { return logger.Level() == level })
+}
+
+func waitFor(cond func() bool) bool {
+	for i := 0; i < 200; i++ {
+		if cond() {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
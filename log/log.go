@@ -23,21 +23,30 @@ import (
 	"io"
 	slog "log"
 	"os"
+	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"aahframe.work/config"
 )
 
-// Level type definition
-type level uint8
+// PanicFieldKey is the field key for panic value logged by method
+// `RecoverAndLog`.
+const PanicFieldKey = "panic"
+
+const stackBufSize = 64 << 10
+
+// LogLevel type is the logging level, refer to constants `LevelFatal` ... `LevelTrace`.
+type LogLevel uint8
 
 // HookFunc type is aah framework logger custom hook.
 type HookFunc func(e Entry)
 
 // Log Level definition
 const (
-	LevelFatal level = iota
+	LevelFatal LogLevel = iota
 	LevelPanic
 	LevelError
 	LevelWarn
@@ -54,11 +63,26 @@ var (
 	// ErrHookFuncIsNil is returned when hook function is nil.
 	ErrHookFuncIsNil = errors.New("log: hook func is nil")
 
+	// ErrWriterIsClosed returned when log entry is written after the log
+	// receiver is closed.
+	ErrWriterIsClosed = errors.New("log: writer is closed")
+
+	// ErrFormatterNotSupported returned when log receiver doesn't support
+	// setting the formatter.
+	ErrFormatterNotSupported = errors.New("log: receiver does not support formatter")
+
+	// ErrRotationNotSupported returned when log receiver doesn't support
+	// on demand rotation.
+	ErrRotationNotSupported = errors.New("log: receiver does not support rotation")
+
 	filePermission = os.FileMode(0755)
 
 	// abstract it, can be unit tested
 	exit = os.Exit
 
+	// clock holds `func() time.Time`, refer to `SetClock`
+	clock atomic.Value
+
 	_ Loggerer = (*Logger)(nil)
 )
 
@@ -67,12 +91,18 @@ type (
 	// format flags. Logger can be used simultaneously from multiple goroutines;
 	// it guarantees to serialize access to the Receivers.
 	Logger struct {
-		cfg      *config.Config
-		m        *sync.RWMutex
-		level    level
-		receiver Receiver
-		ctx      Fields
-		hooks    map[string]HookFunc
+		cfg            *config.Config
+		m              *sync.RWMutex
+		level          *uint32
+		receiver       Receiver
+		categories     map[string]Receiver
+		sampler        *sampler
+		name           string
+		ctx            Fields
+		static         Fields
+		hooks          map[string]HookFunc
+		stopLevelWatch chan struct{}
+		async          *asyncWriter
 	}
 
 	// Receiver is the interface for pluggable log receiver.
@@ -86,8 +116,46 @@ type (
 		Log(e *Entry)
 	}
 
+	// HealthChecker interface is implemented by log receiver to report its
+	// health, for e.g.: last write succeeded or not. It's optional for
+	// receiver, logger considers receiver as healthy when not implemented.
+	HealthChecker interface {
+		Healthy() (bool, error)
+	}
+
+	// Rotator interface is implemented by log receiver which supports on
+	// demand rotation, refer to `Logger.RotateNow`.
+	Rotator interface {
+		RotateNow() error
+	}
+
+	// StatsReporter interface is implemented by log receiver which tracks
+	// its counters, refer to `Logger.Stats`.
+	StatsReporter interface {
+		Stats() ReceiverStats
+	}
+
+	// LevelEnabler interface is implemented by log receiver which gates the
+	// log entries by its own minimum level, for e.g.: category receiver with
+	// config `min_level`. Logger checks it before building the log entry,
+	// refer to `Logger.Enabled`. It's optional for receiver, logger considers
+	// all levels enabled when not implemented.
+	LevelEnabler interface {
+		Enabled(lvl LogLevel) bool
+	}
+
+	// Closer interface is implemented by log receiver to release its writer
+	// resources on shutdown. Close is idempotent, log entries written after
+	// close are dropped and reported as `ErrWriterIsClosed` via `Healthy`.
+	Closer interface {
+		Close() error
+		Closed() bool
+	}
+
 	// Loggerer interface is for Logger and Entry log method implementation.
 	Loggerer interface {
+		Log(lvl LogLevel, v ...interface{})
+		Logf(lvl LogLevel, format string, v ...interface{})
 		Error(v ...interface{})
 		Errorf(format string, v ...interface{})
 		Warn(v ...interface{})
@@ -136,27 +204,60 @@ func New(cfg *config.Config) (*Logger, error) {
 		return nil, errors.New("log: config is nil")
 	}
 
-	logger := &Logger{m: &sync.RWMutex{}, cfg: cfg}
+	logger := &Logger{m: &sync.RWMutex{}, cfg: cfg, level: new(uint32)}
 
 	// Receiver
 	receiverType := strings.ToUpper(cfg.StringDefault("log.receiver", "CONSOLE"))
+	isCategory := receiverType == categoryReceiver
+	if isCategory {
+		receiverType = "FILE"
+	}
 	if err := logger.SetReceiver(getReceiverByName(receiverType)); err != nil {
 		return nil, err
 	}
 
 	// Pattern
-	if err := logger.SetPattern(cfg.StringDefault("log.pattern", DefaultPattern)); err != nil {
+	pattern := cfg.StringDefault("log.pattern", DefaultPattern)
+	if err := ValidatePattern(pattern); err != nil {
+		return nil, err
+	}
+	if err := logger.SetPattern(pattern); err != nil {
 		return nil, err
 	}
 
+	// Categories
+	if isCategory {
+		if err := logger.initCategories(pattern); err != nil {
+			return nil, err
+		}
+	}
+
 	// Level
 	if err := logger.SetLevel(cfg.StringDefault("log.level", "DEBUG")); err != nil {
 		return nil, err
 	}
 
+	// Sampling
+	if err := logger.initSampling(); err != nil {
+		return nil, err
+	}
+
+	// Async
+	if err := logger.initAsync(); err != nil {
+		return nil, err
+	}
+
 	logger.ctx = make(Fields)
 	logger.hooks = make(map[string]HookFunc)
 
+	// Static fields
+	logger.initStaticFields()
+
+	// Level file
+	if err := logger.initLevelFile(); err != nil {
+		return nil, err
+	}
+
 	return logger, nil
 }
 
@@ -170,6 +271,48 @@ func NewWithContext(cfg *config.Config, ctx Fields) (*Logger, error) {
 	return l, nil
 }
 
+// RecoverAndLog method recovers the panic and logs the panic value with stack
+// trace at `ERROR` level using given logger (default logger if nil), given
+// fields and field `panic`. It has to be used with `defer` directly. If
+// repanic is true, panic is rethrown after logging otherwise it's swallowed.
+//
+// 	For e.g.:
+// 		defer log.RecoverAndLog(logger, log.Fields{"handler": "users"}, false)
+func RecoverAndLog(logger Loggerer, fields Fields, repanic bool) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	if logger == nil {
+		logger = dl
+	}
+
+	buf := make([]byte, stackBufSize)
+	buf = buf[:runtime.Stack(buf, false)]
+
+	f := make(Fields, len(fields)+1)
+	for k, v := range fields {
+		f[k] = v
+	}
+	f[PanicFieldKey] = fmt.Sprint(r)
+	logger.WithFields(f).Errorf("panic recovered: %v\n%s", r, buf)
+
+	if repanic {
+		panic(r)
+	}
+}
+
+// SetClock method sets the clock used for log entry timestamp and file
+// rotation, e.g. frozen clock in the tests to assert exact output. Nil resets
+// it to `time.Now`, which is the default.
+func SetClock(fn func() time.Time) {
+	if fn == nil {
+		fn = time.Now
+	}
+	clock.Store(fn)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Logger methods
 //___________________________________
@@ -177,7 +320,8 @@ func NewWithContext(cfg *config.Config, ctx Fields) (*Logger, error) {
 // New method creates a child logger and adds structured context to it. Child
 // logger inherits parent logger context value on creation. Fields added
 // to the child don't affect the parent logger and vice versa. These context
-// values gets logged with each log entry.
+// values gets logged with each log entry. Log level is shared with the
+// parent logger.
 //
 // Also you can use method `AddContext` to context to the current logger.
 func (l *Logger) New(fields Fields) *Logger {
@@ -188,6 +332,62 @@ func (l *Logger) New(fields Fields) *Logger {
 	return &nl
 }
 
+// WithName method creates a child logger with given component name, it gets
+// logged with each log entry as `component`. Names compose on repeated calls
+// with dot separator. Child logger inherits parent logger context as method
+// `New` does.
+//
+// 	For e.g.:
+// 		poolLog := log.WithName("db").WithName("pool")
+// 		poolLog.Info("connection acquired") // component is `db.pool`
+//
+// 		log {
+// 		  pattern = "%time:2006-01-02 15:04:05.000 %level:-5 %component %message"
+// 		}
+func (l *Logger) WithName(component string) *Logger {
+	nl := l.New(nil)
+	if len(component) > 0 {
+		if len(nl.name) > 0 {
+			nl.name += "." + component
+		} else {
+			nl.name = component
+		}
+	}
+	return nl
+}
+
+// WithStaticFields method creates a child logger with given static fields
+// merged into its parent static fields. Static fields gets logged with each
+// log entry at lower precedence, per-call fields and context values of the
+// same key override them. Typically used to tag logs per deployment.
+//
+// It can be configured via config too.
+//
+// 	For e.g.:
+// 		log {
+// 		  fields {
+// 		    env = "prod"
+// 		    service = "api"
+// 		    version = "1.2.3"
+// 		  }
+// 		}
+func (l *Logger) WithStaticFields(fields Fields) *Logger {
+	nl := l.New(nil)
+	nl.static = make(Fields, len(l.static)+len(fields))
+	for k, v := range l.static {
+		nl.static[k] = v
+	}
+	for k, v := range fields {
+		nl.static[k] = v
+	}
+	return nl
+}
+
+// Name method returns the component name of the logger.
+func (l *Logger) Name() string {
+	return l.name
+}
+
 // AddContext method to add context values into current logger.
 func (l *Logger) AddContext(fields Fields) {
 	for k, v := range fields {
@@ -213,19 +413,19 @@ func (l *Logger) AddHook(name string, hook HookFunc) error {
 
 // Level method returns currently enabled logging level.
 func (l *Logger) Level() string {
-	return levelToLevelName[l.level]
+	return levelToLevelName[l.currentLevel()]
 }
 
 // SetLevel method sets the given logging level for the logger.
-// For e.g.: INFO, WARN, DEBUG, etc. Case-insensitive.
+// For e.g.: INFO, WARN, DEBUG, etc. Case-insensitive. It also accepts
+// synonyms/abbreviations (warning, wrn, err, etc.) and numeric levels
+// (0=FATAL ... 6=TRACE).
 func (l *Logger) SetLevel(level string) error {
-	l.m.Lock()
-	defer l.m.Unlock()
 	levelFlag := levelByName(level)
 	if levelFlag == LevelUnknown {
-		return fmt.Errorf("log: unknown log level '%s'", level)
+		return unknownLevelError(level)
 	}
-	l.level = levelFlag
+	atomic.StoreUint32(l.level, uint32(levelFlag))
 	return nil
 }
 
@@ -241,15 +441,16 @@ func (l *Logger) SetPattern(pattern string) error {
 
 // SetReceiver method sets the given receiver into logger instance.
 func (l *Logger) SetReceiver(receiver Receiver) error {
-	l.m.Lock()
-	defer l.m.Unlock()
-
 	if receiver == nil {
 		return ErrLogReceiverIsNil
 	}
 
+	// initialized outside the lock, receiver init may log
+	err := receiver.Init(l.cfg)
+	l.m.Lock()
 	l.receiver = receiver
-	return l.receiver.Init(l.cfg)
+	l.m.Unlock()
+	return err
 }
 
 // SetWriter method sets the given writer into logger instance.
@@ -259,6 +460,131 @@ func (l *Logger) SetWriter(w io.Writer) {
 	l.receiver.SetWriter(w)
 }
 
+// SetFormatter method sets the given log entry formatter into log receiver
+// at runtime. For e.g.: switch to JSON when running in a container.
+//
+// 	For e.g.:
+// 		err := logger.SetFormatter(log.JSONFormatter)
+func (l *Logger) SetFormatter(fn Formatter) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if l.receiver == nil {
+		return ErrLogReceiverIsNil
+	}
+	fs, ok := l.receiver.(FormatterSetter)
+	if !ok {
+		return ErrFormatterNotSupported
+	}
+	fs.SetFormatter(fn)
+	return nil
+}
+
+// Healthy method reports the health of log receiver. It returns false and
+// last write error if the log receiver is unhealthy otherwise true.
+// It could be used for application health check endpoint (e.g. `/healthz`).
+func (l *Logger) Healthy() (bool, error) {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	if l.receiver == nil {
+		return false, ErrLogReceiverIsNil
+	}
+	if hc, ok := l.receiver.(HealthChecker); ok {
+		return hc.Healthy()
+	}
+	return true, nil
+}
+
+// RotateNow method rotates the log file of receiver and category receivers
+// immediately regardless of rotation policy, e.g. from the signal handler
+// wired by application. It returns `ErrRotationNotSupported` if receiver
+// doesn't implement `log.Rotator`. Refer to `FileReceiver.RotateNow`.
+func (l *Logger) RotateNow() error {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	if l.receiver == nil {
+		return ErrLogReceiverIsNil
+	}
+	if l.async != nil {
+		l.async.flush()
+	}
+	r, ok := l.receiver.(Rotator)
+	if !ok {
+		return ErrRotationNotSupported
+	}
+	err := r.RotateNow()
+	for _, cr := range l.categories {
+		if cr == l.receiver {
+			continue
+		}
+		if r, ok := cr.(Rotator); ok {
+			if rerr := r.RotateNow(); err == nil {
+				err = rerr
+			}
+		}
+	}
+	return err
+}
+
+// Stats method returns the point-in-time copy of log receiver counters,
+// summed up with category receivers that implement `log.StatsReporter`.
+// Dropped count of async logging is included. It's safe to call while
+// logging is in progress, returned value is not updated afterwards.
+func (l *Logger) Stats() ReceiverStats {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	var stats ReceiverStats
+	if r, ok := l.receiver.(StatsReporter); ok {
+		stats.add(r.Stats())
+	}
+	for _, cr := range l.categories {
+		if cr == l.receiver {
+			continue
+		}
+		if r, ok := cr.(StatsReporter); ok {
+			stats.add(r.Stats())
+		}
+	}
+	if l.async != nil {
+		stats.Dropped += l.async.stats.Dropped()
+		stats.Panics += l.async.stats.Panics()
+	}
+	return stats
+}
+
+// Close method closes the log receiver if it implements `log.Closer`,
+// including category receivers, and stops the level file watch. Queued log
+// entries of async logging are written before close. It's safe to call
+// multiple times, logging after close doesn't panic.
+func (l *Logger) Close() error {
+	l.m.Lock()
+	defer l.m.Unlock()
+	if l.receiver == nil {
+		return ErrLogReceiverIsNil
+	}
+	if l.stopLevelWatch != nil {
+		close(l.stopLevelWatch)
+		l.stopLevelWatch = nil
+	}
+	if l.async != nil {
+		l.async.close()
+	}
+	closeReceivers(l.categories, l.receiver)
+	if c, ok := l.receiver.(Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Closed method returns true if the log receiver is closed otherwise false.
+func (l *Logger) Closed() bool {
+	l.m.RLock()
+	defer l.m.RUnlock()
+	if c, ok := l.receiver.(Closer); ok {
+		return c.Closed()
+	}
+	return false
+}
+
 // ToGoLogger method wraps the current log writer into Go Logger instance.
 func (l *Logger) ToGoLogger() *slog.Logger {
 	return slog.New(l.receiver.Writer(), "", slog.LstdFlags)
@@ -268,94 +594,82 @@ func (l *Logger) ToGoLogger() *slog.Logger {
 // Logger logging methods
 //_______________________________________
 
-// Error logs message as `ERROR`. Arguments handled in the mananer of `fmt.Print`.
-func (l *Logger) Error(v ...interface{}) {
-	if l.level >= LevelError {
+// Log logs message with given level, for e.g. level chosen at runtime from
+// HTTP response status. Arguments handled in the mananer of `fmt.Print`.
+// Level `LevelFatal` and `LevelPanic` behave as method `Fatal` and `Panic`.
+//
+// 	For e.g.:
+// 		lvl := log.LevelWarn
+// 		if status >= http.StatusInternalServerError {
+// 			lvl = log.LevelError
+// 		}
+// 		logger.Log(lvl, "response status: ", status)
+func (l *Logger) Log(lvl LogLevel, v ...interface{}) {
+	if l.Enabled(lvl) {
 		e := acquireEntry(l)
-		e.Error(v...)
+		e.Log(lvl, v...)
 		releaseEntry(e)
 	}
 }
 
-// Errorf logs message as `ERROR`. Arguments handled in the mananer of `fmt.Printf`.
-func (l *Logger) Errorf(format string, v ...interface{}) {
-	if l.level >= LevelError {
+// Logf logs message with given level. Arguments handled in the mananer of
+// `fmt.Printf`. Refer to method `Log`.
+func (l *Logger) Logf(lvl LogLevel, format string, v ...interface{}) {
+	if l.Enabled(lvl) {
 		e := acquireEntry(l)
-		e.Errorf(format, v...)
+		e.Logf(lvl, format, v...)
 		releaseEntry(e)
 	}
 }
 
+// Error logs message as `ERROR`. Arguments handled in the mananer of `fmt.Print`.
+func (l *Logger) Error(v ...interface{}) {
+	l.Log(LevelError, v...)
+}
+
+// Errorf logs message as `ERROR`. Arguments handled in the mananer of `fmt.Printf`.
+func (l *Logger) Errorf(format string, v ...interface{}) {
+	l.Logf(LevelError, format, v...)
+}
+
 // Warn logs message as `WARN`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Warn(v ...interface{}) {
-	if l.level >= LevelWarn {
-		e := acquireEntry(l)
-		e.Warn(v...)
-		releaseEntry(e)
-	}
+	l.Log(LevelWarn, v...)
 }
 
 // Warnf logs message as `WARN`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Warnf(format string, v ...interface{}) {
-	if l.level >= LevelWarn {
-		e := acquireEntry(l)
-		e.Warnf(format, v...)
-		releaseEntry(e)
-	}
+	l.Logf(LevelWarn, format, v...)
 }
 
 // Info logs message as `INFO`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Info(v ...interface{}) {
-	if l.level >= LevelInfo {
-		e := acquireEntry(l)
-		e.Info(v...)
-		releaseEntry(e)
-	}
+	l.Log(LevelInfo, v...)
 }
 
 // Infof logs message as `INFO`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Infof(format string, v ...interface{}) {
-	if l.level >= LevelInfo {
-		e := acquireEntry(l)
-		e.Infof(format, v...)
-		releaseEntry(e)
-	}
+	l.Logf(LevelInfo, format, v...)
 }
 
 // Debug logs message as `DEBUG`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Debug(v ...interface{}) {
-	if l.level >= LevelDebug {
-		e := acquireEntry(l)
-		e.Debug(v...)
-		releaseEntry(e)
-	}
+	l.Log(LevelDebug, v...)
 }
 
 // Debugf logs message as `DEBUG`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Debugf(format string, v ...interface{}) {
-	if l.level >= LevelDebug {
-		e := acquireEntry(l)
-		e.Debugf(format, v...)
-		releaseEntry(e)
-	}
+	l.Logf(LevelDebug, format, v...)
 }
 
 // Trace logs message as `TRACE`. Arguments handled in the mananer of `fmt.Print`.
 func (l *Logger) Trace(v ...interface{}) {
-	if l.level >= LevelTrace {
-		e := acquireEntry(l)
-		e.Trace(v...)
-		releaseEntry(e)
-	}
+	l.Log(LevelTrace, v...)
 }
 
 // Tracef logs message as `TRACE`. Arguments handled in the mananer of `fmt.Printf`.
 func (l *Logger) Tracef(format string, v ...interface{}) {
-	if l.level >= LevelTrace {
-		e := acquireEntry(l)
-		e.Tracef(format, v...)
-		releaseEntry(e)
-	}
+	l.Logf(LevelTrace, format, v...)
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
@@ -448,50 +762,98 @@ func (l *Logger) Panicln(v ...interface{}) {
 // Logger level methods
 //___________________________________
 
+// Enabled method returns true if the log entry of given level would be
+// written as per logger level and receiver minimum level otherwise false.
+// It's cheap, use it to skip building the expensive log arguments.
+//
+// 	For e.g.:
+// 		if logger.Enabled(log.LevelDebug) {
+// 			logger.Debug(dumpState())
+// 		}
+func (l *Logger) Enabled(lvl LogLevel) bool {
+	if l.currentLevel() < lvl {
+		return false
+	}
+	l.m.RLock()
+	le, ok := l.receiver.(LevelEnabler)
+	l.m.RUnlock()
+	return !ok || le.Enabled(lvl)
+}
+
 // IsLevelInfo method returns true if log level is INFO otherwise false.
 func (l *Logger) IsLevelInfo() bool {
-	return l.level == LevelInfo
+	return l.currentLevel() == LevelInfo
 }
 
 // IsLevelError method returns true if log level is ERROR otherwise false.
 func (l *Logger) IsLevelError() bool {
-	return l.level == LevelError
+	return l.currentLevel() == LevelError
 }
 
 // IsLevelWarn method returns true if log level is WARN otherwise false.
 func (l *Logger) IsLevelWarn() bool {
-	return l.level == LevelWarn
+	return l.currentLevel() == LevelWarn
 }
 
 // IsLevelDebug method returns true if log level is DEBUG otherwise false.
 func (l *Logger) IsLevelDebug() bool {
-	return l.level == LevelDebug
+	return l.currentLevel() == LevelDebug
 }
 
 // IsLevelTrace method returns true if log level is TRACE otherwise false.
 func (l *Logger) IsLevelTrace() bool {
-	return l.level == LevelTrace
+	return l.currentLevel() == LevelTrace
 }
 
 // IsLevelFatal method returns true if log level is FATAL otherwise false.
 func (l *Logger) IsLevelFatal() bool {
-	return l.level == LevelFatal
+	return l.currentLevel() == LevelFatal
 }
 
 // IsLevelPanic method returns true if log level is PANIC otherwise false.
 func (l *Logger) IsLevelPanic() bool {
-	return l.level == LevelPanic
+	return l.currentLevel() == LevelPanic
 }
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Unexported methods
 //___________________________________
 
+// initStaticFields method reads the static fields from config `log.fields`.
+func (l *Logger) initStaticFields() {
+	keys := l.cfg.KeysByPath("log.fields")
+	if len(keys) == 0 {
+		return
+	}
+	l.static = make(Fields, len(keys))
+	for _, k := range keys {
+		if v, found := l.cfg.Get("log.fields." + k); found {
+			l.static[k] = v
+		}
+	}
+}
+
+// currentLevel method returns the logger level, it's safe to call while
+// level is being changed, e.g. by level file watcher.
+func (l *Logger) currentLevel() LogLevel {
+	return LogLevel(atomic.LoadUint32(l.level))
+}
+
 func (l *Logger) output(e *Entry) {
 	if l.receiver.IsCallerInfo() {
-		e.File, e.Line = fetchCallerInfo()
+		e.File, e.Line, e.Func = fetchCallerInfo()
+	}
+
+	switch {
+	case l.async == nil:
+		l.receiver.Log(e)
+	case e.Level <= LevelPanic:
+		// drain the queued entries before exit or panic
+		l.async.flush()
+		l.receiver.Log(e)
+	case !l.async.write(l.receiver, e):
+		l.receiver.Log(e)
 	}
-	l.receiver.Log(e)
 
 	// Execute logger hooks
 	go l.executeHooks(*e)
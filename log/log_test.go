@@ -5,12 +5,18 @@
 package log
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"aahframe.work/config"
+	"aahframe.work/essentials"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -59,7 +65,7 @@ func TestMisc(t *testing.T) {
 	assert.Equal(t, "log: receiver is nil", err.Error())
 
 	err = logger.SetLevel("MYLEVEL")
-	assert.Equal(t, "log: unknown log level 'MYLEVEL'", err.Error())
+	assert.Equal(t, "log: unknown log level 'MYLEVEL', valid values are FATAL, PANIC, ERROR, WARN, INFO, DEBUG, TRACE (or 0-6)", err.Error())
 
 	logger, err = New(nil)
 	assert.Nil(t, logger)
@@ -76,6 +82,360 @@ func TestMisc(t *testing.T) {
 	stdLogger.Print("This is aah logger binds go logger")
 }
 
+func TestLogLevelByName(t *testing.T) {
+	testcases := []struct {
+		name  string
+		level LogLevel
+	}{
+		{name: "ERROR", level: LevelError},
+		{name: "warn", level: LevelWarn},
+		{name: "Warning", level: LevelWarn},
+		{name: "wrn", level: LevelWarn},
+		{name: "err", level: LevelError},
+		{name: "fatal", level: LevelFatal},
+		{name: " dbg ", level: LevelDebug},
+		{name: "0", level: LevelFatal},
+		{name: "3", level: LevelWarn},
+		{name: "6", level: LevelTrace},
+		{name: "7", level: LevelUnknown},
+		{name: "-1", level: LevelUnknown},
+		{name: "verbose", level: LevelUnknown},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, tc.level, levelByName(tc.name), tc.name)
+	}
+
+	cfg, _ := config.ParseString(`log {
+    level = "warning"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	assert.Equal(t, "WARN", logger.Level())
+
+	assert.Nil(t, logger.SetLevel("4"))
+	assert.Equal(t, "INFO", logger.Level())
+}
+
+func TestLogValidatePattern(t *testing.T) {
+	testcases := []struct {
+		label   string
+		pattern string
+		err     string
+	}{
+		{label: "default pattern", pattern: DefaultPattern},
+		{label: "utctime pattern", pattern: "%utctime:2006-01-02T15:04:05Z07:00 %level:-5 %message %fields"},
+		{label: "empty pattern", pattern: "  ", err: "log: pattern is empty"},
+		{label: "typo flag", pattern: "%time:2006-01-02 %levl %message",
+			err: "log: unknown flag '%levl', did you mean '%level'"},
+		{label: "typo flag with format", pattern: "%time:2006-01-02 %level:-5 %mesage",
+			err: "log: unknown flag '%mesage', did you mean '%message'"},
+		{label: "unknown flag", pattern: "%time:2006-01-02 %level %myfile %message",
+			err: "log: unknown flag '%myfile'"},
+		{label: "no message flag", pattern: "%time:2006-01-02 %level:-5 %line",
+			err: "log: no '%message' flag present in pattern '%time:2006-01-02 %level:-5 %line'"},
+		{label: "time without layout", pattern: "%time %level %message",
+			err: "log: flag '%time' requires time layout, e.g. '%time:2006-01-02 15:04:05.000'"},
+		{label: "malformed time layout", pattern: "%utctime:YYYY-MM-DD %level %message",
+			err: "log: malformed time layout 'YYYY-MM-DD' for flag '%utctime'"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			err := ValidatePattern(tc.pattern)
+			if len(tc.err) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			assert.NotNil(t, err)
+			assert.Equal(t, tc.err, err.Error())
+		})
+	}
+
+	cfg, _ := config.ParseString(`log {
+    pattern = "%time:2006-01-02 %levl %message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: unknown flag '%levl', did you mean '%level'", err.Error())
+}
+
+func TestLogRecoverAndLog(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    format = "json"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	// swallow
+	func() {
+		defer RecoverAndLog(logger, Fields{"handler": "users"}, false)
+		panic("something went wrong")
+	}()
+
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "ERROR", m["level"])
+	fields := m["fields"].(map[string]interface{})
+	assert.Equal(t, "users", fields["handler"])
+	assert.Equal(t, "something went wrong", fields[PanicFieldKey])
+	msg := m["message"].(string)
+	assert.True(t, strings.HasPrefix(msg, "panic recovered: something went wrong\ngoroutine "))
+	assert.True(t, strings.Contains(msg, "TestLogRecoverAndLog"))
+
+	// rethrow
+	buf.Reset()
+	r := func() (r interface{}) {
+		defer func() { r = recover() }()
+		defer RecoverAndLog(logger, nil, true)
+		panic(errors.New("rethrow me"))
+	}()
+	assert.Equal(t, "rethrow me", r.(error).Error())
+	assert.True(t, strings.Contains(buf.String(), `"panic":"rethrow me"`))
+
+	// no panic
+	buf.Reset()
+	func() {
+		defer RecoverAndLog(logger, nil, false)
+	}()
+	assert.Equal(t, "", buf.String())
+}
+
+func TestLogSetClock(t *testing.T) {
+	frozen := time.Date(2018, 6, 15, 10, 30, 45, 123000000, time.FixedZone("IST", 19800))
+	SetClock(func() time.Time { return frozen })
+	defer SetClock(nil)
+
+	cfg, _ := config.ParseString(`log {
+    pattern = "%time:2006-01-02 15:04:05.000 %utctime:15:04:05 %level:-5 %message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+	logger.Info("frozen clock")
+	assert.Equal(t, "2018-06-15 10:30:45.123 05:00:45 INFO  frozen clock \n", buf.String())
+
+	cfg, _ = config.ParseString(`log {
+    format = "json"
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, err)
+	buf.Reset()
+	logger.SetWriter(buf)
+	logger.Info("frozen clock")
+	assert.True(t, strings.Contains(buf.String(), `"timestamp":"2018-06-15T10:30:45+05:30"`), buf.String())
+
+	// reset to default clock
+	SetClock(nil)
+	assert.True(t, time.Since(now()) < time.Minute)
+}
+
+func TestLogLazyValue(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    level = "info"
+    pattern = "%level:-5 %message %fields"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	calls := 0
+	expensive := Lazy(func() interface{} {
+		calls++
+		return "large-struct"
+	})
+
+	// below level, thunks are not evaluated
+	logger.Debug("payload: ", expensive)
+	logger.Debugf("payload: %v", expensive)
+	logger.WithField("payload", expensive).Debug("request")
+	logger.WithField("payload", func() interface{} { calls++; return 0 }).Trace("request")
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, "", buf.String())
+
+	// written entry, thunks are evaluated
+	logger.Info("payload: ", expensive)
+	logger.Infof("payload: %v, count: %d", expensive, func() interface{} { return 2 })
+	logger.WithField("payload", expensive).Info("request")
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, "INFO  payload: large-struct \n"+
+		"INFO  payload: large-struct, count: 2 \n"+
+		"INFO  request fields[payload: large-struct] \n", buf.String())
+
+	// nil thunk
+	buf.Reset()
+	var nilLazy Lazy
+	logger.Info("value: ", nilLazy)
+	assert.Equal(t, "INFO  value: <nil> \n", buf.String())
+}
+
+func TestLogWithName(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level:-5 %component %message %fields"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	dbLog := logger.WithName("db")
+	poolLog := dbLog.WithName("pool")
+	assert.Equal(t, "", logger.Name())
+	assert.Equal(t, "db", dbLog.Name())
+	assert.Equal(t, "db.pool", poolLog.Name())
+	assert.Equal(t, "db.pool", poolLog.WithName("").Name())
+
+	poolLog.Info("connection acquired")
+	poolLog.WithField("size", 10).Warn("pool exhausted")
+	dbLog.Info("migrated")
+	logger.Info("no component")
+	assert.Equal(t, "INFO  db.pool connection acquired \n"+
+		"WARN  db.pool pool exhausted fields[size: 10] \n"+
+		"INFO  db migrated \n"+
+		"INFO  no component \n", buf.String())
+
+	// context of child doesn't leak to parent
+	poolLog.AddContext(Fields{"pool": "primary"})
+	buf.Reset()
+	dbLog.Info("migrated")
+	assert.Equal(t, "INFO  db migrated \n", buf.String())
+
+	// JSON output
+	cfg, _ = config.ParseString(`log {
+    format = "json"
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, err)
+	buf.Reset()
+	logger.SetWriter(buf)
+	logger.WithName("db").WithName("pool").Info("connection acquired")
+
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "db.pool", m["component"])
+	assert.Equal(t, "connection acquired", m["message"])
+
+	buf.Reset()
+	logger.Info("no component")
+	assert.False(t, strings.Contains(buf.String(), `"component"`))
+}
+
+func TestLogWithStaticFields(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level:-5 %message %fields"
+    fields {
+      env = "prod"
+      service = "api"
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	logger.Info("from config")
+	assert.True(t, strings.HasPrefix(buf.String(), "INFO  from config fields["))
+	assert.True(t, strings.Contains(buf.String(), "env: prod"))
+	assert.True(t, strings.Contains(buf.String(), "service: api"))
+
+	// per-call fields override static fields
+	buf.Reset()
+	vlog := logger.WithStaticFields(Fields{"version": "1.2.3"})
+	vlog.WithFields(Fields{"env": "staging"}).Info("overridden")
+	assert.True(t, strings.Contains(buf.String(), "env: staging"))
+	assert.False(t, strings.Contains(buf.String(), "env: prod"))
+	assert.True(t, strings.Contains(buf.String(), "service: api"))
+	assert.True(t, strings.Contains(buf.String(), "version: 1.2.3"))
+
+	buf.Reset()
+	vlog.Info("merged")
+	assert.True(t, strings.Contains(buf.String(), "env: prod"))
+	assert.True(t, strings.Contains(buf.String(), "version: 1.2.3"))
+
+	// static fields of child don't leak to parent
+	buf.Reset()
+	logger.Info("parent")
+	assert.False(t, strings.Contains(buf.String(), "version"))
+
+	// JSON output
+	cfg, _ = config.ParseString(`log {
+    format = "json"
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, err)
+	buf.Reset()
+	logger.SetWriter(buf)
+	logger.WithStaticFields(Fields{"env": "prod"}).WithField("service", "api").Info("json entry")
+
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &m))
+	fields, _ := m["fields"].(map[string]interface{})
+	assert.Equal(t, "prod", fields["env"])
+	assert.Equal(t, "api", fields["service"])
+}
+
+func TestLogCallerFunc(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level:-5 %func %message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	logger.Info("direct call")
+	assert.Equal(t, "INFO  TestLogCallerFunc direct call \n", buf.String())
+
+	// through entry and child logger
+	buf.Reset()
+	logger.WithField("key", "value").Warn("entry call")
+	logger.WithName("db").Error("child call")
+	assert.Equal(t, "WARN  TestLogCallerFunc entry call \nERROR TestLogCallerFunc child call \n", buf.String())
+
+	// each call site reports its own function
+	buf.Reset()
+	logFromHelper(logger)
+	func() {
+		logger.Info("closure call")
+	}()
+	assert.Equal(t, "INFO  logFromHelper helper call \nINFO  TestLogCallerFunc.func1 closure call \n", buf.String())
+
+	// JSON field
+	buf.Reset()
+	logger.receiver.(FormatterSetter).SetFormatter(JSONFormatter)
+	logger.Info("json call")
+	var m map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "TestLogCallerFunc", m["func"])
+	assert.Equal(t, "json call", m["message"])
+}
+
+func logFromHelper(logger *Logger) {
+	logger.Info("helper call")
+}
+
+func TestLogShortFuncName(t *testing.T) {
+	testcases := []struct {
+		name, expected string
+	}{
+		{"aahframe.work/ahttp.(*Request).Locale", "(*Request).Locale"},
+		{"aahframe.work/log.TestLogCallerFunc.func1", "TestLogCallerFunc.func1"},
+		{"main.main", "main"},
+		{"gopkg.in/yaml%2ev2.(*Decoder).Decode", "(*Decoder).Decode"},
+		{"github.com/user/pkg.value.Method", "value.Method"},
+		{"github.com/user/pkg.v2.Method", "v2.Method"},
+		{"Func", "Func"},
+	}
+
+	for _, tc := range testcases {
+		assert.Equal(t, tc.expected, shortFuncName(tc.name), tc.name)
+	}
+}
+
 func testPanic(logger *Logger, method, msg string) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -117,3 +477,202 @@ func cleaupFiles(match string) {
 		}
 	}
 }
+
+func TestLogRuntimeLevel(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    level = "info"
+    pattern = "%level:-5 %message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	levelByStatus := func(status int) LogLevel {
+		switch {
+		case status >= 500:
+			return LevelError
+		case status >= 400:
+			return LevelWarn
+		case status >= 300:
+			return LevelDebug
+		}
+		return LevelInfo
+	}
+
+	for _, status := range []int{200, 302, 404, 503} {
+		logger.Log(levelByStatus(status), "response status: ", status)
+	}
+	logger.WithField("status", 418).Logf(levelByStatus(418), "teapot %s", "brewed")
+	assert.Equal(t, "INFO  response status: 200 \n"+
+		"WARN  response status: 404 \n"+
+		"ERROR response status: 503 \n"+
+		"WARN  teapot brewed \n", buf.String())
+
+	// fatal exits and panic panics as its level methods
+	exitCode := -1
+	exit = func(code int) { exitCode = code }
+	defer func() { exit = os.Exit }()
+	buf.Reset()
+	logger.Log(LevelFatal, "fatal at runtime")
+	assert.Equal(t, 1, exitCode)
+	assert.Equal(t, "FATAL fatal at runtime \n", buf.String())
+
+	buf.Reset()
+	func() {
+		defer func() {
+			assert.NotNil(t, recover())
+		}()
+		logger.Logf(LevelPanic, "panic at %s", "runtime")
+	}()
+	assert.Equal(t, "PANIC panic at runtime \n", buf.String())
+}
+
+func TestLogTextFormatterFastPath(t *testing.T) {
+	testcases := []struct {
+		pattern string
+		fast    bool
+	}{
+		{pattern: DefaultPattern, fast: true},
+		{pattern: "%utctime:2006-01-02T15:04:05Z07:00 %level %message", fast: true},
+		{pattern: "%time:2006-01-02 15:04:05.000 %appname %insname %reqid %principal %level:-5 %shortfile %line %custom:- %message", fast: true},
+		{pattern: "%time:15:04:05 %component %level:-5 %longfile %func %custom %message", fast: true},
+		{pattern: "%time:2006-01-02 15:04:05.000 %level:-5 %message %fields", fast: false},
+		{pattern: "%level:5 %message", fast: false},
+		{pattern: "%hostname %pid %level %line:4 %message", fast: false},
+	}
+
+	newEntry := func() *Entry {
+		return &Entry{
+			Time:      time.Date(2018, 5, 14, 10, 24, 11, 123456789, time.FixedZone("PDT", -7*60*60)),
+			Level:     LevelWarn,
+			Message:   "Yes, I would love to see",
+			File:      "/a/b/c/formatter_test.go",
+			Line:      29,
+			Func:      "(*Request).Locale",
+			AppName:   "testapp",
+			RequestID: "40139CA6368607085BF6",
+			Component: "db.pool",
+		}
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.pattern, func(t *testing.T) {
+			flags, err := ess.ParseFmtFlag(tc.pattern, FmtFlags)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.fast, isFastTextPattern(flags))
+			assert.Equal(t, string(formatText(flags, newEntry(), defaultTextFields)), string(textFormatter(flags, newEntry())))
+		})
+	}
+
+	flags, _ := ess.ParseFmtFlag(DefaultPattern, FmtFlags)
+	assert.Equal(t, "2018-05-14 10:24:11.123 WARN  Yes, I would love to see \n",
+		string(textFormatter(flags, newEntry())))
+}
+
+func TestLogTextFormatterLogfmt(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level %message %fields"
+    text {
+      fields = "logfmt"
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	logger.WithFields(Fields{
+		"user":  "jeeva",
+		"query": "a=b&c=d",
+		"title": "hello world",
+		"quote": `say "hi"`,
+		"empty": "",
+		"count": 2,
+		"nl":    "line1\nline2",
+	}).Info("request")
+	assert.Equal(t, `INFO request count=2 empty="" nl="line1\nline2" query="a=b&c=d" quote="say \"hi\"" `+
+		`title="hello world" user=jeeva `+"\n", buf.String())
+
+	// no fields
+	buf.Reset()
+	logger.Info("no fields")
+	assert.Equal(t, "INFO no fields \n", buf.String())
+
+	// custom separator is not allowed for logfmt
+	cfg, _ = config.ParseString(`log {
+    pattern = "%level %message %fields"
+    text {
+      fields = "logfmt"
+      field_separator = " | "
+    }
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: text field separator is not supported for logfmt fields mode", err.Error())
+
+	// plain with custom separator
+	cfg, _ = config.ParseString(`log {
+    pattern = "%level %message %fields"
+    text {
+      field_separator = "; "
+    }
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, err)
+	buf.Reset()
+	logger.SetWriter(buf)
+	logger.WithFields(Fields{"a": 1}).Info("plain")
+	assert.Equal(t, "INFO plain fields[a: 1] \n", buf.String())
+
+	cfg, _ = config.ParseString(`log {
+    text {
+      fields = "csv"
+    }
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: unsupported text fields mode 'csv', valid values are plain, logfmt", err.Error())
+}
+
+func BenchmarkTextFormatterDefaultPattern(b *testing.B) {
+	benchmarkTextFormatter(b, textFormatter)
+}
+
+func BenchmarkTextFormatterGenericPath(b *testing.B) {
+	benchmarkTextFormatter(b, func(flags []ess.FmtFlagPart, e *Entry) []byte {
+		return formatText(flags, e, defaultTextFields)
+	})
+}
+
+func benchmarkTextFormatter(b *testing.B, format Formatter) {
+	flags, _ := ess.ParseFmtFlag(DefaultPattern, FmtFlags)
+	e := &Entry{Time: time.Now(), Level: LevelInfo, Message: "Yes, I would love to see"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = format(flags, e)
+	}
+}
+
+func BenchmarkLogDisabledLevel(b *testing.B) {
+	cfg, _ := config.ParseString(`log { level = "info" }`)
+	logger, _ := New(cfg)
+	logger.SetWriter(ioutil.Discard)
+	benchmarkLogDisabled(b, logger)
+}
+
+func BenchmarkLogReceiverDisabledLevel(b *testing.B) {
+	cfg, _ := config.ParseString(`log { level = "trace", min_level = "warn" }`)
+	logger, _ := New(cfg)
+	logger.SetWriter(ioutil.Discard)
+	benchmarkLogDisabled(b, logger)
+}
+
+func benchmarkLogDisabled(b *testing.B, logger *Logger) {
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		logger.Debugf("request %s completed in %d ms", "GET /users", 12)
+	}
+}
@@ -0,0 +1,437 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+)
+
+const (
+	defaultNetworkBufferSize = 1000
+	networkDialTimeout       = 5 * time.Second
+)
+
+var (
+	// ErrNetworkDisconnected returned by method `NetworkReceiver.Healthy`
+	// while network receiver is not connected to log collector.
+	ErrNetworkDisconnected = errors.New("log: network receiver is disconnected")
+
+	_ Receiver        = (*NetworkReceiver)(nil)
+	_ HealthChecker   = (*NetworkReceiver)(nil)
+	_ Closer          = (*NetworkReceiver)(nil)
+	_ FormatterSetter = (*NetworkReceiver)(nil)
+	_ StatsReporter   = (*NetworkReceiver)(nil)
+	_ LevelEnabler    = (*NetworkReceiver)(nil)
+)
+
+// NetworkReceiver writes the log entry into log collector over TCP or UDP.
+//
+// Log collector doesn't have to be up on application start, receiver
+// initializes successfully and connects in the background with exponential
+// backoff. Same happens when the write fails or doesn't complete within
+// `log.network.write_timeout` (default 5s) later on. Log entries are
+// buffered meanwhile up to `log.network.buffer_size` (default 1000) entries,
+// oldest entries are dropped beyond that. Buffer is flushed once connected.
+// Disconnected state is reported via method `Healthy`.
+//
+// 	For e.g.:
+// 		log {
+// 		  receiver = "network"
+// 		  network {
+// 		    protocol = "tcp"
+// 		    address = "logs.example.com:5170"
+// 		    buffer_size = 1000
+// 		    min_backoff = "100ms"
+// 		    max_backoff = "30s"
+// 		    write_timeout = "5s"
+// 		  }
+// 		}
+type NetworkReceiver struct {
+	protocol     string
+	address      string
+	out          io.Writer
+	formatter    string
+	format       Formatter
+	lineEnding   []byte
+	maxMsgLen    int
+	minLevel     LogLevel
+	flags        []ess.FmtFlagPart
+	isCallerInfo uint32
+	stats        *receiverStats
+	mu           sync.Mutex
+	isClosed     bool
+	lastErr      error
+	pending      [][]byte
+	bufferSize   int
+	minBackoff   time.Duration
+	maxBackoff   time.Duration
+	writeTimeout time.Duration
+	reconnecting bool
+	stop         chan struct{}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// NetworkReceiver methods
+//___________________________________
+
+// Init method initializes the network receiver. It doesn't wait for the
+// connection, receiver connects and keeps reconnecting in the background.
+func (n *NetworkReceiver) Init(cfg *config.Config) error {
+	n.protocol = cfg.StringDefault("log.network.protocol", "tcp")
+	switch n.protocol {
+	case "tcp", "udp":
+	default:
+		return fmt.Errorf("log: unsupported network protocol '%s'", n.protocol)
+	}
+
+	n.address = cfg.StringDefault("log.network.address", "")
+	if ess.IsStrEmpty(n.address) {
+		return errors.New("log: network address is required")
+	}
+
+	n.bufferSize = cfg.IntDefault("log.network.buffer_size", defaultNetworkBufferSize)
+	if n.bufferSize <= 0 {
+		return fmt.Errorf("log: network buffer size '%d' must be greater than zero", n.bufferSize)
+	}
+
+	var err error
+	if n.minBackoff, err = time.ParseDuration(cfg.StringDefault("log.network.min_backoff", "100ms")); err != nil {
+		return fmt.Errorf("log: invalid network min backoff: %s", err)
+	}
+	if n.maxBackoff, err = time.ParseDuration(cfg.StringDefault("log.network.max_backoff", "30s")); err != nil {
+		return fmt.Errorf("log: invalid network max backoff: %s", err)
+	}
+	if n.minBackoff <= 0 || n.maxBackoff < n.minBackoff {
+		return fmt.Errorf("log: network backoff range '%s' to '%s' is invalid", n.minBackoff, n.maxBackoff)
+	}
+	if n.writeTimeout, err = time.ParseDuration(cfg.StringDefault("log.network.write_timeout", "5s")); err != nil {
+		return fmt.Errorf("log: invalid network write timeout: %s", err)
+	}
+	if n.writeTimeout <= 0 {
+		return fmt.Errorf("log: network write timeout '%s' must be greater than zero", n.writeTimeout)
+	}
+
+	n.formatter = cfg.StringDefault("log.format", "text")
+	switch n.formatter {
+	case textFmt:
+		format, err := textFormatterByConfig(cfg)
+		if err != nil {
+			return err
+		}
+		n.format = format
+	case jsonFmt, jsonlFmt:
+		n.formatter, n.format = jsonFmt, jsonFormatterByConfig(cfg)
+	default:
+		return fmt.Errorf("log: unsupported format '%s'", n.formatter)
+	}
+
+	if n.lineEnding, err = parseLineEnding(cfg); err != nil {
+		return err
+	}
+
+	if n.maxMsgLen, err = parseMaxMessageLen(cfg); err != nil {
+		return err
+	}
+
+	if n.minLevel, err = parseMinLevel(cfg); err != nil {
+		return err
+	}
+
+	n.stats = &receiverStats{}
+	n.stop = make(chan struct{})
+	n.mu = sync.Mutex{}
+
+	n.mu.Lock()
+	n.reconnect(0)
+	n.mu.Unlock()
+
+	return nil
+}
+
+// SetPattern method initializes the logger format pattern.
+func (n *NetworkReceiver) SetPattern(pattern string) error {
+	flags, err := ess.ParseFmtFlag(pattern, FmtFlags)
+	if err != nil {
+		return err
+	}
+	n.flags = flags
+	if n.formatter != jsonFmt {
+		setCallerInfo(&n.isCallerInfo, n.flags)
+	}
+	return nil
+}
+
+// SetWriter method sets the given writer into network receiver. Writer must be
+// safe for concurrent use, same as `net.Conn`.
+func (n *NetworkReceiver) SetWriter(w io.Writer) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.out = w
+}
+
+// SetFormatter method sets the given log entry formatter, it's safe to call
+// while logging is in progress.
+func (n *NetworkReceiver) SetFormatter(fn Formatter) {
+	if fn == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.format = fn
+	n.formatter = customFmt
+	setCallerInfo(&n.isCallerInfo, n.flags)
+}
+
+// IsCallerInfo method returns true if log receiver is configured with caller info
+// otherwise false.
+func (n *NetworkReceiver) IsCallerInfo() bool {
+	return atomic.LoadUint32(&n.isCallerInfo) == 1
+}
+
+// Log method writes the log entry into network connection, entry is buffered
+// while disconnected. Write timeout is treated as disconnect. Write happens
+// outside the lock, so slow log collector doesn't block the receiver.
+func (n *NetworkReceiver) Log(entry *Entry) {
+	if entry.Level > n.minLevel {
+		return
+	}
+
+	n.mu.Lock()
+	if n.isClosed {
+		n.lastErr = ErrWriterIsClosed
+		n.mu.Unlock()
+		return
+	}
+
+	if e, truncated := truncateEntry(entry, n.maxMsgLen); truncated {
+		entry = e
+		n.stats.addTruncated()
+	}
+
+	msg := terminate(n.format(n.flags, entry), n.lineEnding)
+	out := n.out
+	if out == nil {
+		n.buffer(msg)
+		n.mu.Unlock()
+		return
+	}
+	n.mu.Unlock()
+
+	for {
+		size, err := n.write(out, msg)
+		if err == nil {
+			n.stats.add(1, int64(size))
+			return
+		}
+
+		n.mu.Lock()
+		switch {
+		case n.isClosed:
+		case n.out == out:
+			n.disconnect(err)
+			n.buffer(msg)
+			n.reconnect(n.minBackoff)
+		case n.out == nil:
+			// disconnected by concurrent write, reconnect is in progress
+			n.buffer(msg)
+		default:
+			// reconnected meanwhile, retry with new connection
+			out = n.out
+			n.mu.Unlock()
+			continue
+		}
+		n.mu.Unlock()
+		return
+	}
+}
+
+// Enabled method returns true if the given level is within the network
+// receiver minimum level `log.min_level` otherwise false.
+func (n *NetworkReceiver) Enabled(lvl LogLevel) bool {
+	return lvl <= n.minLevel
+}
+
+// Writer method returns the current log writer, it's nil while disconnected.
+func (n *NetworkReceiver) Writer() io.Writer {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.out
+}
+
+// Healthy method returns false and error if the receiver is disconnected or
+// the last log write failed otherwise true. Disconnected error is
+// `ErrNetworkDisconnected`.
+func (n *NetworkReceiver) Healthy() (bool, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.out == nil && !n.isClosed {
+		return false, ErrNetworkDisconnected
+	}
+	return n.lastErr == nil, n.lastErr
+}
+
+// Stats method returns the point-in-time copy of network receiver counters,
+// dropped count is of the buffered entries while disconnected.
+func (n *NetworkReceiver) Stats() ReceiverStats {
+	return n.stats.snapshot()
+}
+
+// Close method stops the reconnection and closes the network connection.
+// Buffered log entries are discarded if not connected yet. It's idempotent,
+// log entries written after close are dropped and reported as
+// `ErrWriterIsClosed` via `Healthy`.
+func (n *NetworkReceiver) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.isClosed {
+		return nil
+	}
+	n.isClosed = true
+	close(n.stop)
+	n.pending = nil
+	var err error
+	if c, ok := n.out.(io.Closer); ok {
+		err = c.Close()
+	}
+	return err
+}
+
+// Closed method returns true if the network receiver is closed otherwise false.
+func (n *NetworkReceiver) Closed() bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.isClosed
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// NetworkReceiver Unexported methods
+//___________________________________
+
+// buffer method queues the formatted log entry while disconnected, oldest
+// entry is dropped when buffer is full.
+func (n *NetworkReceiver) buffer(msg []byte) {
+	if len(n.pending) >= n.bufferSize {
+		n.pending = n.pending[1:]
+		n.stats.addDropped()
+	}
+	n.pending = append(n.pending, msg)
+}
+
+// disconnect method closes the broken connection and records the error.
+func (n *NetworkReceiver) disconnect(err error) {
+	if c, ok := n.out.(io.Closer); ok {
+		ess.CloseQuietly(c)
+	}
+	n.out = nil
+	n.lastErr = err
+}
+
+// requeue method puts back the unwritten log entries ahead of the entries
+// buffered meanwhile, oldest entries are dropped beyond buffer size.
+func (n *NetworkReceiver) requeue(msgs [][]byte) {
+	n.pending = append(append(make([][]byte, 0, len(msgs)+len(n.pending)), msgs...), n.pending...)
+	for len(n.pending) > n.bufferSize {
+		n.pending = n.pending[1:]
+		n.stats.addDropped()
+	}
+}
+
+// write method writes the message into given writer, write deadline is set
+// if the writer supports it e.g. `net.Conn`.
+func (n *NetworkReceiver) write(w io.Writer, msg []byte) (int, error) {
+	if d, ok := w.(interface{ SetWriteDeadline(time.Time) error }); ok {
+		if err := d.SetWriteDeadline(time.Now().Add(n.writeTimeout)); err != nil {
+			return 0, err
+		}
+	}
+	return w.Write(msg)
+}
+
+// reconnect method starts the background reconnection after given delay,
+// if not running.
+func (n *NetworkReceiver) reconnect(delay time.Duration) {
+	if n.reconnecting || n.isClosed {
+		return
+	}
+	n.reconnecting = true
+	go n.reconnectLoop(delay)
+}
+
+// reconnectLoop method dials the log collector with exponential backoff
+// until connected or receiver is closed, then flushes the buffered entries.
+// Dial and flush happen outside the lock, so logging is not blocked.
+func (n *NetworkReceiver) reconnectLoop(delay time.Duration) {
+	for {
+		select {
+		case <-n.stop:
+			return
+		case <-time.After(delay):
+		}
+
+		conn, err := net.DialTimeout(n.protocol, n.address, networkDialTimeout)
+		if err == nil {
+			if err = n.flushPending(conn); err == nil {
+				return
+			}
+			ess.CloseQuietly(conn)
+		}
+
+		n.mu.Lock()
+		if n.isClosed {
+			n.mu.Unlock()
+			return
+		}
+		n.lastErr = err
+		n.mu.Unlock()
+
+		if delay *= 2; delay < n.minBackoff {
+			delay = n.minBackoff
+		} else if delay > n.maxBackoff {
+			delay = n.maxBackoff
+		}
+	}
+}
+
+// flushPending method writes the buffered log entries into given connection
+// and then sets it as receiver writer. Log entries keep getting buffered
+// until then, unwritten entries remain buffered on error.
+func (n *NetworkReceiver) flushPending(conn net.Conn) error {
+	for {
+		n.mu.Lock()
+		if n.isClosed {
+			n.mu.Unlock()
+			ess.CloseQuietly(conn)
+			return nil
+		}
+		pending := n.pending
+		n.pending = nil
+		if len(pending) == 0 {
+			n.out, n.lastErr, n.reconnecting = conn, nil, false
+			n.mu.Unlock()
+			return nil
+		}
+		n.mu.Unlock()
+
+		for i, msg := range pending {
+			size, err := n.write(conn, msg)
+			if err != nil {
+				n.mu.Lock()
+				n.requeue(pending[i:])
+				n.mu.Unlock()
+				return err
+			}
+			n.stats.add(1, int64(size))
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkLoggerConnectLater(t *testing.T) {
+	// reserve the address and release it, collector is not up yet
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	cfg, _ := config.ParseString(fmt.Sprintf(`log {
+    receiver = "network"
+    pattern = "%%level %%message"
+    network {
+      address = "%s"
+      buffer_size = 3
+      min_backoff = "10ms"
+      max_backoff = "50ms"
+    }
+  }`, addr))
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	defer func() { _ = logger.Close() }()
+
+	healthy, err := logger.Healthy()
+	assert.False(t, healthy)
+	assert.Equal(t, ErrNetworkDisconnected, err)
+
+	for i := 1; i <= 5; i++ {
+		logger.Infof("entry %d", i)
+	}
+	assert.Equal(t, int64(2), logger.Stats().Dropped)
+
+	// collector comes up, buffered entries are delivered
+	l, err = net.Listen("tcp", addr)
+	assert.Nil(t, err)
+	defer func() { _ = l.Close() }()
+
+	conn, err := l.Accept()
+	assert.Nil(t, err)
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	for _, expected := range []string{"INFO entry 3 \n", "INFO entry 4 \n", "INFO entry 5 \n"} {
+		line, err := r.ReadString('\n')
+		assert.Nil(t, err)
+		assert.Equal(t, expected, line)
+	}
+
+	// once connected, entries are written directly
+	logger.Info("entry 6")
+	line, err := r.ReadString('\n')
+	assert.Nil(t, err)
+	assert.Equal(t, "INFO entry 6 \n", line)
+
+	healthy, err = logger.Healthy()
+	assert.True(t, healthy)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(4), logger.Stats().Lines)
+}
+
+func TestNetworkLoggerWriteTimeout(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	cfg, _ := config.ParseString(fmt.Sprintf(`log {
+    receiver = "network"
+    pattern = "%%level %%message"
+    network {
+      address = "%s"
+      min_backoff = "10ms"
+      max_backoff = "50ms"
+      write_timeout = "50ms"
+    }
+  }`, addr))
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	defer func() { _ = logger.Close() }()
+
+	// collector doesn't read, write must not block beyond write timeout
+	client, server := net.Pipe()
+	defer func() { _ = server.Close() }()
+	logger.SetWriter(client)
+
+	done := make(chan struct{})
+	go func() {
+		logger.Info("entry 1")
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("network receiver write is blocked")
+	}
+
+	healthy, err := logger.Healthy()
+	assert.False(t, healthy)
+	assert.Equal(t, ErrNetworkDisconnected, err)
+	assert.Nil(t, logger.receiver.(*NetworkReceiver).Writer())
+	assert.Equal(t, int64(0), logger.Stats().Lines)
+}
+
+func TestNetworkLoggerWriteOutsideLock(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	addr := l.Addr().String()
+	_ = l.Close()
+
+	cfg, _ := config.ParseString(fmt.Sprintf(`log {
+    receiver = "network"
+    pattern = "%%level %%message"
+    network {
+      address = "%s"
+      min_backoff = "10ms"
+      max_backoff = "50ms"
+      write_timeout = "10s"
+    }
+  }`, addr))
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	defer func() { _ = logger.Close() }()
+
+	// collector doesn't read yet, receiver is not blocked by pending write
+	client, server := net.Pipe()
+	defer func() { _ = server.Close() }()
+	logger.SetWriter(client)
+	go logger.Info("entry 1")
+	time.Sleep(20 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		assert.Equal(t, client, logger.receiver.(*NetworkReceiver).Writer())
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("network receiver is locked during write")
+	}
+
+	line, err := bufio.NewReader(server).ReadString('\n')
+	assert.Nil(t, err)
+	assert.Equal(t, "INFO entry 1 \n", line)
+}
+
+func TestNetworkLoggerConfigError(t *testing.T) {
+	testcases := []struct {
+		label, cfg, err string
+	}{
+		{label: "no address", cfg: `network { }`, err: "log: network address is required"},
+		{label: "protocol", cfg: `network { protocol = "unix", address = "x" }`,
+			err: "log: unsupported network protocol 'unix'"},
+		{label: "buffer size", cfg: `network { address = "127.0.0.1:1", buffer_size = 0 }`,
+			err: "log: network buffer size '0' must be greater than zero"},
+		{label: "backoff", cfg: `network { address = "127.0.0.1:1", min_backoff = "1s", max_backoff = "10ms" }`,
+			err: "log: network backoff range '1s' to '10ms' is invalid"},
+		{label: "write timeout", cfg: `network { address = "127.0.0.1:1", write_timeout = "0s" }`,
+			err: "log: network write timeout '0s' must be greater than zero"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			cfg, _ := config.ParseString(`log {
+    receiver = "network"
+    ` + tc.cfg + `
+  }`)
+			logger, err := New(cfg)
+			assert.Nil(t, logger)
+			assert.Equal(t, tc.err, err.Error())
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ReceiverFactory func type creates the new log receiver instance, logger
+// initializes it with application config via method `Receiver.Init`.
+type ReceiverFactory func() Receiver
+
+// ErrReceiverFactoryIsNil returned when given receiver factory is nil.
+var ErrReceiverFactoryIsNil = errors.New("log: receiver factory is nil")
+
+var (
+	receiverMu = &sync.RWMutex{}
+
+	// built-in receivers, registered at package variable initialization so
+	// that default logger can use it
+	receiverFactories = map[string]ReceiverFactory{
+		"CONSOLE": func() Receiver { return &ConsoleReceiver{} },
+		"FILE":    func() Receiver { return &FileReceiver{} },
+		"NETWORK": func() Receiver { return &NetworkReceiver{} },
+	}
+)
+
+// RegisterReceiver method registers the custom log receiver type, so that
+// it becomes selectable via config `log.receiver`. Receiver type name is
+// case-insensitive. It returns error if type name is already registered.
+// Built-in receivers `console`, `file` and `network` are in the same registry.
+//
+// 	For e.g.:
+// 		err := log.RegisterReceiver("kafka", func() log.Receiver {
+// 			return &KafkaReceiver{}
+// 		})
+//
+// 		log {
+// 		  receiver = "kafka"
+// 		}
+func RegisterReceiver(typeName string, factory ReceiverFactory) error {
+	if factory == nil {
+		return ErrReceiverFactoryIsNil
+	}
+
+	name := strings.ToUpper(strings.TrimSpace(typeName))
+	if len(name) == 0 {
+		return errors.New("log: receiver type name is empty")
+	}
+
+	receiverMu.Lock()
+	defer receiverMu.Unlock()
+	if _, found := receiverFactories[name]; found || name == categoryReceiver {
+		return fmt.Errorf("log: receiver type '%s' is already registered", typeName)
+	}
+	receiverFactories[name] = factory
+	return nil
+}
+
+func getReceiverByName(name string) Receiver {
+	receiverMu.RLock()
+	factory, found := receiverFactories[strings.ToUpper(name)]
+	receiverMu.RUnlock()
+	if !found {
+		return nil
+	}
+	return factory()
+}
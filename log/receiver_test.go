@@ -0,0 +1,184 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/essentials"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogRegisterReceiver(t *testing.T) {
+	defer func() {
+		receiverMu.Lock()
+		delete(receiverFactories, "MEMORY")
+		receiverMu.Unlock()
+	}()
+
+	buf := &bytes.Buffer{}
+	err := RegisterReceiver("memory", func() Receiver { return &memoryReceiver{out: buf} })
+	assert.Nil(t, err)
+
+	cfg, _ := config.ParseString(`log {
+    receiver = "Memory"
+    pattern = "%level:-5 %message"
+    prefix = "[app]"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	logger.Info("custom receiver")
+	logger.Debugf("welcome %s", "aah")
+	assert.Equal(t, "[app] INFO  custom receiver \n[app] DEBUG welcome aah \n", buf.String())
+
+	// registration errors
+	assert.Equal(t, "log: receiver type 'Memory' is already registered",
+		RegisterReceiver("Memory", func() Receiver { return &memoryReceiver{} }).Error())
+	assert.Equal(t, "log: receiver type 'file' is already registered",
+		RegisterReceiver("file", func() Receiver { return &FileReceiver{} }).Error())
+	assert.Equal(t, "log: receiver type 'category' is already registered",
+		RegisterReceiver("category", func() Receiver { return &FileReceiver{} }).Error())
+	assert.Equal(t, ErrReceiverFactoryIsNil, RegisterReceiver("nil", nil))
+	assert.Equal(t, "log: receiver type name is empty",
+		RegisterReceiver(" ", func() Receiver { return &memoryReceiver{} }).Error())
+
+	// unknown receiver type
+	cfg, _ = config.ParseString(`log { receiver = "kafka" }`)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, ErrLogReceiverIsNil, err)
+
+	// concurrent registration, only one succeeds per name
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if RegisterReceiver("memory2", func() Receiver { return &memoryReceiver{} }) == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+			_ = getReceiverByName("memory2")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, succeeded)
+	receiverMu.Lock()
+	delete(receiverFactories, "MEMORY2")
+	receiverMu.Unlock()
+}
+
+type memoryReceiver struct {
+	mu     sync.Mutex
+	out    io.Writer
+	prefix string
+	flags  []ess.FmtFlagPart
+}
+
+func (m *memoryReceiver) Init(cfg *config.Config) error {
+	m.prefix = cfg.StringDefault("log.prefix", "")
+	return nil
+}
+
+func (m *memoryReceiver) SetPattern(pattern string) error {
+	flags, err := ess.ParseFmtFlag(pattern, FmtFlags)
+	if err != nil {
+		return err
+	}
+	m.flags = flags
+	return nil
+}
+
+func (m *memoryReceiver) SetWriter(w io.Writer) { m.out = w }
+
+func (m *memoryReceiver) IsCallerInfo() bool { return false }
+
+func (m *memoryReceiver) Writer() io.Writer { return m.out }
+
+func (m *memoryReceiver) Log(e *Entry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, _ = fmt.Fprintf(m.out, "%s %s", m.prefix, TextFormatter(m.flags, e))
+}
+
+func TestLogReceiverMinLevel(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    level = "debug"
+    pattern = "%level %message"
+    min_level = "warn"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	assert.True(t, logger.IsLevelDebug())
+	assert.True(t, logger.Enabled(LevelError))
+	assert.True(t, logger.Enabled(LevelWarn))
+	assert.False(t, logger.Enabled(LevelInfo))
+	assert.False(t, logger.Enabled(LevelDebug))
+
+	logger.Debug("debug message")
+	logger.Info("info message")
+	logger.WithField("key", "value").Info("entry info message")
+	logger.Warn("warn message")
+	assert.Equal(t, "WARN warn message \n", buf.String())
+
+	// receiver gates the entry written directly too
+	buf.Reset()
+	logger.receiver.Log(&Entry{Level: LevelInfo, Message: "direct info message"})
+	assert.Equal(t, "", buf.String())
+
+	// logger level is still honored
+	assert.Nil(t, logger.SetLevel("error"))
+	assert.False(t, logger.Enabled(LevelWarn))
+
+	// receiver without LevelEnabler
+	nl := &Logger{m: &sync.RWMutex{}, level: new(uint32), receiver: discardReceiver{}}
+	atomic.StoreUint32(nl.level, uint32(LevelInfo))
+	assert.True(t, nl.Enabled(LevelInfo))
+	assert.False(t, nl.Enabled(LevelDebug))
+
+	cfg, _ = config.ParseString(`log {
+    min_level = "verbose"
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: unknown log level 'verbose', valid values are FATAL, PANIC, ERROR, WARN, INFO, DEBUG, TRACE (or 0-6)", err.Error())
+}
+
+func TestLogEnabledConcurrentSetReceiver(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    min_level = "warn"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	logger.SetWriter(ioutil.Discard)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 50; i++ {
+			assert.Nil(t, logger.SetReceiver(&ConsoleReceiver{}))
+		}
+	}()
+	for i := 0; i < 500; i++ {
+		assert.False(t, logger.Enabled(LevelInfo))
+		assert.True(t, logger.Enabled(LevelError))
+	}
+	wg.Wait()
+}
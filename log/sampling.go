@@ -0,0 +1,101 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+)
+
+const defaultSamplingField = "reqid"
+
+// sampler keeps `DEBUG` and `TRACE` entries for the sampled fraction of
+// correlation field values, e.g. request ID.
+type sampler struct {
+	field     string
+	threshold uint64
+}
+
+// SetSampling method enables the log sampling keyed by given correlation
+// field, e.g. request ID. Field value is hashed and `DEBUG` and `TRACE`
+// entries are written only when the hash falls in the sampled fraction
+// `rate` (0 to 1), so that sampled requests get the complete trace instead
+// of disconnected lines. Entries without the field and levels `INFO` and
+// above are always written. Rate `1` disables the sampling.
+//
+// It can be configured via config too, default field is `reqid`.
+//
+// 	For e.g.: keep debug logs of 1% requests
+// 		log {
+// 		  sampling {
+// 		    field = "reqid"
+// 		    rate = 0.01
+// 		  }
+// 		}
+func (l *Logger) SetSampling(field string, rate float64) error {
+	if rate < 0 || rate > 1 || math.IsNaN(rate) {
+		return fmt.Errorf("log: sampling rate '%v' is out of range [0, 1]", rate)
+	}
+	if len(field) == 0 {
+		field = defaultSamplingField
+	}
+
+	var s *sampler
+	if rate < 1 {
+		s = &sampler{field: field, threshold: uint64(rate * (math.MaxUint32 + 1))}
+	}
+
+	l.m.Lock()
+	l.sampler = s
+	l.m.Unlock()
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+func (l *Logger) initSampling() error {
+	if !l.cfg.IsExists("log.sampling") {
+		return nil
+	}
+	v, _ := l.cfg.Get("log.sampling.rate")
+	var rate float64
+	switch n := v.(type) {
+	case float64:
+		rate = n
+	case int64:
+		rate = float64(n)
+	case int:
+		rate = float64(n)
+	default:
+		return fmt.Errorf("log: sampling rate is required and must be a number")
+	}
+	return l.SetSampling(l.cfg.StringDefault("log.sampling.field", defaultSamplingField), rate)
+}
+
+// isSampled method reports whether the entry with given fields is in the
+// sampled fraction, logger context is considered too.
+func (s *sampler) isSampled(fields, ctx Fields) bool {
+	v, found := fields[s.field]
+	if !found {
+		if v, found = ctx[s.field]; !found {
+			return true
+		}
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(fmt.Sprint(v)))
+	return uint64(h.Sum32()) < s.threshold
+}
+
+// isSampled method reports whether the `DEBUG` or `TRACE` entry to be written
+// as per logger sampling.
+func (e *Entry) isSampled() bool {
+	e.logger.m.RLock()
+	s := e.logger.sampler
+	e.logger.m.RUnlock()
+	return s == nil || s.isSampled(e.Fields, e.logger.ctx)
+}
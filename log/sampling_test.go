@@ -0,0 +1,126 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogSamplingByCorrelationField(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    level = "trace"
+    pattern = "%level %message"
+    sampling {
+      rate = 0.5
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	requests := 200
+	for i := 0; i < requests; i++ {
+		reqID := fmt.Sprintf("req-%d", i)
+		l := logger.WithField("reqid", reqID)
+		l.Debug(reqID, " debug")
+		l.Debugf("%s debugf", reqID)
+		l.Trace(reqID, " trace")
+		l.Tracef("%s tracef", reqID)
+		l.Info(reqID, " info")
+		l.Warn(reqID, " warn")
+		l.Error(reqID, " error")
+	}
+
+	// all-or-nothing per request ID
+	counts := make(map[string]int)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		counts[strings.Fields(line)[1]]++
+	}
+	assert.Equal(t, requests, len(counts))
+	sampled := 0
+	for reqID, count := range counts {
+		assert.True(t, count == 7 || count == 3, "request %s has %d lines", reqID, count)
+		if count == 7 {
+			sampled++
+		}
+	}
+	assert.True(t, sampled > requests/4 && sampled < requests*3/4, "sampled %d of %d", sampled, requests)
+
+	// same decision for logger context field
+	buf.Reset()
+	child := logger.New(Fields{"reqid": "req-1"})
+	child.Debug("req-1 context")
+	assert.Equal(t, counts["req-1"] == 7, strings.Contains(buf.String(), "req-1 context"))
+
+	// entries without correlation field are written
+	buf.Reset()
+	logger.Debug("no-reqid debug")
+	assert.Equal(t, "DEBUG no-reqid debug \n", buf.String())
+
+	// rate 0 keeps none, rate 1 disables sampling
+	assert.Nil(t, logger.SetSampling("tenant", 0))
+	buf.Reset()
+	logger.WithField("tenant", "t1").Debug("t1 debug")
+	logger.WithField("tenant", "t1").Error("t1 error")
+	assert.Equal(t, "ERROR t1 error \n", buf.String())
+
+	assert.Nil(t, logger.SetSampling("tenant", 1))
+	buf.Reset()
+	logger.WithField("tenant", "t1").Debug("t1 debug")
+	assert.Equal(t, "DEBUG t1 debug \n", buf.String())
+}
+
+func TestLogSamplingConcurrentSet(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    level = "debug"
+    pattern = "%level %message"
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	logger.SetWriter(&syncBuffer{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.WithField("reqid", fmt.Sprintf("req-%d-%d", i, j)).Debug("debug")
+			}
+		}(i)
+	}
+	for j := 0; j < 100; j++ {
+		assert.Nil(t, logger.SetSampling("reqid", float64(j%4)/4))
+	}
+	wg.Wait()
+}
+
+func TestLogSamplingConfigError(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    sampling {
+      field = "reqid"
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: sampling rate is required and must be a number", err.Error())
+
+	cfg, _ = config.ParseString(`log {
+    sampling {
+      rate = 2
+    }
+  }`)
+	logger, err = New(cfg)
+	assert.Nil(t, logger)
+	assert.Equal(t, "log: sampling rate '2' is out of range [0, 1]", err.Error())
+}
@@ -4,18 +4,105 @@
 
 package log
 
-// receiverStats tracks the number of output lines and bytes written.
+import "sync/atomic"
+
+// ReceiverStats is the point-in-time copy of log receiver counters, returned
+// by method `Logger.Stats`. Counters are read atomically one by one while
+// logging continues, so it's safe to read but counters are not captured at
+// the exact same instant.
+type ReceiverStats struct {
+	// Lines and Bytes written, file receiver resets them on rotation
+	Lines int64
+	Bytes int64
+
+	// Panics recovered in receiver background goroutine
+	Panics int64
+
+	// Dropped log entries by async buffer full policy
+	Dropped int64
+
+	// Truncated log messages by config `log.max_message_len`
+	Truncated int64
+}
+
+// receiverStats tracks the number of output lines and bytes written,
+// recovered panics of receiver background goroutine, dropped log entries and
+// truncated log messages.
+// Counters are updated atomically, so that it can be read without receiver
+// lock.
 type receiverStats struct {
-	lines int64
-	bytes int64
+	lines     int64
+	bytes     int64
+	panics    int64
+	dropped   int64
+	truncated int64
 }
 
 // Lines returns the number of lines written.
 func (s *receiverStats) Lines() int64 {
-	return s.lines
+	return atomic.LoadInt64(&s.lines)
 }
 
 // Bytes returns the number of bytes written.
 func (s *receiverStats) Bytes() int64 {
-	return s.bytes
+	return atomic.LoadInt64(&s.bytes)
+}
+
+// Panics returns the number of recovered panics in receiver background
+// goroutine.
+func (s *receiverStats) Panics() int64 {
+	return atomic.LoadInt64(&s.panics)
+}
+
+// Dropped returns the number of dropped log entries.
+func (s *receiverStats) Dropped() int64 {
+	return atomic.LoadInt64(&s.dropped)
+}
+
+// Truncated returns the number of log messages truncated by config
+// `log.max_message_len`.
+func (s *receiverStats) Truncated() int64 {
+	return atomic.LoadInt64(&s.truncated)
+}
+
+func (s *receiverStats) add(lines, bytes int64) {
+	atomic.AddInt64(&s.lines, lines)
+	atomic.AddInt64(&s.bytes, bytes)
+}
+
+func (s *receiverStats) set(lines, bytes int64) {
+	atomic.StoreInt64(&s.lines, lines)
+	atomic.StoreInt64(&s.bytes, bytes)
+}
+
+func (s *receiverStats) addPanic() {
+	atomic.AddInt64(&s.panics, 1)
+}
+
+func (s *receiverStats) addDropped() {
+	atomic.AddInt64(&s.dropped, 1)
+}
+
+func (s *receiverStats) addTruncated() {
+	atomic.AddInt64(&s.truncated, 1)
+}
+
+// snapshot method returns the point-in-time copy of counters.
+func (s *receiverStats) snapshot() ReceiverStats {
+	return ReceiverStats{
+		Lines:     s.Lines(),
+		Bytes:     s.Bytes(),
+		Panics:    s.Panics(),
+		Dropped:   s.Dropped(),
+		Truncated: s.Truncated(),
+	}
+}
+
+// add method accumulates the given stats into s.
+func (s *ReceiverStats) add(o ReceiverStats) {
+	s.Lines += o.Lines
+	s.Bytes += o.Bytes
+	s.Panics += o.Panics
+	s.Dropped += o.Dropped
+	s.Truncated += o.Truncated
 }
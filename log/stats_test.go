@@ -0,0 +1,74 @@
+// Copyright (c) Jeevanandam M (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package log
+
+import (
+	"io/ioutil"
+	"sync"
+	"testing"
+
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogStatsConcurrent(t *testing.T) {
+	cfg, _ := config.ParseString(`log {
+    pattern = "%level %message"
+    max_message_len = 8
+    async {
+      size = 16
+    }
+  }`)
+	logger, err := New(cfg)
+	assert.Nil(t, err)
+	logger.receiver.(*ConsoleReceiver).SetOutput(ioutil.Discard)
+
+	writers, perWriter := 8, 500
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			var last ReceiverStats
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				stats := logger.Stats()
+				assert.True(t, stats.Lines >= last.Lines)
+				last = stats
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWriter; j++ {
+				logger.Info("stats message over limit")
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Nil(t, logger.Close())
+	close(done)
+	readers.Wait()
+
+	stats := logger.Stats()
+	total := int64(writers * perWriter)
+	assert.Equal(t, total, stats.Lines)
+	assert.Equal(t, total, stats.Truncated)
+	assert.Equal(t, int64(0), stats.Dropped)
+	assert.True(t, stats.Bytes > 0)
+
+	// snapshot is a copy
+	stats.Lines = 0
+	assert.Equal(t, total, logger.Stats().Lines)
+}
@@ -5,15 +5,20 @@
 package log
 
 import (
+	"fmt"
+	"io"
+	"os"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"aahframe.work/essentials"
 )
 
 var (
-	levelNameToLevel = map[string]level{
+	levelNameToLevel = map[string]LogLevel{
 		"FATAL": LevelFatal,
 		"PANIC": LevelPanic,
 		"ERROR": LevelError,
@@ -23,7 +28,18 @@ var (
 		"TRACE": LevelTrace,
 	}
 
-	levelToLevelName = map[level]string{
+	// levelAliasToLevel holds the synonyms and abbreviations of level names,
+	// canonical names are used for output.
+	levelAliasToLevel = map[string]LogLevel{
+		"ERR":     LevelError,
+		"WARNING": LevelWarn,
+		"WRN":     LevelWarn,
+		"INF":     LevelInfo,
+		"DBG":     LevelDebug,
+		"TRC":     LevelTrace,
+	}
+
+	levelToLevelName = map[LogLevel]string{
 		LevelFatal: "FATAL",
 		LevelPanic: "PANIC",
 		LevelError: "ERROR",
@@ -39,18 +55,40 @@ var (
 //___________________________________
 
 // String level string interface.
-func (l level) String() string {
+func (l LogLevel) String() string {
 	return levelToLevelName[l]
 }
 
-func levelByName(name string) level {
-	if level, ok := levelNameToLevel[strings.ToUpper(name)]; ok {
+// levelByName method returns the level for given name, it accepts canonical
+// name, synonym/abbreviation (e.g. warning, wrn, err) and numeric level
+// (0=FATAL ... 6=TRACE) in case-insensitive. Otherwise `LevelUnknown`.
+func levelByName(name string) LogLevel {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if level, ok := levelNameToLevel[name]; ok {
+		return level
+	}
+
+	if level, ok := levelAliasToLevel[name]; ok {
 		return level
 	}
 
+	if n, err := strconv.Atoi(name); err == nil && n >= int(LevelFatal) && n < int(LevelUnknown) {
+		return LogLevel(n)
+	}
+
 	return LevelUnknown
 }
 
+// unknownLevelError method returns the error with valid level values.
+func unknownLevelError(name string) error {
+	names := make([]string, 0, len(levelToLevelName))
+	for l := LevelFatal; l < LevelUnknown; l++ {
+		names = append(names, levelToLevelName[l])
+	}
+	return fmt.Errorf("log: unknown log level '%s', valid values are %s (or %d-%d)",
+		name, strings.Join(names, ", "), int(LevelFatal), int(LevelUnknown-1))
+}
+
 func isFmtFlagExists(flags []ess.FmtFlagPart, flag ess.FmtFlag) bool {
 	for _, f := range flags {
 		if f.Flag == flag {
@@ -60,15 +98,15 @@ func isFmtFlagExists(flags []ess.FmtFlagPart, flag ess.FmtFlag) bool {
 	return false
 }
 
-func fetchCallerInfo() (string, int) {
+func fetchCallerInfo() (string, int, string) {
 	// dynamic call depth calculation; skip 3 known path and get
-	// maximum 5 which would cover log package.
-	pc := make([]uintptr, 5)
+	// maximum 10 which would cover log package.
+	pc := make([]uintptr, 10)
 	n := runtime.Callers(3, pc)
 	if n == 0 {
 		// No pcs available. Stop now.
 		// This can happen if the first argument to runtime.Callers is large.
-		return "???", 0
+		return "???", 0, "???"
 	}
 
 	pc = pc[:n] // pass only valid pcs to runtime.CallersFrames
@@ -80,30 +118,116 @@ func fetchCallerInfo() (string, int) {
 		frame, _ := frames.Next()
 
 		// Unwinding for aah log pkg otherwise stop.
-		if strings.Contains(frame.File, "aahframe.work/log") {
+		if isLogPkgFrame(frame) {
 			continue
 		}
 
-		return frame.File, frame.Line
+		return frame.File, frame.Line, shortFuncName(frame.Function)
 	}
 }
 
+// isLogPkgFrame method returns true if the frame belongs to aah log pkg,
+// test files of the pkg are considered as caller.
+func isLogPkgFrame(frame runtime.Frame) bool {
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+	return strings.Contains(frame.File, "aahframe.work/log") ||
+		strings.HasPrefix(frame.Function, "aahframe.work/log.")
+}
+
+// shortFuncName method returns the function name without package path,
+// e.g.: `aahframe.work/ahttp.(*Request).Locale` => `(*Request).Locale`.
+func shortFuncName(name string) string {
+	if idx := strings.LastIndexByte(name, '/'); idx >= 0 {
+		name = name[idx+1:]
+	}
+	if idx := strings.IndexByte(name, '.'); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return name
+}
+
 // isCallerInfo method to identify to fetch caller or not.
 func isCallerInfo(flags []ess.FmtFlagPart) bool {
 	return (isFmtFlagExists(flags, FmtFlagShortfile) ||
 		isFmtFlagExists(flags, FmtFlagLongfile) ||
-		isFmtFlagExists(flags, FmtFlagLine))
+		isFmtFlagExists(flags, FmtFlagLine) ||
+		isFmtFlagExists(flags, FmtFlagFunc))
 }
 
-func getReceiverByName(name string) Receiver {
-	switch name {
-	case "FILE":
-		return &FileReceiver{}
-	case "CONSOLE":
-		return &ConsoleReceiver{}
-	default:
-		return nil
+// setCallerInfo method stores the caller info flag of the receiver atomically,
+// since it's read on the logging path without receiver lock.
+func setCallerInfo(flag *uint32, flags []ess.FmtFlagPart) {
+	var v uint32
+	if isCallerInfo(flags) {
+		v = 1
 	}
+	atomic.StoreUint32(flag, v)
+}
+
+// closestFlagName method returns the closest known format flag name for given
+// name within edit distance of 2 otherwise empty string.
+func closestFlagName(name string) string {
+	var closest string
+	distance := 3
+	for fn := range FmtFlags {
+		if d := editDistance(strings.ToLower(name), fn); d < distance ||
+			(d == distance && fn < closest) {
+			closest, distance = fn, d
+		}
+	}
+	return closest
+}
+
+// editDistance method returns Levenshtein distance between given strings.
+func editDistance(s, t string) int {
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(minInt(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(t)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// isTerminal method returns true if given writer is a character device
+// e.g. terminal otherwise false.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+func resolveHostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return name
 }
 
 func formatTime(t time.Time) string {
@@ -112,3 +236,11 @@ func formatTime(t time.Time) string {
 	}
 	return t.Format(time.RFC3339)
 }
+
+// now method returns current time from clock, refer to `SetClock`.
+func now() time.Time {
+	if fn, ok := clock.Load().(func() time.Time); ok {
+		return fn()
+	}
+	return time.Now()
+}
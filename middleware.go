@@ -185,8 +185,8 @@ func ActionMiddleware(ctx *Context, m *Middleware) {
 	if !ctx.abort {
 		// Parse Action Parameters
 		actionArgs, err := ctx.parseParameters()
-		if err != nil { // Any error of parameter parsing result in 400 Bad Request
-			ctx.Reply().BadRequest().Error(err)
+		if err != nil { // Parameter parsing error result in 400 Bad Request or 413 if body is too large
+			ctx.Reply().Status(err.Code).Error(err)
 			return
 		}
 
@@ -162,7 +162,7 @@ func (a *Application) initRouter() error {
 //  - auto options
 //  - route not found
 //  - if route found then it sets targeted controller into context
-//  - adds the url path params into context if present
+//  - adds the url path params and route pattern into request if present
 //
 // Returns status as-
 //  - flowCont
@@ -187,6 +187,7 @@ func handleRoute(ctx *Context) flowResult {
 	}
 	ctx.route = route
 	ctx.Req.URLParams = urlParams
+	ctx.Req.SetRoutePattern(route.Path)
 
 	// Serving static file
 	if ctx.route.IsStatic {
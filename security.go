@@ -216,6 +216,7 @@ type principalProviderNoInit interface {
 
 func doAuthentication(authScheme scheme.Schemer, ctx *Context) flowResult {
 	var authcInfo *authc.AuthenticationInfo
+	var authcToken *authc.AuthenticationToken
 	if c, ok := authScheme.(principalProviderNoInit); ok {
 		// Call Subject principals provider
 		principals, err := c.Principal(authScheme.Key(), ctx)
@@ -230,9 +231,14 @@ func doAuthentication(authScheme scheme.Schemer, ctx *Context) flowResult {
 		authcInfo.Principals = append(authcInfo.Principals, principals...)
 	} else {
 		// Call Authentication Info provider
+		authcToken = authScheme.ExtractAuthenticationToken(ctx.Req)
 		var err error
-		authcInfo, err = authScheme.DoAuthenticate(authScheme.ExtractAuthenticationToken(ctx.Req))
+		authcInfo, err = authScheme.DoAuthenticate(authcToken)
 		if err != nil || authcInfo == nil {
+			if err == nil {
+				err = authc.ErrAuthenticationFailed
+			}
+			authc.NotifyFailure(authcToken, err)
 			switch sa := authScheme.(type) {
 			case *scheme.FormAuth:
 				ctx.Log().Infof("%s: Authentication is failed, sending to login failure URL", authScheme.Key())
@@ -251,6 +257,9 @@ func doAuthentication(authScheme scheme.Schemer, ctx *Context) flowResult {
 	ctx.Session().IsAuthenticated = true
 	ctx.Session().Set(keyAuthScheme, authScheme.Key())
 	ctx.Log().Infof("%s: Authentication successful", authScheme.Key())
+	if authcToken != nil {
+		authc.NotifySuccess(authcToken, authcInfo)
+	}
 
 	// Add to session its stateful
 	if ctx.a.SessionManager().IsStateful() {
@@ -42,6 +42,7 @@ type AuthenticationInfo struct {
 	IsLocked   bool
 	IsExpired  bool
 	Principals []*Principal
+	MFA        MFAStatus
 }
 
 // PrimaryPrincipal method returns the primary Principal instance if principal
@@ -70,22 +71,84 @@ func (a *AuthenticationInfo) Principal(claim string) *Principal {
 	return nil
 }
 
+// NeedsMFA method returns true if the subject is authenticated but second
+// factor verification is required and not yet verified, for e.g. step-up
+// authentication for sensitive operations.
+func (a *AuthenticationInfo) NeedsMFA() bool {
+	return a.MFA.Required && !a.MFA.Verified
+}
+
+// RequireMFA method marks the second factor verification as required, it
+// resets the existing verification so that step-up authentication can be
+// enforced for sensitive operations.
+func (a *AuthenticationInfo) RequireMFA() {
+	a.MFA.Required = true
+	a.MFA.Verified = false
+}
+
+// VerifyMFA method marks the second factor verification as succeeded with
+// given factor name, e.g. `totp`, `sms`, `webauthn`.
+func (a *AuthenticationInfo) VerifyMFA(factor string) {
+	a.MFA.Verified = true
+	a.MFA.addFactor(factor)
+}
+
 // Merge method merges the given authentication information into existing
-// `AuthenticationInfo` instance. IsExpired and IsLocked values considered as latest
-// from the given object.
+// `AuthenticationInfo` instance. IsExpired, IsLocked and MFA verified values
+// considered as latest from the given object. MFA is required if either one
+// requires it.
 func (a *AuthenticationInfo) Merge(oa *AuthenticationInfo) *AuthenticationInfo {
 	a.Principals = append(a.Principals, oa.Principals...)
 	a.IsExpired = oa.IsExpired
 	a.IsLocked = oa.IsLocked
+	a.MFA.Required = a.MFA.Required || oa.MFA.Required
+	a.MFA.Verified = oa.MFA.Verified
+	for _, f := range oa.MFA.Factors {
+		a.MFA.addFactor(f)
+	}
 	return a
 }
 
 // String method is stringer interface implementation.
 func (a AuthenticationInfo) String() string {
+	if a.MFA.Required || a.MFA.Verified {
+		return fmt.Sprintf("authenticationinfo(%s credential:******* islocked:%v isexpired:%v %s)",
+			a.Principals, a.IsLocked, a.IsExpired, a.MFA)
+	}
 	return fmt.Sprintf("authenticationinfo(%s credential:******* islocked:%v isexpired:%v)",
 		a.Principals, a.IsLocked, a.IsExpired)
 }
 
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// MFAStatus
+//___________________________________
+
+// MFAStatus struct holds the multi-factor authentication state of the
+// Subject. Authenticated subject with `Required` true and `Verified` false
+// is "authenticated but not yet MFA-verified".
+type MFAStatus struct {
+	Required bool
+	Verified bool
+	Factors  []string
+}
+
+// String method is stringer interface implementation.
+func (m MFAStatus) String() string {
+	return fmt.Sprintf("mfa(required:%v verified:%v factors:%s)", m.Required, m.Verified, m.Factors)
+}
+
+func (m *MFAStatus) addFactor(factor string) {
+	if len(factor) == 0 {
+		return
+	}
+	for _, f := range m.Factors {
+		if f == factor {
+			return
+		}
+	}
+	m.Factors = append(m.Factors, factor)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Principal
 //___________________________________
@@ -5,6 +5,7 @@
 package authc
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -48,3 +49,47 @@ func TestAuthcAuthenticationInfoMerge(t *testing.T) {
 	assert.True(t, a1.IsExpired)
 	assert.Nil(t, a1.PrimaryPrincipal())
 }
+
+func TestAuthcAuthenticationInfoMFA(t *testing.T) {
+	a := NewAuthenticationInfo()
+	a.Credential = []byte("$2y$10$2A4GsJ6SmLAMvDe8XmTam.MSkKojdobBVJfIU7GiyoM.lWt.XV3H6")
+	a.Principals = append(a.Principals, &Principal{Claim: "Username", Value: "user@sample.com", IsPrimary: true})
+
+	// single-step authentication
+	assert.False(t, a.NeedsMFA())
+
+	// authenticated but not yet MFA-verified
+	a.RequireMFA()
+	assert.True(t, a.NeedsMFA())
+	assert.Equal(t, "authenticationinfo([principal(realm: isprimary:true claim:Username value:user@sample.com)] "+
+		"credential:******* islocked:false isexpired:false mfa(required:true verified:false factors:[]))", a.String())
+
+	a.VerifyMFA("totp")
+	assert.False(t, a.NeedsMFA())
+	a.VerifyMFA("totp")
+	a.VerifyMFA("")
+	assert.Equal(t, []string{"totp"}, a.MFA.Factors)
+
+	// step-up resets verification, factors are kept
+	a.RequireMFA()
+	assert.True(t, a.NeedsMFA())
+	a.VerifyMFA("webauthn")
+	assert.False(t, a.NeedsMFA())
+	assert.Equal(t, "authenticationinfo([principal(realm: isprimary:true claim:Username value:user@sample.com)] "+
+		"credential:******* islocked:false isexpired:false mfa(required:true verified:true factors:[totp webauthn]))", a.String())
+	assert.False(t, strings.Contains(a.String(), string(a.Credential)))
+
+	// merge
+	a1 := NewAuthenticationInfo()
+	a2 := NewAuthenticationInfo()
+	a2.RequireMFA()
+	a1.Merge(a2)
+	assert.True(t, a1.NeedsMFA())
+
+	a3 := NewAuthenticationInfo()
+	a3.VerifyMFA("sms")
+	a1.Merge(a3)
+	assert.True(t, a1.MFA.Required)
+	assert.False(t, a1.NeedsMFA())
+	assert.Equal(t, []string{"sms"}, a1.MFA.Factors)
+}
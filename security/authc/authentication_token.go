@@ -4,7 +4,57 @@
 
 package authc
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Authentication scheme names used by the `AuthenticationToken` constructors.
+const (
+	SchemeForm   = "form"
+	SchemeBasic  = "basic"
+	SchemeBearer = "bearer"
+	SchemeAPIKey = "api"
+)
+
+var (
+	// ErrTokenIdentityEmpty returned when identity is empty while creating
+	// the `AuthenticationToken`.
+	ErrTokenIdentityEmpty = errors.New("security/authc: token identity is empty")
+
+	// ErrTokenCredentialEmpty returned when credential, bearer token or API
+	// key is empty while creating the `AuthenticationToken`.
+	ErrTokenCredentialEmpty = errors.New("security/authc: token credential is empty")
+
+	// ErrTokenCredentialInvalid returned when bearer token or API key contains
+	// whitespace.
+	ErrTokenCredentialInvalid = errors.New("security/authc: token credential is invalid")
+)
+
+// NewFormToken method creates the `AuthenticationToken` for form auth scheme
+// with given identity and credential.
+func NewFormToken(identity, credential string) (*AuthenticationToken, error) {
+	return newIdentityToken(SchemeForm, identity, credential)
+}
+
+// NewBasicToken method creates the `AuthenticationToken` for basic auth scheme
+// with given identity and credential.
+func NewBasicToken(identity, credential string) (*AuthenticationToken, error) {
+	return newIdentityToken(SchemeBasic, identity, credential)
+}
+
+// NewBearerToken method creates the `AuthenticationToken` for bearer auth
+// scheme, given token is set as credential.
+func NewBearerToken(token string) (*AuthenticationToken, error) {
+	return newSecretToken(SchemeBearer, token)
+}
+
+// NewAPIKeyToken method creates the `AuthenticationToken` for API key auth
+// scheme, given key is set as credential.
+func NewAPIKeyToken(key string) (*AuthenticationToken, error) {
+	return newSecretToken(SchemeAPIKey, key)
+}
 
 // AuthenticationToken is an account's principals and supporting credentials
 // submitted by a user during an authentication attempt.
@@ -31,3 +81,25 @@ type AuthenticationToken struct {
 func (a AuthenticationToken) String() string {
 	return fmt.Sprintf("authenticationtoken(scheme:%s identity:%s credential:*******)", a.Scheme, a.Identity)
 }
+
+func newIdentityToken(scheme, identity, credential string) (*AuthenticationToken, error) {
+	identity = strings.TrimSpace(identity)
+	if len(identity) == 0 {
+		return nil, ErrTokenIdentityEmpty
+	}
+	if len(credential) == 0 {
+		return nil, ErrTokenCredentialEmpty
+	}
+	return &AuthenticationToken{Scheme: scheme, Identity: identity, Credential: credential}, nil
+}
+
+func newSecretToken(scheme, secret string) (*AuthenticationToken, error) {
+	secret = strings.TrimSpace(secret)
+	if len(secret) == 0 {
+		return nil, ErrTokenCredentialEmpty
+	}
+	if strings.ContainsAny(secret, " \t\r\n") {
+		return nil, ErrTokenCredentialInvalid
+	}
+	return &AuthenticationToken{Scheme: scheme, Credential: secret}, nil
+}
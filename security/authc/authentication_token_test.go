@@ -5,6 +5,7 @@
 package authc
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,3 +20,43 @@ func TestAuthcAuthenticationToken(t *testing.T) {
 
 	assert.Equal(t, "authenticationtoken(scheme:form identity:jeeva credential:*******)", authToken.String())
 }
+
+func TestAuthcAuthenticationTokenConstructors(t *testing.T) {
+	testcases := []struct {
+		label    string
+		fn       func() (*AuthenticationToken, error)
+		scheme   string
+		identity string
+		str      string
+	}{
+		{"form", func() (*AuthenticationToken, error) { return NewFormToken(" jeeva ", "welcome123") },
+			"form", "jeeva", "authenticationtoken(scheme:form identity:jeeva credential:*******)"},
+		{"basic", func() (*AuthenticationToken, error) { return NewBasicToken("jeeva", "welcome123") },
+			"basic", "jeeva", "authenticationtoken(scheme:basic identity:jeeva credential:*******)"},
+		{"bearer", func() (*AuthenticationToken, error) { return NewBearerToken("eyJhbGciOiJIUzI1NiJ9.e30.sig") },
+			"bearer", "", "authenticationtoken(scheme:bearer identity: credential:*******)"},
+		{"api key", func() (*AuthenticationToken, error) { return NewAPIKeyToken(" 4f9a1c7e ") },
+			"api", "", "authenticationtoken(scheme:api identity: credential:*******)"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.label, func(t *testing.T) {
+			authToken, err := tc.fn()
+			assert.Nil(t, err)
+			assert.Equal(t, tc.scheme, authToken.Scheme)
+			assert.Equal(t, tc.identity, authToken.Identity)
+			assert.True(t, len(authToken.Credential) > 0)
+			assert.Equal(t, tc.str, authToken.String())
+			assert.False(t, strings.Contains(authToken.String(), authToken.Credential))
+		})
+	}
+
+	_, err := NewFormToken(" ", "welcome123")
+	assert.Equal(t, ErrTokenIdentityEmpty, err)
+	_, err = NewBasicToken("jeeva", "")
+	assert.Equal(t, ErrTokenCredentialEmpty, err)
+	_, err = NewBearerToken("  ")
+	assert.Equal(t, ErrTokenCredentialEmpty, err)
+	_, err = NewAPIKeyToken("4f9a 1c7e")
+	assert.Equal(t, ErrTokenCredentialInvalid, err)
+}
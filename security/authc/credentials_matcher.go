@@ -0,0 +1,65 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package authc
+
+import (
+	"crypto/subtle"
+
+	"aahframe.work/security/acrypto"
+)
+
+var (
+	// BcryptCredentialsMatcher verifies the token credential against the stored
+	// `bcrypt` password hash.
+	BcryptCredentialsMatcher CredentialsMatcher = NewCredentialsMatcher(&acrypto.BcryptEncoder{})
+
+	// PlainTextCredentialsMatcher verifies the token credential against the
+	// stored plain text credential in constant time.
+	PlainTextCredentialsMatcher CredentialsMatcher = plainTextCredentialsMatcher{}
+)
+
+// CredentialsMatcher interface is used to verify the credential submitted
+// via `AuthenticationToken` against the stored credential of
+// `AuthenticationInfo`. So that authenticators can delegate the credential
+// verification consistently.
+type CredentialsMatcher interface {
+	// Match method returns true if the token credential matches with stored
+	// credential otherwise false.
+	Match(authcToken *AuthenticationToken, authcInfo *AuthenticationInfo) bool
+}
+
+// NewCredentialsMatcher method returns the `CredentialsMatcher` for the given
+// password encoder. It can be used with `bcrypt`, `scrypt`, `pbkdf2` or your
+// own password encoder.
+//
+// 	For e.g.:
+// 		matcher := authc.NewCredentialsMatcher(acrypto.PasswordAlgorithm("pbkdf2"))
+func NewCredentialsMatcher(pe acrypto.PasswordEncoder) CredentialsMatcher {
+	return &encoderCredentialsMatcher{pe: pe}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//___________________________________
+
+type encoderCredentialsMatcher struct {
+	pe acrypto.PasswordEncoder
+}
+
+func (m *encoderCredentialsMatcher) Match(authcToken *AuthenticationToken, authcInfo *AuthenticationInfo) bool {
+	if m.pe == nil || authcToken == nil || authcInfo == nil {
+		return false
+	}
+	return m.pe.Compare(authcInfo.Credential, []byte(authcToken.Credential))
+}
+
+type plainTextCredentialsMatcher struct{}
+
+func (plainTextCredentialsMatcher) Match(authcToken *AuthenticationToken, authcInfo *AuthenticationInfo) bool {
+	if authcToken == nil || authcInfo == nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(authcToken.Credential), authcInfo.Credential) == 1
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package authc
+
+import (
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/security/acrypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthcBcryptCredentialsMatcher(t *testing.T) {
+	authcInfo := NewAuthenticationInfo()
+	authcInfo.Credential = []byte("$2y$10$2A4GsJ6SmLAMvDe8XmTam.MSkKojdobBVJfIU7GiyoM.lWt.XV3H6") // welcome123
+
+	assert.True(t, BcryptCredentialsMatcher.Match(&AuthenticationToken{Identity: "jeeva", Credential: "welcome123"}, authcInfo))
+	assert.False(t, BcryptCredentialsMatcher.Match(&AuthenticationToken{Identity: "jeeva", Credential: "welcome@123"}, authcInfo))
+	assert.False(t, BcryptCredentialsMatcher.Match(nil, authcInfo))
+	assert.False(t, BcryptCredentialsMatcher.Match(&AuthenticationToken{Credential: "welcome123"}, nil))
+	assert.False(t, NewCredentialsMatcher(nil).Match(&AuthenticationToken{Credential: "welcome123"}, authcInfo))
+}
+
+func TestAuthcPasswordEncoderCredentialsMatcher(t *testing.T) {
+	cfg, _ := config.ParseString(`
+		security {
+			password_encoder {
+				pbkdf2 {
+					enable = true
+				}
+			}
+		}
+	`)
+	err := acrypto.InitPasswordEncoders(cfg)
+	assert.Nil(t, err)
+
+	pe := acrypto.PasswordAlgorithm("pbkdf2")
+	hash, err := pe.Generate([]byte("welcome@123"))
+	assert.Nil(t, err)
+
+	matcher := NewCredentialsMatcher(pe)
+	authcInfo := &AuthenticationInfo{Credential: hash}
+	assert.True(t, matcher.Match(&AuthenticationToken{Credential: "welcome@123"}, authcInfo))
+	assert.False(t, matcher.Match(&AuthenticationToken{Credential: "welcome123"}, authcInfo))
+}
+
+func TestAuthcPlainTextCredentialsMatcher(t *testing.T) {
+	authcInfo := &AuthenticationInfo{Credential: []byte("welcome123")}
+
+	assert.True(t, PlainTextCredentialsMatcher.Match(&AuthenticationToken{Credential: "welcome123"}, authcInfo))
+	assert.False(t, PlainTextCredentialsMatcher.Match(&AuthenticationToken{Credential: "welcome12"}, authcInfo))
+	assert.False(t, PlainTextCredentialsMatcher.Match(&AuthenticationToken{Credential: ""}, authcInfo))
+	assert.False(t, PlainTextCredentialsMatcher.Match(nil, authcInfo))
+}
@@ -0,0 +1,72 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package authc
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrListenerIsNil error is returned when given authentication listener is nil.
+var ErrListenerIsNil = errors.New("security/authc: authentication listener is nil")
+
+var (
+	listenerMu = &sync.RWMutex{}
+	listeners  []AuthenticationListener
+)
+
+// AuthenticationListener interface is implemented by application to observe
+// the authentication outcome, for e.g. track failed attempts to lock the
+// account, metrics and alerting, without modifying the auth scheme.
+//
+// Listeners are called synchronously on the request goroutine in the
+// registration order, after the outcome is decided, so listener must not
+// block; hand off slow work (e.g. datastore update) to a goroutine.
+type AuthenticationListener interface {
+	// OnSuccess method is called after successful authentication.
+	OnSuccess(token *AuthenticationToken, info *AuthenticationInfo)
+
+	// OnFailure method is called after failed authentication with
+	// the reason, typically `ErrAuthenticationFailed`.
+	OnFailure(token *AuthenticationToken, err error)
+}
+
+// AddListener method registers the given authentication listener.
+//
+// 	For e.g.:
+// 		func init() {
+// 			_ = authc.AddListener(&LockoutListener{MaxAttempts: 5})
+// 		}
+func AddListener(l AuthenticationListener) error {
+	if l == nil {
+		return ErrListenerIsNil
+	}
+	listenerMu.Lock()
+	listeners = append(listeners, l)
+	listenerMu.Unlock()
+	return nil
+}
+
+// NotifySuccess method calls the registered listeners with successful
+// authentication outcome. It's called by the authentication path.
+func NotifySuccess(token *AuthenticationToken, info *AuthenticationInfo) {
+	for _, l := range registeredListeners() {
+		l.OnSuccess(token, info)
+	}
+}
+
+// NotifyFailure method calls the registered listeners with failed
+// authentication outcome. It's called by the authentication path.
+func NotifyFailure(token *AuthenticationToken, err error) {
+	for _, l := range registeredListeners() {
+		l.OnFailure(token, err)
+	}
+}
+
+func registeredListeners() []AuthenticationListener {
+	listenerMu.RLock()
+	defer listenerMu.RUnlock()
+	return listeners
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package authc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthcListener(t *testing.T) {
+	defer func() { listeners = nil }()
+
+	assert.Equal(t, ErrListenerIsNil, AddListener(nil))
+
+	var calls []string
+	first := &testListener{name: "first", calls: &calls}
+	second := &testListener{name: "second", calls: &calls}
+	assert.Nil(t, AddListener(first))
+	assert.Nil(t, AddListener(second))
+
+	token, _ := NewBasicToken("jeeva", "welcome123")
+	info := NewAuthenticationInfo()
+	NotifySuccess(token, info)
+	NotifyFailure(token, ErrAuthenticationFailed)
+
+	assert.Equal(t, []string{"first:success", "second:success", "first:failure", "second:failure"}, calls)
+	assert.Equal(t, token, first.token)
+	assert.Equal(t, info, first.info)
+	assert.Equal(t, ErrAuthenticationFailed, second.err)
+}
+
+type testListener struct {
+	name  string
+	calls *[]string
+	token *AuthenticationToken
+	info  *AuthenticationInfo
+	err   error
+}
+
+func (l *testListener) OnSuccess(token *AuthenticationToken, info *AuthenticationInfo) {
+	*l.calls = append(*l.calls, l.name+":success")
+	l.token, l.info = token, info
+}
+
+func (l *testListener) OnFailure(token *AuthenticationToken, err error) {
+	*l.calls = append(*l.calls, l.name+":failure")
+	l.token, l.err = token, err
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package security
+
+import (
+	"errors"
+	"strings"
+
+	"aahframe.work/ahttp"
+	"aahframe.work/security/authc"
+	"aahframe.work/security/authz"
+)
+
+// ErrUnauthenticated returned when request does not carry any credentials
+// on method `Authenticate`.
+var ErrUnauthenticated = errors.New("security: request is unauthenticated")
+
+// Authenticate method is batteries-included authentication path over the
+// security primitives. It extracts the credentials from the request in the
+// order of `Authorization: Basic`, `Authorization: Bearer` and form fields
+// `username` and `password` (POST only), builds the `AuthenticationToken`,
+// gets the account info from authenticator and verifies the credential using
+// given matcher. On success it returns the resolved Subject via authorizer.
+//
+// 	For e.g.:
+// 		subject, err := security.Authenticate(req, authenticator, authorizer,
+// 				authc.NewCredentialsMatcher(security.Bcrypt))
+//
+// It returns `ErrUnauthenticated` when no credentials are present and
+// `authc.ErrAuthenticationFailed` on credential mismatch, unknown, locked
+// or expired account. Registered `authc.AuthenticationListener` are notified
+// of the outcome once credentials are present. Advanced users can still
+// compose the auth flow manually.
+func Authenticate(req *ahttp.Request, authenticator authc.Authenticator, authorizer authz.Authorizer,
+	matcher authc.CredentialsMatcher) (*Subject, error) {
+	if authenticator == nil {
+		return nil, authc.ErrAuthenticatorIsNil
+	}
+
+	authcToken, err := requestAuthenticationToken(req)
+	if err != nil {
+		return nil, err
+	}
+
+	authcInfo, err := authenticator.GetAuthenticationInfo(authcToken)
+	if err != nil || authcInfo == nil || authcInfo.IsLocked || authcInfo.IsExpired ||
+		matcher == nil || !matcher.Match(authcToken, authcInfo) {
+		authc.NotifyFailure(authcToken, authc.ErrAuthenticationFailed)
+		return nil, authc.ErrAuthenticationFailed
+	}
+
+	authc.NotifySuccess(authcToken, authcInfo)
+	return NewSubject(authcInfo, authorizer), nil
+}
+
+func requestAuthenticationToken(req *ahttp.Request) (*authc.AuthenticationToken, error) {
+	if req == nil {
+		return nil, ErrUnauthenticated
+	}
+
+	if username, password, ok := req.Unwrap().BasicAuth(); ok {
+		return tokenOrFailed(authc.NewBasicToken(username, password))
+	}
+
+	if hdr := strings.TrimSpace(req.Header.Get(ahttp.HeaderAuthorization)); len(hdr) > 7 &&
+		strings.EqualFold(hdr[:7], "bearer ") {
+		return tokenOrFailed(authc.NewBearerToken(strings.TrimSpace(hdr[7:])))
+	}
+
+	if req.Method == ahttp.MethodPost {
+		if username := req.FormValue("username"); len(username) > 0 {
+			return tokenOrFailed(authc.NewFormToken(username, req.FormValue("password")))
+		}
+	}
+
+	return nil, ErrUnauthenticated
+}
+
+// tokenOrFailed maps the token validation errors, credentials are present
+// but not usable, to authentication failure.
+func tokenOrFailed(authcToken *authc.AuthenticationToken, err error) (*authc.AuthenticationToken, error) {
+	if err != nil {
+		return nil, authc.ErrAuthenticationFailed
+	}
+	return authcToken, nil
+}
@@ -4,7 +4,14 @@
 
 package authz
 
-import "strings"
+import (
+	"strings"
+	"sync"
+)
+
+// maxPermittedCacheSize is the maximum number of permission check results
+// cached per `AuthorizationInfo`, cache is cleared when it's reached.
+const maxPermittedCacheSize = 1024
 
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Package methods
@@ -17,6 +24,7 @@ func NewAuthorizationInfo() *AuthorizationInfo {
 	return &AuthorizationInfo{
 		roles:       make(parts, 0),
 		permissions: make([]*Permission, 0),
+		permitted:   &permittedCache{results: make(map[string]bool)},
 	}
 }
 
@@ -34,14 +42,21 @@ func NewAuthorizationInfo() *AuthorizationInfo {
 // These string methods do forego type-safety for the benefit of convenience and
 // simplicity, so you should choose which ones to use based on your preferences
 // and needs.
+//
+// Results of permission string checks are cached for the lifetime of the
+// instance (created via `NewAuthorizationInfo`), so that repeated checks on
+// the same Subject don't re-parse the permission strings. Adding roles or
+// permissions invalidates the cache.
 type AuthorizationInfo struct {
 	roles       parts
 	permissions []*Permission
+	permitted   *permittedCache
 }
 
 // AddRole method assigns a multiple-role to those associated with the account.
 func (a *AuthorizationInfo) AddRole(roles ...string) *AuthorizationInfo {
 	a.roles = append(a.roles, roles...)
+	a.permitted.clear()
 	return a
 }
 
@@ -49,6 +64,7 @@ func (a *AuthorizationInfo) AddRole(roles ...string) *AuthorizationInfo {
 // the account.
 func (a *AuthorizationInfo) AddPermission(permissions ...*Permission) *AuthorizationInfo {
 	a.permissions = append(a.permissions, permissions...)
+	a.permitted.clear()
 	return a
 }
 
@@ -89,22 +105,41 @@ func (a *AuthorizationInfo) HasAllRoles(roles ...string) bool {
 // to perform an action or access a resource summarized by the specified
 // permission string.
 func (a *AuthorizationInfo) IsPermitted(permission string) bool {
-	p, _ := NewPermission(permission)
-	return a.IsPermittedp(p)
+	return a.isPermittedString(permission)
 }
 
 // IsPermittedAll method returns true if the Subject implies
 // all of the specified permission strings, otherwise false.
 func (a *AuthorizationInfo) IsPermittedAll(permissions ...string) bool {
 	for _, permission := range permissions {
-		p, _ := NewPermission(permission)
-		if !a.IsPermittedp(p) {
+		if !a.isPermittedString(permission) {
 			return false
 		}
 	}
 	return true
 }
 
+// HasAllPermissions method returns true if the Subject implies all of the
+// specified permission strings, otherwise false. Permission string with
+// improper format is treated as not permitted.
+//
+// 	For e.g.:
+// 		authzInfo.HasAllPermissions("user:edit", "user:view")
+func (a *AuthorizationInfo) HasAllPermissions(permissions ...string) bool {
+	return a.IsPermittedAll(permissions...)
+}
+
+// HasAnyPermission method returns true if the Subject implies any-one of the
+// specified permission strings, otherwise false.
+func (a *AuthorizationInfo) HasAnyPermission(permissions ...string) bool {
+	for _, permission := range permissions {
+		if a.isPermittedString(permission) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsPermittedp method returns true if the Subject is permitted
 // to perform an action or access a resource summarized by the specified
 // permission string.
@@ -150,3 +185,66 @@ func (a *AuthorizationInfo) Permissions() string {
 func (a AuthorizationInfo) String() string {
 	return "authorizationinfo(roles(" + a.Roles() + ") allpermissions(" + a.Permissions() + "))"
 }
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//___________________________________
+
+// isPermittedString method evaluates the permission string and puts back
+// the parsed permission instance to pool. Result is cached per permission
+// string.
+func (a *AuthorizationInfo) isPermittedString(permission string) bool {
+	if result, found := a.permitted.get(permission); found {
+		return result
+	}
+
+	p, err := NewPermission(permission)
+	if err != nil {
+		return false
+	}
+	defer releasePermission(p)
+
+	result := a.IsPermittedp(p)
+	a.permitted.set(permission, result)
+	return result
+}
+
+// permittedCache holds the permission string check results, it's safe for
+// concurrent checks. Nil cache is no-op.
+type permittedCache struct {
+	mu      sync.RWMutex
+	results map[string]bool
+}
+
+func (c *permittedCache) get(permission string) (bool, bool) {
+	if c == nil {
+		return false, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result, found := c.results[permission]
+	return result, found
+}
+
+func (c *permittedCache) set(permission string, result bool) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.results) >= maxPermittedCacheSize {
+		c.results = make(map[string]bool)
+	}
+	c.results[permission] = result
+}
+
+func (c *permittedCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.results) > 0 {
+		c.results = make(map[string]bool)
+	}
+}
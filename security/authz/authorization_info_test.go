@@ -5,6 +5,7 @@
 package authz
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -45,3 +46,70 @@ func TestAuthAuthorizationPermissions(t *testing.T) {
 	assert.False(t, a2.IsPermitted("newsletter:write"))
 	assert.False(t, a2.IsPermittedAll("newsletter:read", "newsletter:write"))
 }
+
+func TestAuthAuthorizationMultiplePermissions(t *testing.T) {
+	a1 := NewAuthorizationInfo()
+	a1.AddPermissionString("user:edit,view:*", "newsletter:*:read", "printer")
+
+	assert.True(t, a1.HasAllPermissions("user:edit", "user:view"))
+	assert.True(t, a1.HasAllPermissions("user:edit:jeeva", "newsletter:123:read", "printer:print"))
+	assert.True(t, a1.HasAllPermissions())
+	assert.False(t, a1.HasAllPermissions("user:edit", "user:delete"))
+	assert.False(t, a1.HasAllPermissions("user:view", "newsletter:123:write"))
+	assert.False(t, a1.HasAllPermissions("user:view", ""))
+
+	assert.True(t, a1.HasAnyPermission("user:delete", "newsletter:456:read"))
+	assert.True(t, a1.HasAnyPermission("", "printer:scan:lp7200"))
+	assert.False(t, a1.HasAnyPermission("user:delete", "newsletter:456:write"))
+	assert.False(t, a1.HasAnyPermission())
+}
+
+func TestAuthAuthorizationPermittedCache(t *testing.T) {
+	a1 := NewAuthorizationInfo()
+	a1.AddPermissionString("user:view:*")
+	assert.True(t, a1.IsPermitted("user:view:jeeva"))
+	assert.False(t, a1.IsPermitted("user:edit:jeeva"))
+	assert.Equal(t, 2, len(a1.permitted.results))
+
+	// cached results are served until mutation
+	assert.True(t, a1.IsPermitted("user:view:jeeva"))
+	assert.False(t, a1.HasAnyPermission("user:edit:jeeva"))
+	assert.Equal(t, 2, len(a1.permitted.results))
+
+	a1.AddPermissionString("user:edit:*")
+	assert.Equal(t, 0, len(a1.permitted.results))
+	assert.True(t, a1.IsPermitted("user:edit:jeeva"))
+	assert.True(t, a1.IsPermittedAll("user:view:jeeva", "user:edit:jeeva"))
+
+	a1.AddRole("admin")
+	assert.Equal(t, 0, len(a1.permitted.results))
+
+	// cache is bounded
+	for i := 0; i <= maxPermittedCacheSize; i++ {
+		a1.IsPermitted(fmt.Sprintf("printer:%d", i))
+	}
+	assert.True(t, len(a1.permitted.results) <= maxPermittedCacheSize)
+
+	// zero value works without cache
+	a2 := &AuthorizationInfo{}
+	a2.AddPermissionString("user:view:*")
+	assert.True(t, a2.IsPermitted("user:view:jeeva"))
+	assert.False(t, a2.IsPermitted("user:edit:jeeva"))
+}
+
+func BenchmarkAuthorizationInfoIsPermittedCached(b *testing.B) {
+	benchmarkIsPermitted(b, NewAuthorizationInfo())
+}
+
+func BenchmarkAuthorizationInfoIsPermittedUncached(b *testing.B) {
+	benchmarkIsPermitted(b, &AuthorizationInfo{})
+}
+
+func benchmarkIsPermitted(b *testing.B, a *AuthorizationInfo) {
+	a.AddPermissionString("user:edit,view:*", "newsletter:*:read", "printer:print,scan:*")
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.HasAllPermissions("user:view:jeeva", "newsletter:123:read", "printer:scan:lp7200")
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package authz
+
+import (
+	"fmt"
+	"sync"
+
+	"aahframe.work/config"
+	"aahframe.work/security/authc"
+)
+
+const (
+	configAuthorizerKeyPrefix = "security.authorizer"
+	anySubject                = "*"
+)
+
+var _ Authorizer = (*ConfigAuthorizer)(nil)
+
+// ConfigAuthorizer struct implements `Authorizer` interface, it provides
+// roles and permissions of the Subject declared in the configuration
+// `security.authorizer { ... }`. It's meant for simple deployments where
+// database is overkill.
+//
+// 	For e.g.:
+// 		security {
+// 		  authorizer {
+// 		    roles {
+// 		      admin {
+// 		        permissions = ["*"]
+// 		      }
+// 		      editor {
+// 		        permissions = ["article:read,write", "newsletter:*:read"]
+// 		      }
+// 		    }
+//
+// 		    subjects {
+// 		      jeeva {
+// 		        # Subject primary principal value, default is key name
+// 		        principal = "jeeva@example.com"
+// 		        roles = ["admin"]
+// 		      }
+// 		      guest {
+// 		        # "*" applies to all authenticated subjects
+// 		        principal = "*"
+// 		        permissions = ["article:read"]
+// 		      }
+// 		    }
+// 		  }
+// 		}
+//
+// Permission strings supports wildcard, refer to `authz.Permission`.
+type ConfigAuthorizer struct {
+	mu       sync.RWMutex
+	roles    map[string][]string
+	subjects map[string]*configSubject
+}
+
+type configSubject struct {
+	roles       []string
+	permissions []string
+}
+
+// NewConfigAuthorizer method creates the `ConfigAuthorizer` instance and
+// loads the roles and permissions from given configuration.
+func NewConfigAuthorizer(cfg *config.Config) (*ConfigAuthorizer, error) {
+	a := &ConfigAuthorizer{}
+	if err := a.Init(cfg); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Init method (re)loads the roles and permissions from given configuration.
+// It returns error if permission string is invalid or subject refers to
+// undefined role.
+func (a *ConfigAuthorizer) Init(appCfg *config.Config) error {
+	if appCfg == nil {
+		return nil
+	}
+
+	roles := make(map[string][]string)
+	keyPrefix := configAuthorizerKeyPrefix + ".roles"
+	for _, name := range appCfg.KeysByPath(keyPrefix) {
+		permissions, _ := appCfg.StringList(keyPrefix + "." + name + ".permissions")
+		if err := validatePermissions("role", name, permissions); err != nil {
+			return err
+		}
+		roles[name] = permissions
+	}
+
+	subjects := make(map[string]*configSubject)
+	keyPrefix = configAuthorizerKeyPrefix + ".subjects"
+	for _, name := range appCfg.KeysByPath(keyPrefix) {
+		key := keyPrefix + "." + name
+		s := &configSubject{}
+		s.roles, _ = appCfg.StringList(key + ".roles")
+		for _, role := range s.roles {
+			if _, found := roles[role]; !found {
+				return fmt.Errorf("security/authz: subject '%s': role '%s' is not defined", name, role)
+			}
+		}
+		s.permissions, _ = appCfg.StringList(key + ".permissions")
+		if err := validatePermissions("subject", name, s.permissions); err != nil {
+			return err
+		}
+		subjects[appCfg.StringDefault(key+".principal", name)] = s
+	}
+
+	a.mu.Lock()
+	a.roles, a.subjects = roles, subjects
+	a.mu.Unlock()
+	return nil
+}
+
+// GetAuthorizationInfo method returns the roles and permissions of the Subject
+// by primary principal value lookup. Subject `*` is applied to all the
+// Subjects.
+func (a *ConfigAuthorizer) GetAuthorizationInfo(authcInfo *authc.AuthenticationInfo) *AuthorizationInfo {
+	authzInfo := NewAuthorizationInfo()
+	if authcInfo == nil {
+		return authzInfo
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	if p := authcInfo.PrimaryPrincipal(); p != nil {
+		a.addSubject(authzInfo, a.subjects[p.Value])
+	}
+	a.addSubject(authzInfo, a.subjects[anySubject])
+	return authzInfo
+}
+
+func (a *ConfigAuthorizer) addSubject(authzInfo *AuthorizationInfo, s *configSubject) {
+	if s == nil {
+		return
+	}
+	for _, role := range s.roles {
+		if !authzInfo.HasRole(role) {
+			authzInfo.AddRole(role)
+		}
+		authzInfo.AddPermissionString(a.roles[role]...)
+	}
+	authzInfo.AddPermissionString(s.permissions...)
+}
+
+func validatePermissions(kind, name string, permissions []string) error {
+	for _, ps := range permissions {
+		p, err := NewPermission(ps)
+		if err != nil {
+			return fmt.Errorf("security/authz: %s '%s' has invalid permission '%s'", kind, name, ps)
+		}
+		releasePermission(p)
+	}
+	return nil
+}
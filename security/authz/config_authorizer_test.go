@@ -0,0 +1,132 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package authz
+
+import (
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/security/authc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthzConfigAuthorizer(t *testing.T) {
+	cfg, err := config.ParseString(`
+security {
+  authorizer {
+    roles {
+      admin {
+        permissions = ["*"]
+      }
+      editor {
+        permissions = ["article:read,write", "newsletter:*:read"]
+      }
+      viewer {
+      }
+    }
+
+    subjects {
+      jeeva {
+        principal = "jeeva@example.com"
+        roles = ["admin"]
+      }
+      john {
+        roles = ["editor", "viewer"]
+        permissions = ["reports:view"]
+      }
+      everyone {
+        principal = "*"
+        roles = ["viewer"]
+        permissions = ["profile:read"]
+      }
+    }
+  }
+}`)
+	assert.Nil(t, err)
+
+	authorizer, err := NewConfigAuthorizer(cfg)
+	assert.Nil(t, err)
+
+	// wildcard permission via role
+	authzInfo := authorizer.GetAuthorizationInfo(testAuthcInfo("jeeva@example.com"))
+	assert.True(t, authzInfo.HasAllRoles("admin", "viewer"))
+	assert.True(t, authzInfo.IsPermitted("anything:goes:here"))
+
+	// principal defaults to key name
+	authzInfo = authorizer.GetAuthorizationInfo(testAuthcInfo("john"))
+	assert.True(t, authzInfo.HasAllRoles("editor", "viewer"))
+	assert.False(t, authzInfo.HasRole("admin"))
+	assert.True(t, authzInfo.IsPermittedAll("article:write", "newsletter:2018:read", "reports:view", "profile:read"))
+	assert.False(t, authzInfo.IsPermitted("newsletter:2018:write"))
+	assert.Equal(t, "authorizationinfo(roles(editor, viewer) allpermissions(permission(article:read,write)|"+
+		"permission(newsletter:*:read)|permission(reports:view)|permission(profile:read)))", authzInfo.String())
+
+	// unknown subject gets only "*" subject
+	authzInfo = authorizer.GetAuthorizationInfo(testAuthcInfo("unknown"))
+	assert.True(t, authzInfo.HasRole("viewer"))
+	assert.True(t, authzInfo.IsPermitted("profile:read"))
+	assert.False(t, authzInfo.IsPermitted("article:read"))
+
+	authzInfo = authorizer.GetAuthorizationInfo(nil)
+	assert.False(t, authzInfo.HasRole("viewer"))
+
+	// reload on init
+	cfg, _ = config.ParseString(`
+security {
+  authorizer {
+    roles {
+      admin {
+        permissions = ["article:*"]
+      }
+    }
+    subjects {
+      jeeva {
+        principal = "jeeva@example.com"
+        roles = ["admin"]
+      }
+    }
+  }
+}`)
+	assert.Nil(t, authorizer.Init(cfg))
+	authzInfo = authorizer.GetAuthorizationInfo(testAuthcInfo("jeeva@example.com"))
+	assert.True(t, authzInfo.IsPermitted("article:delete"))
+	assert.False(t, authzInfo.IsPermitted("newsletter:read"))
+	assert.False(t, authorizer.GetAuthorizationInfo(testAuthcInfo("john")).HasRole("editor"))
+}
+
+func TestAuthzConfigAuthorizerErrors(t *testing.T) {
+	testcases := []struct {
+		cfg      string
+		errorMsg string
+	}{
+		{
+			cfg:      `roles { admin { permissions = ["article::read"] } }`,
+			errorMsg: "security/authz: role 'admin' has invalid permission 'article::read'",
+		},
+		{
+			cfg:      `subjects { jeeva { roles = ["admin"] } }`,
+			errorMsg: "security/authz: subject 'jeeva': role 'admin' is not defined",
+		},
+		{
+			cfg:      `subjects { jeeva { permissions = [" "] } }`,
+			errorMsg: "security/authz: subject 'jeeva' has invalid permission ' '",
+		},
+	}
+
+	for _, tc := range testcases {
+		cfg, err := config.ParseString("security { authorizer { " + tc.cfg + " } }")
+		assert.Nil(t, err)
+		authorizer, err := NewConfigAuthorizer(cfg)
+		assert.Nil(t, authorizer)
+		assert.Equal(t, tc.errorMsg, err.Error())
+	}
+}
+
+func testAuthcInfo(principal string) *authc.AuthenticationInfo {
+	authcInfo := authc.NewAuthenticationInfo()
+	authcInfo.Principals = append(authcInfo.Principals,
+		&authc.Principal{Claim: "Username", Value: principal, IsPrimary: true})
+	return authcInfo
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package authz
+
+import (
+	"fmt"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"aahframe.work/security/authc"
+)
+
+var _ Authorizer = (*SafeAuthorizer)(nil)
+
+// SafeAuthorizer struct wraps the `Authorizer` to harden the authorization
+// path against third-party code. Panic from method `GetAuthorizationInfo`
+// of delegate authorizer is recovered and logged, and the Subject gets no
+// roles and permissions (deny-by-default) instead of crashing the request.
+//
+// 	For e.g.:
+// 		authorizer := authz.NewSafeAuthorizer(&MyAuthorizer{})
+type SafeAuthorizer struct {
+	delegate Authorizer
+	logger   log.Loggerer
+}
+
+// NewSafeAuthorizer method creates the `SafeAuthorizer` instance for given
+// delegate authorizer. Recovered panics are logged via default logger,
+// refer to `SafeAuthorizer.SetLogger`.
+func NewSafeAuthorizer(delegate Authorizer) *SafeAuthorizer {
+	return &SafeAuthorizer{delegate: delegate}
+}
+
+// SetLogger method sets the logger used to log the recovered panics, nil
+// means default logger.
+func (a *SafeAuthorizer) SetLogger(logger log.Loggerer) *SafeAuthorizer {
+	a.logger = logger
+	return a
+}
+
+// Init method calls the delegate authorizer `Init` method.
+func (a *SafeAuthorizer) Init(appCfg *config.Config) error {
+	if a.delegate == nil {
+		return ErrAuthorizerIsNil
+	}
+	return a.delegate.Init(appCfg)
+}
+
+// GetAuthorizationInfo method returns the roles and permissions of the
+// Subject from delegate authorizer. It returns empty authorization info if
+// delegate authorizer panics or returns nil.
+func (a *SafeAuthorizer) GetAuthorizationInfo(authcInfo *authc.AuthenticationInfo) (authzInfo *AuthorizationInfo) {
+	defer func() {
+		if authzInfo == nil {
+			authzInfo = NewAuthorizationInfo()
+		}
+	}()
+	defer log.RecoverAndLog(a.logger, a.logFields(authcInfo), false)
+
+	if a.delegate == nil {
+		return nil
+	}
+	return a.delegate.GetAuthorizationInfo(authcInfo)
+}
+
+func (a *SafeAuthorizer) logFields(authcInfo *authc.AuthenticationInfo) log.Fields {
+	fields := log.Fields{"authorizer": fmt.Sprintf("%T", a.delegate)}
+	if authcInfo != nil {
+		if p := authcInfo.PrimaryPrincipal(); p != nil {
+			fields["principal"] = p.Value
+		}
+	}
+	return fields
+}
@@ -0,0 +1,66 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package authz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"aahframe.work/security/authc"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthzSafeAuthorizer(t *testing.T) {
+	logCfg, _ := config.ParseString(`log { pattern = "%level %principal %message %fields" }`)
+	logger, err := log.New(logCfg)
+	assert.Nil(t, err)
+	buf := &bytes.Buffer{}
+	logger.SetWriter(buf)
+
+	// panicking authorizer is denied by default
+	authorizer := NewSafeAuthorizer(&testAuthorizer{panicOn: "jeeva"}).SetLogger(logger)
+	assert.Nil(t, authorizer.Init(nil))
+	authzInfo := authorizer.GetAuthorizationInfo(testAuthcInfo("jeeva"))
+	assert.NotNil(t, authzInfo)
+	assert.False(t, authzInfo.HasRole("admin"))
+	assert.False(t, authzInfo.IsPermitted("article:read"))
+	assert.True(t, strings.HasPrefix(buf.String(), "ERROR jeeva panic recovered: authorizer failure"))
+	assert.True(t, strings.Contains(buf.String(), "authorizer: *authz.testAuthorizer"))
+
+	// others are delegated
+	buf.Reset()
+	authzInfo = authorizer.GetAuthorizationInfo(testAuthcInfo("john"))
+	assert.True(t, authzInfo.HasRole("admin"))
+	assert.True(t, authzInfo.IsPermitted("article:read"))
+	assert.Equal(t, "", buf.String())
+
+	// nil authorization info and nil delegate
+	authzInfo = authorizer.GetAuthorizationInfo(nil)
+	assert.NotNil(t, authzInfo)
+	assert.False(t, authzInfo.HasRole("admin"))
+
+	authorizer = NewSafeAuthorizer(nil)
+	assert.Equal(t, ErrAuthorizerIsNil, authorizer.Init(nil))
+	assert.NotNil(t, authorizer.GetAuthorizationInfo(testAuthcInfo("john")))
+}
+
+type testAuthorizer struct {
+	panicOn string
+}
+
+func (a *testAuthorizer) Init(_ *config.Config) error { return nil }
+
+func (a *testAuthorizer) GetAuthorizationInfo(authcInfo *authc.AuthenticationInfo) *AuthorizationInfo {
+	if authcInfo == nil {
+		return nil
+	}
+	if authcInfo.PrimaryPrincipal().Value == a.panicOn {
+		panic("authorizer failure")
+	}
+	return NewAuthorizationInfo().AddRole("admin").AddPermissionString("article:read")
+}
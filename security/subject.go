@@ -38,6 +38,21 @@ type Subject struct {
 	Session            *session.Session
 }
 
+// NewSubject method creates the Subject for given authentication info and
+// its authorization info resolved via given authorizer. Subject is not
+// pooled, it's safe to cache per request. If authorizer is nil, Subject
+// gets empty authorization info.
+func NewSubject(authcInfo *authc.AuthenticationInfo, authorizer authz.Authorizer) *Subject {
+	s := &Subject{AuthenticationInfo: authcInfo}
+	if authorizer != nil && authcInfo != nil {
+		s.AuthorizationInfo = authorizer.GetAuthorizationInfo(authcInfo)
+	}
+	if s.AuthorizationInfo == nil {
+		s.AuthorizationInfo = authz.NewAuthorizationInfo()
+	}
+	return s
+}
+
 // PrimaryPrincipal method is convenience wrapper. See `AuthenticationInfo.PrimaryPrincipal`.
 func (s *Subject) PrimaryPrincipal() *authc.Principal {
 	return s.AuthenticationInfo.PrimaryPrincipal()
@@ -62,6 +77,11 @@ func (s *Subject) IsAuthenticated() bool {
 	return s.Session.IsAuthenticated
 }
 
+// NeedsMFA method is convenience wrapper. See `AuthenticationInfo.NeedsMFA`.
+func (s *Subject) NeedsMFA() bool {
+	return s.AuthenticationInfo.NeedsMFA()
+}
+
 // Logout method is convenience wrapper. See `Session.Clear`.
 func (s *Subject) Logout() {
 	if s.Session != nil {
@@ -98,6 +118,16 @@ func (s *Subject) IsPermittedAll(permissions ...string) bool {
 	return s.AuthorizationInfo.IsPermittedAll(permissions...)
 }
 
+// HasAllPermissions method is convenience wrapper. See `AuthorizationInfo.HasAllPermissions`.
+func (s *Subject) HasAllPermissions(permissions ...string) bool {
+	return s.AuthorizationInfo.HasAllPermissions(permissions...)
+}
+
+// HasAnyPermission method is convenience wrapper. See `AuthorizationInfo.HasAnyPermission`.
+func (s *Subject) HasAnyPermission(permissions ...string) bool {
+	return s.AuthorizationInfo.HasAnyPermission(permissions...)
+}
+
 // Reset method clear the instance for reuse.
 func (s *Subject) Reset() {
 	s.AuthenticationInfo = nil
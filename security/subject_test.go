@@ -75,3 +75,43 @@ func TestSecuritySubject(t *testing.T) {
 
 	ReleaseSubject(sub)
 }
+
+func TestSecurityNewSubject(t *testing.T) {
+	authcInfo := authc.NewAuthenticationInfo()
+	authcInfo.Principals = append(authcInfo.Principals, &authc.Principal{Claim: "Email", Value: "user@sample.com", IsPrimary: true})
+
+	sub := NewSubject(authcInfo, &testAuthorizer{})
+	assert.Equal(t, "user@sample.com", sub.PrimaryPrincipal().Value)
+	assert.Equal(t, "user@sample.com", sub.Principal("Email").Value)
+	assert.False(t, sub.IsAuthenticated())
+	assert.False(t, sub.NeedsMFA())
+	authcInfo.RequireMFA()
+	assert.True(t, sub.NeedsMFA())
+
+	assert.True(t, sub.HasRole("editor"))
+	assert.True(t, sub.HasAnyRole("admin", "editor"))
+	assert.False(t, sub.HasAllRoles("admin", "editor"))
+	assert.True(t, sub.IsPermitted("article:edit:1001"))
+	assert.True(t, sub.HasAllPermissions("article:edit", "article:view:1001"))
+	assert.False(t, sub.HasAllPermissions("article:edit", "article:delete"))
+	assert.True(t, sub.HasAnyPermission("article:delete", "article:view"))
+	assert.False(t, sub.HasAnyPermission("article:delete", "user:view"))
+
+	// without authorizer
+	sub = NewSubject(authcInfo, nil)
+	assert.NotNil(t, sub.AuthorizationInfo)
+	assert.False(t, sub.HasRole("editor"))
+	assert.False(t, sub.HasAnyPermission("article:view"))
+}
+
+type testAuthorizer struct{}
+
+func (a *testAuthorizer) Init(appCfg *config.Config) error {
+	return nil
+}
+
+func (a *testAuthorizer) GetAuthorizationInfo(authcInfo *authc.AuthenticationInfo) *authz.AuthorizationInfo {
+	return authz.NewAuthorizationInfo().
+		AddRole("editor").
+		AddPermissionString("article:edit,view:*")
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"strings"
 
+	"aahframe.work/ahttp"
 	"gopkg.in/go-playground/validator.v9"
 )
 
@@ -31,6 +32,9 @@ func Validator() *validator.Validate {
 		aahValidator = validator.New()
 
 		// Do customizations here
+
+		// same validator for `ahttp.Request.BindAndValidate`
+		ahttp.SetValidator(aahValidator)
 	}
 	return aahValidator
 }
@@ -96,7 +96,9 @@ func ValueParser(typ reflect.Type) (Parser, bool) {
 	return nil, false
 }
 
-// Body method parse the body based on Content-Type.
+// Body method parse the body based on Content-Type. On error, it returns
+// the zero value of given type, so that partially decoded value is not
+// used. Body limit error `ahttp.ErrBodyTooLarge` is returned as-is.
 func Body(contentType string, body io.Reader, typ reflect.Type) (reflect.Value, error) {
 	var err error
 	s := reflect.New(typ)
@@ -104,12 +106,12 @@ func Body(contentType string, body io.Reader, typ reflect.Type) (reflect.Value,
 	case ahttp.ContentTypeJSON.Mime, ahttp.ContentTypeJSONText.Mime:
 		if err = json.NewDecoder(body).Decode(s.Interface()); err != nil {
 			log.Errorf("json: %s", err)
-			return s.Elem(), err
+			return reflect.Zero(typ), err
 		}
 	case ahttp.ContentTypeXML.Mime, ahttp.ContentTypeXMLText.Mime:
 		if err = xml.NewDecoder(body).Decode(s.Interface()); err != nil {
 			log.Error(err)
-			return s.Elem(), err
+			return reflect.Zero(typ), err
 		}
 	}
 
@@ -8,11 +8,13 @@ import (
 	"bytes"
 	"encoding/xml"
 	"fmt"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"testing"
 	"time"
 
+	"aahframe.work/ahttp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -96,6 +98,20 @@ func TestParserBodyJSON(t *testing.T) {
 	assert.Equal(t, "invalid character '}' looking for beginning of object key string", err.Error())
 }
 
+func TestParserBodyJSONTooLarge(t *testing.T) {
+	jsonBytes := []byte(`{"first_name":"My firstname","last_name":"My lastname","email":"email@myemail.com"}`)
+	req := httptest.NewRequest(ahttp.MethodPost, "http://localhost:8080/users", bytes.NewReader(jsonBytes))
+	aahReq := ahttp.AcquireRequest(req)
+	defer ahttp.ReleaseRequest(aahReq)
+	aahReq.LimitBody(nil, int64(len(jsonBytes)-1))
+
+	val, err := Body("application/json", aahReq.Body(), reflect.TypeOf(sampleInfo{}))
+	assert.Equal(t, ahttp.ErrBodyTooLarge, err)
+
+	// no partially decoded value
+	assert.Equal(t, sampleInfo{}, val.Interface().(sampleInfo))
+}
+
 func TestParserBodyXML(t *testing.T) {
 	xmlBytes := []byte(`<Submit>
 	<FirstName>My xml firstname</FirstName>